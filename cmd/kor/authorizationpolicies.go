@@ -0,0 +1,33 @@
+package kor
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yonahd/kor/pkg/kor"
+	"github.com/yonahd/kor/pkg/utils"
+)
+
+var authorizationPolicyCmd = &cobra.Command{
+	Use:     "authorizationpolicy",
+	Aliases: []string{"ap", "authorizationpolicies"},
+	Short:   "Gets unused authorizationpolicies",
+	Args:    cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		clientset := kor.GetKubeClient(kubeconfig)
+		dynamicClient := kor.GetDynamicClient(kubeconfig)
+
+		if response, err := kor.GetUnusedAuthorizationPolicies(filterOptions, clientset, dynamicClient, outputFormat, opts); err != nil {
+			fmt.Println(err)
+		} else {
+			utils.PrintLogo(outputFormat, opts.ClusterName)
+			fmt.Println(response)
+		}
+	},
+}
+
+func init() {
+	authorizationPolicyCmd.Flags().StringVar(&opts.IstioRootNamespace, "istio-root-namespace", "", "Istio root namespace, whose AuthorizationPolicies apply to workloads in every namespace (defaults to the rootNamespace of the istio ConfigMap in istio-system)")
+	rootCmd.AddCommand(authorizationPolicyCmd)
+}
@@ -0,0 +1,32 @@
+package kor
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yonahd/kor/pkg/kor"
+	"github.com/yonahd/kor/pkg/utils"
+)
+
+var certificateSigningRequestCmd = &cobra.Command{
+	Use:     "certificatesigningrequest",
+	Aliases: []string{"csr", "certificatesigningrequests"},
+	Short:   "Gets denied, failed and issued certificateSigningRequests",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		clientset := kor.GetKubeClient(kubeconfig)
+
+		if response, err := kor.GetUnusedCertificateSigningRequests(filterOptions, clientset, outputFormat, opts); err != nil {
+			fmt.Println(err)
+		} else {
+			utils.PrintLogo(outputFormat, opts.ClusterName)
+			fmt.Println(response)
+		}
+	},
+}
+
+func init() {
+	certificateSigningRequestCmd.Flags().DurationVar(&opts.CsrThreshold, "threshold", opts.CsrThreshold, "Minimum time since a request was denied, failed or issued for it to be reported")
+	rootCmd.AddCommand(certificateSigningRequestCmd)
+}
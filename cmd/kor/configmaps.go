@@ -16,8 +16,9 @@ var configmapCmd = &cobra.Command{
 	Args:    cobra.ExactArgs(0),
 	Run: func(cmd *cobra.Command, args []string) {
 		clientset := kor.GetKubeClient(kubeconfig)
+		dynamicClient := kor.GetDynamicClient(kubeconfig)
 
-		if response, err := kor.GetUnusedConfigmaps(filterOptions, clientset, outputFormat, opts); err != nil {
+		if response, err := kor.GetUnusedConfigmaps(filterOptions, clientset, dynamicClient, outputFormat, opts); err != nil {
 			fmt.Println(err)
 		} else {
 			utils.PrintLogo(outputFormat, opts.ClusterName)
@@ -27,5 +28,6 @@ var configmapCmd = &cobra.Command{
 }
 
 func init() {
+	configmapCmd.Flags().BoolVar(&opts.UnusedKeys, "unused-keys", false, "Also report keys of used ConfigMaps that no consumer references, for objects only consumed key by key")
 	rootCmd.AddCommand(configmapCmd)
 }
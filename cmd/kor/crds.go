@@ -15,10 +15,11 @@ var crdCmd = &cobra.Command{
 	Short:   "Gets unused crds",
 	Args:    cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		clientset := kor.GetKubeClient(kubeconfig)
 		apiExtClient := kor.GetAPIExtensionsClient(kubeconfig)
 		dynamicClient := kor.GetDynamicClient(kubeconfig)
 
-		if response, err := kor.GetUnusedCrds(filterOptions, apiExtClient, dynamicClient, outputFormat, opts); err != nil {
+		if response, err := kor.GetUnusedCrds(filterOptions, clientset, apiExtClient, dynamicClient, outputFormat, opts); err != nil {
 			fmt.Println(err)
 		} else {
 			utils.PrintLogo(outputFormat, opts.ClusterName)
@@ -29,5 +30,6 @@ var crdCmd = &cobra.Command{
 }
 
 func init() {
+	crdCmd.Flags().BoolVar(&opts.CrdInstances, "instances", false, "Also report orphaned custom resource instances: dangling owner references, stale status.observedGeneration or a stopped controller Deployment (set with the kor/controller annotation)")
 	rootCmd.AddCommand(crdCmd)
 }
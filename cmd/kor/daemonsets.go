@@ -27,5 +27,6 @@ var dsCmd = &cobra.Command{
 }
 
 func init() {
+	dsCmd.Flags().DurationVar(&opts.UnavailableThreshold, "unavailable-threshold", opts.UnavailableThreshold, "Minimum time a DaemonSet must have no available pods to be reported")
 	rootCmd.AddCommand(dsCmd)
 }
@@ -27,5 +27,6 @@ var deployCmd = &cobra.Command{
 }
 
 func init() {
+	deployCmd.Flags().DurationVar(&opts.UnavailableThreshold, "unavailable-threshold", opts.UnavailableThreshold, "Minimum time a Deployment must have no available replicas to be reported")
 	rootCmd.AddCommand(deployCmd)
 }
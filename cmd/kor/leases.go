@@ -0,0 +1,32 @@
+package kor
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yonahd/kor/pkg/kor"
+	"github.com/yonahd/kor/pkg/utils"
+)
+
+var leaseCmd = &cobra.Command{
+	Use:     "lease",
+	Aliases: []string{"leases"},
+	Short:   "Gets leases whose holder stopped renewing them",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		clientset := kor.GetKubeClient(kubeconfig)
+
+		if response, err := kor.GetUnusedLeases(filterOptions, clientset, outputFormat, opts); err != nil {
+			fmt.Println(err)
+		} else {
+			utils.PrintLogo(outputFormat, opts.ClusterName)
+			fmt.Println(response)
+		}
+	},
+}
+
+func init() {
+	leaseCmd.Flags().DurationVar(&opts.LeaseRenewThreshold, "renew-threshold", opts.LeaseRenewThreshold, "Minimum time since the last renewal for a lease to be reported")
+	rootCmd.AddCommand(leaseCmd)
+}
@@ -0,0 +1,33 @@
+package kor
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yonahd/kor/pkg/kor"
+	"github.com/yonahd/kor/pkg/utils"
+)
+
+var nodeCmd = &cobra.Command{
+	Use:     "node",
+	Aliases: []string{"no", "nodes"},
+	Short:   "Gets NotReady, long-cordoned and workload-free nodes",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		clientset := kor.GetKubeClient(kubeconfig)
+
+		if response, err := kor.GetUnusedNodes(filterOptions, clientset, outputFormat, opts); err != nil {
+			fmt.Println(err)
+		} else {
+			utils.PrintLogo(outputFormat, opts.ClusterName)
+			fmt.Println(response)
+		}
+	},
+}
+
+func init() {
+	nodeCmd.Flags().DurationVar(&opts.NodeNotReadyThreshold, "not-ready-threshold", opts.NodeNotReadyThreshold, "Minimum time a node must be NotReady to be reported")
+	nodeCmd.Flags().IntVar(&opts.NodeCordonedDays, "cordoned-days", opts.NodeCordonedDays, "Minimum number of days a node must be cordoned to be reported")
+	rootCmd.AddCommand(nodeCmd)
+}
@@ -0,0 +1,33 @@
+package kor
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yonahd/kor/pkg/kor"
+	"github.com/yonahd/kor/pkg/utils"
+)
+
+var peerAuthenticationCmd = &cobra.Command{
+	Use:     "peerauthentication",
+	Aliases: []string{"pa", "peerauthentications"},
+	Short:   "Gets unused peerauthentications",
+	Args:    cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		clientset := kor.GetKubeClient(kubeconfig)
+		dynamicClient := kor.GetDynamicClient(kubeconfig)
+
+		if response, err := kor.GetUnusedPeerAuthentications(filterOptions, clientset, dynamicClient, outputFormat, opts); err != nil {
+			fmt.Println(err)
+		} else {
+			utils.PrintLogo(outputFormat, opts.ClusterName)
+			fmt.Println(response)
+		}
+	},
+}
+
+func init() {
+	peerAuthenticationCmd.Flags().StringVar(&opts.IstioRootNamespace, "istio-root-namespace", "", "Istio root namespace, whose PeerAuthentications apply to workloads in every namespace (defaults to the rootNamespace of the istio ConfigMap in istio-system)")
+	rootCmd.AddCommand(peerAuthenticationCmd)
+}
@@ -27,5 +27,8 @@ var podCmd = &cobra.Command{
 }
 
 func init() {
+	podCmd.Flags().DurationVar(&opts.PodFinishedThreshold, "finished-threshold", opts.PodFinishedThreshold, "Minimum time since a pod completed or failed for it to be reported")
+	podCmd.Flags().DurationVar(&opts.PodPendingThreshold, "pending-threshold", opts.PodPendingThreshold, "Minimum time a pod must be Pending to be reported")
+	podCmd.Flags().DurationVar(&opts.PodWaitingThreshold, "waiting-threshold", opts.PodWaitingThreshold, "Minimum time a pod must be not ready with a container in CrashLoopBackOff, ImagePullBackOff or CreateContainerConfigError to be reported")
 	rootCmd.AddCommand(podCmd)
 }
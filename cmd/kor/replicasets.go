@@ -27,5 +27,6 @@ var replicaSetCmd = &cobra.Command{
 }
 
 func init() {
+	replicaSetCmd.Flags().IntVar(&opts.RollbackHistory, "rollback-history", opts.RollbackHistory, "Number of old ReplicaSets per Deployment kept for rollbacks and not reported, overriding the Deployment's revisionHistoryLimit (0 reports all of them)")
 	rootCmd.AddCommand(replicaSetCmd)
 }
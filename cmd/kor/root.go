@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -40,7 +41,10 @@ var rootCmd = &cobra.Command{
 		}
 
 		initKindsList()
-		return nil
+		if err := initAuditLog(); err != nil {
+			return err
+		}
+		return initReferenceRules()
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		resourceNames := args[0]
@@ -59,10 +63,12 @@ var rootCmd = &cobra.Command{
 }
 
 var (
-	outputFormat  string
-	kubeconfig    string
-	opts          common.Opts
-	filterOptions = &filters.Options{}
+	outputFormat       string
+	kubeconfig         string
+	referenceRulesFile string
+	auditLogPath       string
+	opts               = common.NewOpts()
+	filterOptions      = &filters.Options{}
 )
 
 func init() {
@@ -79,6 +85,33 @@ func initKindsList() {
 	}
 }
 
+func initReferenceRules() error {
+	if referenceRulesFile == "" {
+		return nil
+	}
+	rules, err := kor.LoadReferenceRules(referenceRulesFile, kor.GetKubeClient(kubeconfig).Discovery())
+	if err != nil {
+		return err
+	}
+	opts.ReferenceRules = rules
+	return nil
+}
+
+func initAuditLog() error {
+	if auditLogPath == "" {
+		return nil
+	}
+	activity, err := kor.LoadAuditLogs(auditLogPath)
+	if err != nil {
+		return err
+	}
+	if time.Since(activity.Since) < time.Duration(opts.SubjectInactiveDays)*24*time.Hour {
+		fmt.Fprintf(os.Stderr, "Audit logs only go back to %s, Users and Groups won't be reported inactive for %d days\n", activity.Since.Format(time.DateOnly), opts.SubjectInactiveDays)
+	}
+	opts.AuditLogActivity = activity
+	return nil
+}
+
 func initFlags() {
 	rootCmd.PersistentFlags().StringVarP(&kubeconfig, "kubeconfig", "k", "", "Path to kubeconfig file (optional)")
 	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json or yaml)")
@@ -91,6 +124,9 @@ func initFlags() {
 	rootCmd.PersistentFlags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Verbose output (print empty namespaces)")
 	rootCmd.PersistentFlags().StringVar(&opts.GroupBy, "group-by", "namespace", "Group output by (namespace, resource)")
 	rootCmd.PersistentFlags().BoolVar(&opts.ShowReason, "show-reason", false, "Print reason resource is considered unused")
+	rootCmd.PersistentFlags().StringVar(&referenceRulesFile, "reference-rules", "", "Path to a file with rules describing how custom resources reference other objects or select pods")
+	rootCmd.PersistentFlags().StringVar(&auditLogPath, "audit-log", "", "Path to a Kubernetes audit log file or directory, used to report RoleBinding and ClusterRoleBinding Users and Groups that stopped making requests")
+	rootCmd.PersistentFlags().IntVar(&opts.SubjectInactiveDays, "subject-inactive-days", opts.SubjectInactiveDays, "Number of days without requests in the audit logs for a User or Group to be reported inactive")
 }
 
 func initViper() {
@@ -16,8 +16,9 @@ var secretCmd = &cobra.Command{
 	Args:    cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		clientset := kor.GetKubeClient(kubeconfig)
+		dynamicClient := kor.GetDynamicClient(kubeconfig)
 
-		if response, err := kor.GetUnusedSecrets(filterOptions, clientset, outputFormat, opts); err != nil {
+		if response, err := kor.GetUnusedSecrets(filterOptions, clientset, dynamicClient, outputFormat, opts); err != nil {
 			fmt.Println(err)
 		} else {
 			utils.PrintLogo(outputFormat, opts.ClusterName)
@@ -27,5 +28,7 @@ var secretCmd = &cobra.Command{
 }
 
 func init() {
+	secretCmd.Flags().BoolVar(&opts.UnusedKeys, "unused-keys", false, "Also report keys of used Secrets that no consumer references, for objects only consumed key by key")
+	secretCmd.Flags().BoolVar(&opts.ShowUsed, "show-used", false, "Also list the used Secrets with why they are considered used (table output)")
 	rootCmd.AddCommand(secretCmd)
 }
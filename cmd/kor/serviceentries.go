@@ -0,0 +1,33 @@
+package kor
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yonahd/kor/pkg/kor"
+	"github.com/yonahd/kor/pkg/utils"
+)
+
+var serviceEntryCmd = &cobra.Command{
+	Use:     "serviceentry",
+	Aliases: []string{"se", "serviceentries"},
+	Short:   "Gets unused serviceentries",
+	Args:    cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		clientset := kor.GetKubeClient(kubeconfig)
+		dynamicClient := kor.GetDynamicClient(kubeconfig)
+
+		if response, err := kor.GetUnusedServiceEntries(filterOptions, clientset, dynamicClient, outputFormat, opts); err != nil {
+			fmt.Println(err)
+		} else {
+			utils.PrintLogo(outputFormat, opts.ClusterName)
+			fmt.Println(response)
+		}
+	},
+}
+
+func init() {
+	serviceEntryCmd.Flags().StringVar(&opts.IstioRootNamespace, "istio-root-namespace", "", "Istio root namespace, whose ServiceEntries apply to workloads in every namespace (defaults to the rootNamespace of the istio ConfigMap in istio-system)")
+	rootCmd.AddCommand(serviceEntryCmd)
+}
@@ -0,0 +1,33 @@
+package kor
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yonahd/kor/pkg/kor"
+	"github.com/yonahd/kor/pkg/utils"
+)
+
+var sidecarCmd = &cobra.Command{
+	Use:     "sidecar",
+	Aliases: []string{"sidecars"},
+	Short:   "Gets unused sidecars",
+	Args:    cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		clientset := kor.GetKubeClient(kubeconfig)
+		dynamicClient := kor.GetDynamicClient(kubeconfig)
+
+		if response, err := kor.GetUnusedSidecars(filterOptions, clientset, dynamicClient, outputFormat, opts); err != nil {
+			fmt.Println(err)
+		} else {
+			utils.PrintLogo(outputFormat, opts.ClusterName)
+			fmt.Println(response)
+		}
+	},
+}
+
+func init() {
+	sidecarCmd.Flags().StringVar(&opts.IstioRootNamespace, "istio-root-namespace", "", "Istio root namespace, whose Sidecars apply to workloads in every namespace (defaults to the rootNamespace of the istio ConfigMap in istio-system)")
+	rootCmd.AddCommand(sidecarCmd)
+}
@@ -27,5 +27,6 @@ var stsCmd = &cobra.Command{
 }
 
 func init() {
+	stsCmd.Flags().DurationVar(&opts.UnavailableThreshold, "unavailable-threshold", opts.UnavailableThreshold, "Minimum time a StatefulSet must have no available replicas to be reported")
 	rootCmd.AddCommand(stsCmd)
 }
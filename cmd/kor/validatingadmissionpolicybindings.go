@@ -0,0 +1,32 @@
+package kor
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yonahd/kor/pkg/kor"
+	"github.com/yonahd/kor/pkg/utils"
+)
+
+var validatingAdmissionPolicyBindingCmd = &cobra.Command{
+	Use:     "validatingadmissionpolicybinding",
+	Aliases: []string{"vapb", "validatingadmissionpolicybindings"},
+	Short:   "Gets validatingAdmissionPolicyBindings referencing a missing policy or param",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		clientset := kor.GetKubeClient(kubeconfig)
+		dynamicClient := kor.GetDynamicClient(kubeconfig)
+
+		if response, err := kor.GetUnusedValidatingAdmissionPolicyBindings(filterOptions, clientset, dynamicClient, outputFormat, opts); err != nil {
+			fmt.Println(err)
+		} else {
+			utils.PrintLogo(outputFormat, opts.ClusterName)
+			fmt.Println(response)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validatingAdmissionPolicyBindingCmd)
+}
@@ -1,5 +1,7 @@
 package common
 
+import "time"
+
 type Opts struct {
 	DeleteFlag    bool
 	NoInteractive bool
@@ -11,4 +13,53 @@ type Opts struct {
 	GroupBy       string
 	ShowReason    bool
 	Namespaced    bool
+	CrdInstances  bool
+	UnusedKeys    bool
+	// ShowUsed lists the used Secrets with why they are used, in a section of the table output of its own
+	ShowUsed bool
+
+	NodeNotReadyThreshold time.Duration
+	NodeCordonedDays      int
+	LeaseRenewThreshold   time.Duration
+	CsrThreshold          time.Duration
+	PodFinishedThreshold  time.Duration
+	PodPendingThreshold   time.Duration
+	PodWaitingThreshold   time.Duration
+	UnavailableThreshold  time.Duration
+	// RollbackHistory overrides the revisionHistoryLimit of Deployments when it isn't negative
+	RollbackHistory int
+
+	// AuditLogActivity is loaded from --audit-log, Users and Groups are only reported inactive when it is set
+	AuditLogActivity    *SubjectActivity
+	SubjectInactiveDays int
+
+	// IstioRootNamespace overrides the rootNamespace read from the Istio mesh config
+	IstioRootNamespace string
+
+	// ReferenceRules are loaded from --reference-rules, with rules whose source is not installed left out
+	ReferenceRules *ReferenceRules
+}
+
+// NewOpts returns Opts with the default thresholds, which the command line flags start from
+func NewOpts() Opts {
+	return Opts{
+		NodeNotReadyThreshold: time.Hour,
+		NodeCordonedDays:      7,
+		LeaseRenewThreshold:   24 * time.Hour,
+		CsrThreshold:          24 * time.Hour,
+		PodFinishedThreshold:  24 * time.Hour,
+		PodPendingThreshold:   time.Hour,
+		PodWaitingThreshold:   time.Hour,
+		UnavailableThreshold:  24 * time.Hour,
+		RollbackHistory:       -1,
+		SubjectInactiveDays:   90,
+	}
+}
+
+// SubjectActivity records when each user and group last made a request, as found in Kubernetes audit logs
+type SubjectActivity struct {
+	Users  map[string]time.Time
+	Groups map[string]time.Time
+	// Since is the time of the oldest event, subjects can't be judged inactive for longer than the logs go back
+	Since time.Time
 }
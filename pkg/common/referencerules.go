@@ -0,0 +1,50 @@
+package common
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// ReferenceRules describe how custom resources point at other objects, so that kor can
+// check those references without knowing anything about the operator behind them.
+// Rules apply to namespaced resources and references are resolved in the same namespace.
+type ReferenceRules struct {
+	References []ReferenceRule `json:"references"`
+	Selectors  []SelectorRule  `json:"selectors"`
+}
+
+type RuleResource struct {
+	Group    string `json:"group"`
+	Version  string `json:"version"`
+	Resource string `json:"resource"`
+	// Kind is only used for display and defaults to the resource name
+	Kind string `json:"kind,omitempty"`
+}
+
+// ReferenceRule reads "Source references Target by the name found at Path"
+type ReferenceRule struct {
+	Source RuleResource `json:"source"`
+	Target RuleResource `json:"target"`
+	Path   string       `json:"path"`
+	// ParsedPath is Path as parsed when the rules are loaded
+	ParsedPath *jsonpath.JSONPath `json:"-"`
+}
+
+// SelectorRule reads "Source selects pods with the label selector found at Path"
+type SelectorRule struct {
+	Source RuleResource `json:"source"`
+	Path   string       `json:"path"`
+	// ParsedPath is Path as parsed when the rules are loaded
+	ParsedPath *jsonpath.JSONPath `json:"-"`
+}
+
+func (r RuleResource) GVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Resource}
+}
+
+func (r RuleResource) DisplayKind() string {
+	if r.Kind != "" {
+		return r.Kind
+	}
+	return r.Resource
+}
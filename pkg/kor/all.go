@@ -7,6 +7,7 @@ import (
 	"os"
 
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
@@ -26,8 +27,8 @@ type ResourceDiff struct {
 	diff         []ResourceInfo
 }
 
-func getUnusedCMs(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
-	cmDiff, err := processNamespaceCM(clientset, namespace, filterOpts, opts)
+func getUnusedCMs(clientset kubernetes.Interface, dynamicClient dynamic.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	cmDiff, err := processNamespaceCM(clientset, dynamicClient, namespace, filterOpts, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to get %s namespace %s: %v\n", "configmaps", namespace, err)
 	}
@@ -38,8 +39,8 @@ func getUnusedCMs(clientset kubernetes.Interface, namespace string, filterOpts *
 	return namespaceCMDiff
 }
 
-func getUnusedSVCs(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
-	svcDiff, err := processNamespaceServices(clientset, namespace, filterOpts, opts)
+func getUnusedSVCs(clientset kubernetes.Interface, dynamicClient dynamic.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	svcDiff, err := processNamespaceServices(clientset, dynamicClient, namespace, filterOpts, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to get %s namespace %s: %v\n", "services", namespace, err)
 	}
@@ -50,8 +51,8 @@ func getUnusedSVCs(clientset kubernetes.Interface, namespace string, filterOpts
 	return namespaceSVCDiff
 }
 
-func getUnusedSecrets(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
-	secretDiff, err := processNamespaceSecret(clientset, namespace, filterOpts, opts)
+func getUnusedSecrets(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	secretDiff, err := processNamespaceSecret(clientset, dynamicClient, cache, namespace, filterOpts, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to get %s namespace %s: %v\n", "secrets", namespace, err)
 	}
@@ -62,8 +63,8 @@ func getUnusedSecrets(clientset kubernetes.Interface, namespace string, filterOp
 	return namespaceSecretDiff
 }
 
-func getUnusedServiceAccounts(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
-	saDiff, err := processNamespaceSA(clientset, namespace, filterOpts, opts)
+func getUnusedServiceAccounts(clientset kubernetes.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	saDiff, err := processNamespaceSA(clientset, cache, namespace, filterOpts, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to get %s namespace %s: %v\n", "serviceaccounts", namespace, err)
 	}
@@ -98,8 +99,8 @@ func getUnusedStatefulSets(clientset kubernetes.Interface, namespace string, fil
 	return namespaceSADiff
 }
 
-func getUnusedRoles(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
-	roleDiff, err := processNamespaceRoles(clientset, namespace, filterOpts, opts)
+func getUnusedRoles(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	roleDiff, err := processNamespaceRoles(clientset, dynamicClient, cache, namespace, filterOpts, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to get %s namespace %s: %v\n", "roles", namespace, err)
 	}
@@ -110,20 +111,20 @@ func getUnusedRoles(clientset kubernetes.Interface, namespace string, filterOpts
 	return namespaceSADiff
 }
 
-func getUnusedClusterRoles(clientset kubernetes.Interface, filterOpts *filters.Options) ResourceDiff {
-	clusterRoleDiff, err := processClusterRoles(clientset, filterOpts)
+func getUnusedClusterRoles(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, filterOpts *filters.Options) ResourceDiff {
+	clusterRoleDiff, deadClusterRoles, err := processClusterRoles(clientset, dynamicClient, cache, filterOpts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to get %s: %v\n", "clusterRoles", err)
 	}
 	aDiff := ResourceDiff{
 		"ClusterRole",
-		clusterRoleDiff,
+		append(clusterRoleDiff, deadClusterRoles...),
 	}
 	return aDiff
 }
 
-func getUnusedClusterRoleBindings(clientset kubernetes.Interface, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
-	clusterRoleBindingDiff, err := processClusterRoleBindings(clientset, filterOpts, opts)
+func getUnusedClusterRoleBindings(clientset kubernetes.Interface, cache *clusterCache, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	clusterRoleBindingDiff, err := processClusterRoleBindings(clientset, cache, filterOpts, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to get %s: %v\n", "clusterRoleBindings", err)
 	}
@@ -194,8 +195,8 @@ func getUnusedCrds(apiExtClient apiextensionsclientset.Interface, dynamicClient
 	return allCrdDiff
 }
 
-func getUnusedPvs(clientset kubernetes.Interface, filterOpts *filters.Options) ResourceDiff {
-	pvDiff, err := processPvs(clientset, filterOpts)
+func getUnusedPvs(clientset kubernetes.Interface, filterOpts *filters.Options) (ResourceDiff, resource.Quantity) {
+	pvDiff, reclaimable, err := processPvs(clientset, filterOpts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to get %s: %v\n", "Pvs", err)
 	}
@@ -203,7 +204,91 @@ func getUnusedPvs(clientset kubernetes.Interface, filterOpts *filters.Options) R
 		"Pv",
 		pvDiff,
 	}
-	return allPvDiff
+	return allPvDiff, reclaimable
+}
+
+func getUnusedNodes(clientset kubernetes.Interface, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	nodeDiff, err := processNodes(clientset, filterOpts, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get %s: %v\n", "Nodes", err)
+	}
+	allNodeDiff := ResourceDiff{
+		"Node",
+		nodeDiff,
+	}
+	return allNodeDiff
+}
+
+func getUnusedRuntimeClasses(clientset kubernetes.Interface, filterOpts *filters.Options) ResourceDiff {
+	runtimeClassDiff, err := processRuntimeClasses(clientset, filterOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get %s: %v\n", "RuntimeClasses", err)
+	}
+	allRuntimeClassDiff := ResourceDiff{
+		"RuntimeClass",
+		runtimeClassDiff,
+	}
+	return allRuntimeClassDiff
+}
+
+func getUnusedFlowSchemas(clientset kubernetes.Interface, filterOpts *filters.Options) ResourceDiff {
+	flowSchemaDiff, err := processFlowSchemas(clientset, filterOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get %s: %v\n", "FlowSchemas", err)
+	}
+	allFlowSchemaDiff := ResourceDiff{
+		"FlowSchema",
+		flowSchemaDiff,
+	}
+	return allFlowSchemaDiff
+}
+
+func getUnusedPriorityLevelConfigurations(clientset kubernetes.Interface, filterOpts *filters.Options) ResourceDiff {
+	priorityLevelConfigurationDiff, err := processPriorityLevelConfigurations(clientset, filterOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get %s: %v\n", "PriorityLevelConfigurations", err)
+	}
+	allPriorityLevelConfigurationDiff := ResourceDiff{
+		"PriorityLevelConfiguration",
+		priorityLevelConfigurationDiff,
+	}
+	return allPriorityLevelConfigurationDiff
+}
+
+func getUnusedValidatingAdmissionPolicies(clientset kubernetes.Interface, filterOpts *filters.Options) ResourceDiff {
+	policyDiff, err := processValidatingAdmissionPolicies(clientset, filterOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get %s: %v\n", "ValidatingAdmissionPolicies", err)
+	}
+	allPolicyDiff := ResourceDiff{
+		"ValidatingAdmissionPolicy",
+		policyDiff,
+	}
+	return allPolicyDiff
+}
+
+func getUnusedValidatingAdmissionPolicyBindings(clientset kubernetes.Interface, dynamicClient dynamic.Interface, filterOpts *filters.Options) ResourceDiff {
+	bindingDiff, err := processValidatingAdmissionPolicyBindings(clientset, dynamicClient, filterOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get %s: %v\n", "ValidatingAdmissionPolicyBindings", err)
+	}
+	allBindingDiff := ResourceDiff{
+		"ValidatingAdmissionPolicyBinding",
+		bindingDiff,
+	}
+	return allBindingDiff
+}
+
+func getUnusedCertificateSigningRequests(clientset kubernetes.Interface, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	csrDiff, err := processCertificateSigningRequests(clientset, filterOpts, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get %s: %v\n", "CertificateSigningRequests", err)
+	}
+	allCsrDiff := ResourceDiff{
+		"CertificateSigningRequest",
+		csrDiff,
+	}
+	return allCsrDiff
 }
 
 func getUnusedPods(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
@@ -255,13 +340,13 @@ func getUnusedDaemonSets(clientset kubernetes.Interface, namespace string, filte
 }
 
 func getUnusedStorageClasses(clientset kubernetes.Interface, filterOpts *filters.Options) ResourceDiff {
-	scDiff, err := processStorageClasses(clientset, filterOpts)
+	scDiff, scIssues, err := processStorageClasses(clientset, filterOpts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to get %s: %v\n", "StorageClasses", err)
 	}
 	allScDiff := ResourceDiff{
 		"StorageClass",
-		scDiff,
+		append(scDiff, scIssues...),
 	}
 	return allScDiff
 }
@@ -289,8 +374,8 @@ func getUnusedPriorityClasses(clientset kubernetes.Interface, filterOpts *filter
 	return allPcDiff
 }
 
-func getUnusedNetworkPolicies(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
-	netpolDiff, err := processNamespaceNetworkPolicies(clientset, namespace, filterOpts, opts)
+func getUnusedNetworkPolicies(clientset kubernetes.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	netpolDiff, err := processNamespaceNetworkPolicies(clientset, cache, namespace, filterOpts, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to get %s namespace %s: %v\n", "NetworkPolicies", namespace, err)
 	}
@@ -301,8 +386,8 @@ func getUnusedNetworkPolicies(clientset kubernetes.Interface, namespace string,
 	return namespaceNetpolDiff
 }
 
-func getUnusedRoleBindings(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
-	roleBindingDiff, err := processNamespaceRoleBindings(clientset, namespace, filterOpts, opts)
+func getUnusedRoleBindings(clientset kubernetes.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	roleBindingDiff, err := processNamespaceRoleBindings(clientset, cache, namespace, filterOpts, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to get %s namespace %s: %v\n", "RoleBindings", namespace, err)
 	}
@@ -314,19 +399,131 @@ func getUnusedRoleBindings(clientset kubernetes.Interface, namespace string, fil
 	return namespaceRoleBindingDiff
 }
 
-func GetUnusedAllNamespaced(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
+func getUnusedVirtualServices(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	virtualServiceDiff, err := processNamespaceVirtualServices(clientset, dynamicClient, cache, namespace, filterOpts, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get %s namespace %s: %v\n", "VirtualServices", namespace, err)
+	}
+	namespaceVirtualServiceDiff := ResourceDiff{
+		"VirtualService",
+		virtualServiceDiff,
+	}
+	return namespaceVirtualServiceDiff
+}
+
+func getUnusedDestinationRules(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	destinationRuleDiff, err := processNamespaceDestinationRules(clientset, dynamicClient, cache, namespace, filterOpts, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get %s namespace %s: %v\n", "DestinationRules", namespace, err)
+	}
+	namespaceDestinationRuleDiff := ResourceDiff{
+		"DestinationRule",
+		destinationRuleDiff,
+	}
+	return namespaceDestinationRuleDiff
+}
+
+func getUnusedServiceEntries(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	serviceEntryDiff, err := processNamespaceServiceEntries(clientset, dynamicClient, cache, namespace, filterOpts, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get %s namespace %s: %v\n", "ServiceEntries", namespace, err)
+	}
+	namespaceServiceEntryDiff := ResourceDiff{
+		"ServiceEntry",
+		serviceEntryDiff,
+	}
+	return namespaceServiceEntryDiff
+}
+
+func getUnusedSidecars(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	sidecarDiff, err := processNamespaceSidecars(clientset, dynamicClient, cache, namespace, filterOpts, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get %s namespace %s: %v\n", "Sidecars", namespace, err)
+	}
+	namespaceSidecarDiff := ResourceDiff{
+		"Sidecar",
+		sidecarDiff,
+	}
+	return namespaceSidecarDiff
+}
+
+func getUnusedAuthorizationPolicies(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	authzPolicyDiff, err := processNamespaceAuthorizationPolicies(clientset, dynamicClient, cache, namespace, filterOpts, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get %s namespace %s: %v\n", "AuthorizationPolicies", namespace, err)
+	}
+	namespaceAuthorizationPolicyDiff := ResourceDiff{
+		"AuthorizationPolicy",
+		authzPolicyDiff,
+	}
+	return namespaceAuthorizationPolicyDiff
+}
+
+func getUnusedPeerAuthentications(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	peerAuthnDiff, err := processNamespacePeerAuthentications(clientset, dynamicClient, cache, namespace, filterOpts, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get %s namespace %s: %v\n", "PeerAuthentications", namespace, err)
+	}
+	namespacePeerAuthenticationDiff := ResourceDiff{
+		"PeerAuthentication",
+		peerAuthnDiff,
+	}
+	return namespacePeerAuthenticationDiff
+}
+
+func getUnusedLeases(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	leaseDiff, err := processNamespaceLeases(clientset, namespace, filterOpts, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get %s namespace %s: %v\n", "Leases", namespace, err)
+	}
+	namespaceLeaseDiff := ResourceDiff{
+		"Lease",
+		leaseDiff,
+	}
+	return namespaceLeaseDiff
+}
+
+func getUnusedPodTemplates(clientset kubernetes.Interface, dynamicClient dynamic.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	podTemplateDiff, err := processNamespacePodTemplates(clientset, dynamicClient, namespace, filterOpts, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get %s namespace %s: %v\n", "PodTemplates", namespace, err)
+	}
+	namespacePodTemplateDiff := ResourceDiff{
+		"PodTemplate",
+		podTemplateDiff,
+	}
+	return namespacePodTemplateDiff
+}
+
+func getUnusedControllerRevisions(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ResourceDiff {
+	controllerRevisionDiff, err := processNamespaceControllerRevisions(clientset, namespace, filterOpts, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to get %s namespace %s: %v\n", "ControllerRevisions", namespace, err)
+	}
+	namespaceControllerRevisionDiff := ResourceDiff{
+		"ControllerRevision",
+		controllerRevisionDiff,
+	}
+	return namespaceControllerRevisionDiff
+}
+
+func GetUnusedAllNamespaced(filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
+	return getUnusedAllNamespaced(filterOpts, clientset, dynamicClient, newClusterCache(), outputFormat, opts)
+}
+
+func getUnusedAllNamespaced(filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, outputFormat string, opts common.Opts) (string, error) {
 	resources := make(map[string]map[string][]ResourceInfo)
 	for _, namespace := range filterOpts.Namespaces(clientset) {
 		switch opts.GroupBy {
 		case "namespace":
 			resources[namespace] = make(map[string][]ResourceInfo)
-			resources[namespace]["ConfigMap"] = getUnusedCMs(clientset, namespace, filterOpts, opts).diff
-			resources[namespace]["Service"] = getUnusedSVCs(clientset, namespace, filterOpts, opts).diff
-			resources[namespace]["Secret"] = getUnusedSecrets(clientset, namespace, filterOpts, opts).diff
-			resources[namespace]["ServiceAccount"] = getUnusedServiceAccounts(clientset, namespace, filterOpts, opts).diff
+			resources[namespace]["ConfigMap"] = getUnusedCMs(clientset, dynamicClient, namespace, filterOpts, opts).diff
+			resources[namespace]["Service"] = getUnusedSVCs(clientset, dynamicClient, namespace, filterOpts, opts).diff
+			resources[namespace]["Secret"] = getUnusedSecrets(clientset, dynamicClient, cache, namespace, filterOpts, opts).diff
+			resources[namespace]["ServiceAccount"] = getUnusedServiceAccounts(clientset, cache, namespace, filterOpts, opts).diff
 			resources[namespace]["Deployment"] = getUnusedDeployments(clientset, namespace, filterOpts, opts).diff
 			resources[namespace]["StatefulSet"] = getUnusedStatefulSets(clientset, namespace, filterOpts, opts).diff
-			resources[namespace]["Role"] = getUnusedRoles(clientset, namespace, filterOpts, opts).diff
+			resources[namespace]["Role"] = getUnusedRoles(clientset, dynamicClient, cache, namespace, filterOpts, opts).diff
 			resources[namespace]["Hpa"] = getUnusedHpas(clientset, namespace, filterOpts, opts).diff
 			resources[namespace]["Pvc"] = getUnusedPvcs(clientset, namespace, filterOpts, opts).diff
 			resources[namespace]["Pod"] = getUnusedPods(clientset, namespace, filterOpts, opts).diff
@@ -335,16 +532,19 @@ func GetUnusedAllNamespaced(filterOpts *filters.Options, clientset kubernetes.In
 			resources[namespace]["Job"] = getUnusedJobs(clientset, namespace, filterOpts, opts).diff
 			resources[namespace]["ReplicaSet"] = getUnusedReplicaSets(clientset, namespace, filterOpts, opts).diff
 			resources[namespace]["DaemonSet"] = getUnusedDaemonSets(clientset, namespace, filterOpts, opts).diff
-			resources[namespace]["NetworkPolicy"] = getUnusedNetworkPolicies(clientset, namespace, filterOpts, opts).diff
-			resources[namespace]["RoleBinding"] = getUnusedRoleBindings(clientset, namespace, filterOpts, opts).diff
+			resources[namespace]["NetworkPolicy"] = getUnusedNetworkPolicies(clientset, cache, namespace, filterOpts, opts).diff
+			resources[namespace]["RoleBinding"] = getUnusedRoleBindings(clientset, cache, namespace, filterOpts, opts).diff
+			resources[namespace]["Lease"] = getUnusedLeases(clientset, namespace, filterOpts, opts).diff
+			resources[namespace]["PodTemplate"] = getUnusedPodTemplates(clientset, dynamicClient, namespace, filterOpts, opts).diff
+			resources[namespace]["ControllerRevision"] = getUnusedControllerRevisions(clientset, namespace, filterOpts, opts).diff
 		case "resource":
-			appendResources(resources, "ConfigMap", namespace, getUnusedCMs(clientset, namespace, filterOpts, opts).diff)
-			appendResources(resources, "Service", namespace, getUnusedSVCs(clientset, namespace, filterOpts, opts).diff)
-			appendResources(resources, "Secret", namespace, getUnusedSecrets(clientset, namespace, filterOpts, opts).diff)
-			appendResources(resources, "ServiceAccount", namespace, getUnusedServiceAccounts(clientset, namespace, filterOpts, opts).diff)
+			appendResources(resources, "ConfigMap", namespace, getUnusedCMs(clientset, dynamicClient, namespace, filterOpts, opts).diff)
+			appendResources(resources, "Service", namespace, getUnusedSVCs(clientset, dynamicClient, namespace, filterOpts, opts).diff)
+			appendResources(resources, "Secret", namespace, getUnusedSecrets(clientset, dynamicClient, cache, namespace, filterOpts, opts).diff)
+			appendResources(resources, "ServiceAccount", namespace, getUnusedServiceAccounts(clientset, cache, namespace, filterOpts, opts).diff)
 			appendResources(resources, "Deployment", namespace, getUnusedDeployments(clientset, namespace, filterOpts, opts).diff)
 			appendResources(resources, "StatefulSet", namespace, getUnusedStatefulSets(clientset, namespace, filterOpts, opts).diff)
-			appendResources(resources, "Role", namespace, getUnusedRoles(clientset, namespace, filterOpts, opts).diff)
+			appendResources(resources, "Role", namespace, getUnusedRoles(clientset, dynamicClient, cache, namespace, filterOpts, opts).diff)
 			appendResources(resources, "Hpa", namespace, getUnusedHpas(clientset, namespace, filterOpts, opts).diff)
 			appendResources(resources, "Pvc", namespace, getUnusedPvcs(clientset, namespace, filterOpts, opts).diff)
 			appendResources(resources, "Pod", namespace, getUnusedPods(clientset, namespace, filterOpts, opts).diff)
@@ -353,8 +553,11 @@ func GetUnusedAllNamespaced(filterOpts *filters.Options, clientset kubernetes.In
 			appendResources(resources, "Job", namespace, getUnusedJobs(clientset, namespace, filterOpts, opts).diff)
 			appendResources(resources, "ReplicaSet", namespace, getUnusedReplicaSets(clientset, namespace, filterOpts, opts).diff)
 			appendResources(resources, "DaemonSet", namespace, getUnusedDaemonSets(clientset, namespace, filterOpts, opts).diff)
-			appendResources(resources, "NetworkPolicy", namespace, getUnusedNetworkPolicies(clientset, namespace, filterOpts, opts).diff)
-			appendResources(resources, "RoleBinding", namespace, getUnusedRoleBindings(clientset, namespace, filterOpts, opts).diff)
+			appendResources(resources, "NetworkPolicy", namespace, getUnusedNetworkPolicies(clientset, cache, namespace, filterOpts, opts).diff)
+			appendResources(resources, "RoleBinding", namespace, getUnusedRoleBindings(clientset, cache, namespace, filterOpts, opts).diff)
+			appendResources(resources, "Lease", namespace, getUnusedLeases(clientset, namespace, filterOpts, opts).diff)
+			appendResources(resources, "PodTemplate", namespace, getUnusedPodTemplates(clientset, dynamicClient, namespace, filterOpts, opts).diff)
+			appendResources(resources, "ControllerRevision", namespace, getUnusedControllerRevisions(clientset, namespace, filterOpts, opts).diff)
 		}
 	}
 
@@ -379,25 +582,42 @@ func GetUnusedAllNamespaced(filterOpts *filters.Options, clientset kubernetes.In
 }
 
 func GetUnusedAllNonNamespaced(filterOpts *filters.Options, clientset kubernetes.Interface, apiExtClient apiextensionsclientset.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
+	return getUnusedAllNonNamespaced(filterOpts, clientset, apiExtClient, dynamicClient, newClusterCache(), outputFormat, opts)
+}
+
+func getUnusedAllNonNamespaced(filterOpts *filters.Options, clientset kubernetes.Interface, apiExtClient apiextensionsclientset.Interface, dynamicClient dynamic.Interface, cache *clusterCache, outputFormat string, opts common.Opts) (string, error) {
 	resources := make(map[string]map[string][]ResourceInfo)
+	pvDiff, reclaimable := getUnusedPvs(clientset, filterOpts)
 	switch opts.GroupBy {
 	case "namespace":
 		resources[""] = make(map[string][]ResourceInfo)
 		resources[""]["Crd"] = getUnusedCrds(apiExtClient, dynamicClient, filterOpts).diff
-		resources[""]["Pv"] = getUnusedPvs(clientset, filterOpts).diff
-		resources[""]["ClusterRole"] = getUnusedClusterRoles(clientset, filterOpts).diff
-		resources[""]["ClusterRoleBinding"] = getUnusedClusterRoleBindings(clientset, filterOpts, opts).diff
+		resources[""]["Pv"] = pvDiff.diff
+		resources[""]["ClusterRole"] = getUnusedClusterRoles(clientset, dynamicClient, cache, filterOpts).diff
+		resources[""]["ClusterRoleBinding"] = getUnusedClusterRoleBindings(clientset, cache, filterOpts, opts).diff
 		resources[""]["StorageClass"] = getUnusedStorageClasses(clientset, filterOpts).diff
 		resources[""]["VolumeAttachment"] = getUnusedVolumeAttachments(clientset, filterOpts).diff
 		resources[""]["PriorityClass"] = getUnusedPriorityClasses(clientset, filterOpts).diff
+		resources[""]["RuntimeClass"] = getUnusedRuntimeClasses(clientset, filterOpts).diff
+		resources[""]["FlowSchema"] = getUnusedFlowSchemas(clientset, filterOpts).diff
+		resources[""]["PriorityLevelConfiguration"] = getUnusedPriorityLevelConfigurations(clientset, filterOpts).diff
+		resources[""]["ValidatingAdmissionPolicy"] = getUnusedValidatingAdmissionPolicies(clientset, filterOpts).diff
+		resources[""]["ValidatingAdmissionPolicyBinding"] = getUnusedValidatingAdmissionPolicyBindings(clientset, dynamicClient, filterOpts).diff
+		resources[""]["CertificateSigningRequest"] = getUnusedCertificateSigningRequests(clientset, filterOpts, opts).diff
 	case "resource":
 		appendResources(resources, "Crd", "", getUnusedCrds(apiExtClient, dynamicClient, filterOpts).diff)
-		appendResources(resources, "Pv", "", getUnusedPvs(clientset, filterOpts).diff)
-		appendResources(resources, "ClusterRole", "", getUnusedClusterRoles(clientset, filterOpts).diff)
-		appendResources(resources, "ClusterRoleBinding", "", getUnusedClusterRoleBindings(clientset, filterOpts, opts).diff)
+		appendResources(resources, "Pv", "", pvDiff.diff)
+		appendResources(resources, "ClusterRole", "", getUnusedClusterRoles(clientset, dynamicClient, cache, filterOpts).diff)
+		appendResources(resources, "ClusterRoleBinding", "", getUnusedClusterRoleBindings(clientset, cache, filterOpts, opts).diff)
 		appendResources(resources, "StorageClass", "", getUnusedStorageClasses(clientset, filterOpts).diff)
 		appendResources(resources, "VolumeAttachment", "", getUnusedVolumeAttachments(clientset, filterOpts).diff)
 		appendResources(resources, "PriorityClass", "", getUnusedPriorityClasses(clientset, filterOpts).diff)
+		appendResources(resources, "RuntimeClass", "", getUnusedRuntimeClasses(clientset, filterOpts).diff)
+		appendResources(resources, "FlowSchema", "", getUnusedFlowSchemas(clientset, filterOpts).diff)
+		appendResources(resources, "PriorityLevelConfiguration", "", getUnusedPriorityLevelConfigurations(clientset, filterOpts).diff)
+		appendResources(resources, "ValidatingAdmissionPolicy", "", getUnusedValidatingAdmissionPolicies(clientset, filterOpts).diff)
+		appendResources(resources, "ValidatingAdmissionPolicyBinding", "", getUnusedValidatingAdmissionPolicyBindings(clientset, dynamicClient, filterOpts).diff)
+		appendResources(resources, "CertificateSigningRequest", "", getUnusedCertificateSigningRequests(clientset, filterOpts, opts).diff)
 
 	}
 
@@ -412,24 +632,27 @@ func GetUnusedAllNonNamespaced(filterOpts *filters.Options, clientset kubernetes
 			return "", err
 		}
 	}
+	writeReclaimableStorage(&outputBuffer, outputFormat, reclaimable)
 
 	unusedAllNonNamespaced, err := unusedResourceFormatter(outputFormat, outputBuffer, opts, jsonResponse)
 	if err != nil {
 		fmt.Printf("err: %v\n", err)
 	}
 
-	return unusedAllNonNamespaced, nil
+	return addReclaimableStorage(unusedAllNonNamespaced, outputFormat, reclaimable)
 }
 
 func GetUnusedAll(filterOpts *filters.Options, clientset kubernetes.Interface, apiExtClient apiextensionsclientset.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
 	if NamespacedFlagUsed {
 		if opts.Namespaced {
-			return GetUnusedAllNamespaced(filterOpts, clientset, outputFormat, opts)
+			return GetUnusedAllNamespaced(filterOpts, clientset, dynamicClient, outputFormat, opts)
 		}
 		return GetUnusedAllNonNamespaced(filterOpts, clientset, apiExtClient, dynamicClient, outputFormat, opts)
 	}
 
-	unusedAllNamespaced, err := GetUnusedAllNamespaced(filterOpts, clientset, outputFormat, opts)
+	// Both runs share the cluster-wide lookups, e.g. API discovery for Roles and ClusterRoles
+	cache := newClusterCache()
+	unusedAllNamespaced, err := getUnusedAllNamespaced(filterOpts, clientset, dynamicClient, cache, outputFormat, opts)
 	if err != nil {
 		fmt.Printf("err: %v\n", err)
 	}
@@ -439,7 +662,7 @@ func GetUnusedAll(filterOpts *filters.Options, clientset kubernetes.Interface, a
 		return unusedAllNamespaced, nil
 	}
 
-	unusedAllNonNamespaced, err := GetUnusedAllNonNamespaced(filterOpts, clientset, apiExtClient, dynamicClient, outputFormat, opts)
+	unusedAllNonNamespaced, err := getUnusedAllNonNamespaced(filterOpts, clientset, apiExtClient, dynamicClient, cache, outputFormat, opts)
 	if err != nil {
 		fmt.Printf("err: %v\n", err)
 	}
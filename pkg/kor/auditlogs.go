@@ -0,0 +1,151 @@
+package kor
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/yonahd/kor/pkg/common"
+)
+
+type auditUserInfo struct {
+	Username string   `json:"username"`
+	Groups   []string `json:"groups"`
+}
+
+// auditEvent holds the fields kor needs from an audit.k8s.io/v1 Event
+type auditEvent struct {
+	User                     auditUserInfo  `json:"user"`
+	ImpersonatedUser         *auditUserInfo `json:"impersonatedUser"`
+	RequestReceivedTimestamp time.Time      `json:"requestReceivedTimestamp"`
+}
+
+func recordSubjectActivity(a *common.SubjectActivity, user auditUserInfo, timestamp time.Time) {
+	if user.Username != "" && timestamp.After(a.Users[user.Username]) {
+		a.Users[user.Username] = timestamp
+	}
+	for _, group := range user.Groups {
+		if timestamp.After(a.Groups[group]) {
+			a.Groups[group] = timestamp
+		}
+	}
+}
+
+func loadAuditLogFile(a *common.SubjectActivity, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("failed to read audit log %s: %v", path, err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	// Audit logs in json format hold one event per line
+	decoder := json.NewDecoder(reader)
+	for {
+		var event auditEvent
+		if err := decoder.Decode(&event); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to parse audit log %s: %v", path, err)
+		}
+		if event.RequestReceivedTimestamp.IsZero() {
+			continue
+		}
+
+		recordSubjectActivity(a, event.User, event.RequestReceivedTimestamp)
+		if event.ImpersonatedUser != nil {
+			recordSubjectActivity(a, *event.ImpersonatedUser, event.RequestReceivedTimestamp)
+		}
+		if a.Since.IsZero() || event.RequestReceivedTimestamp.Before(a.Since) {
+			a.Since = event.RequestReceivedTimestamp
+		}
+	}
+}
+
+// LoadAuditLogs reads an audit log file, or every file of a directory such as rotated and gzipped logs.
+// Files of a directory that aren't audit logs are skipped with a warning.
+func LoadAuditLogs(path string) (*common.SubjectActivity, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := &common.SubjectActivity{
+		Users:  make(map[string]time.Time),
+		Groups: make(map[string]time.Time),
+	}
+	if !info.IsDir() {
+		if err := loadAuditLogFile(activity, path); err != nil {
+			return nil, err
+		}
+	} else {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			if err := loadAuditLogFile(activity, filepath.Join(path, entry.Name())); err != nil {
+				fmt.Fprintf(os.Stderr, "Skipping file that is not an audit log: %v\n", err)
+			}
+		}
+	}
+	if activity.Since.IsZero() {
+		return nil, fmt.Errorf("no audit events found in %s", path)
+	}
+	return activity, nil
+}
+
+// retrieveInactiveSubjectReason explains why a User or Group subject is considered inactive,
+// or returns an empty string when it is active or no audit logs cover the inactivity window.
+// Subjects with the system: prefix are skipped, since components such as other apiservers or
+// unauthenticated clients may not show up in the audit logs that were loaded.
+func retrieveInactiveSubjectReason(subject rbacv1.Subject, opts common.Opts) string {
+	activity := opts.AuditLogActivity
+	if activity == nil || strings.HasPrefix(subject.Name, "system:") {
+		return ""
+	}
+	inactiveDays := opts.SubjectInactiveDays
+	cutoff := time.Now().AddDate(0, 0, -inactiveDays)
+	if activity.Since.After(cutoff) {
+		return ""
+	}
+
+	var lastSeen map[string]time.Time
+	switch subject.Kind {
+	case rbacv1.UserKind:
+		lastSeen = activity.Users
+	case rbacv1.GroupKind:
+		lastSeen = activity.Groups
+	default:
+		return ""
+	}
+
+	timestamp, found := lastSeen[subject.Name]
+	if !found {
+		return fmt.Sprintf("%s %s made no request in %d days", subject.Kind, subject.Name, inactiveDays)
+	}
+	if timestamp.Before(cutoff) {
+		return fmt.Sprintf("%s %s made no request in %d days (last on %s)", subject.Kind, subject.Name, inactiveDays, timestamp.Format(time.DateOnly))
+	}
+	return ""
+}
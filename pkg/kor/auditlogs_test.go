@@ -0,0 +1,104 @@
+package kor
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/yonahd/kor/pkg/common"
+)
+
+func TestLoadAuditLogs(t *testing.T) {
+	dir := t.TempDir()
+
+	current := `{"kind":"Event","apiVersion":"audit.k8s.io/v1","user":{"username":"alice","groups":["devs","system:authenticated"]},"requestReceivedTimestamp":"2024-03-01T10:00:00.000000Z"}
+{"kind":"Event","apiVersion":"audit.k8s.io/v1","user":{"username":"admin"},"impersonatedUser":{"username":"bob","groups":["ops"]},"requestReceivedTimestamp":"2024-03-02T10:00:00.000000Z"}
+`
+	if err := os.WriteFile(filepath.Join(dir, "audit.log"), []byte(current), 0o600); err != nil {
+		t.Fatalf("Error writing audit log: %v", err)
+	}
+
+	rotated, err := os.Create(filepath.Join(dir, "audit-2024-02-01.log.gz"))
+	if err != nil {
+		t.Fatalf("Error creating rotated audit log: %v", err)
+	}
+	gzipWriter := gzip.NewWriter(rotated)
+	if _, err := gzipWriter.Write([]byte(`{"kind":"Event","apiVersion":"audit.k8s.io/v1","user":{"username":"alice","groups":["devs"]},"requestReceivedTimestamp":"2024-02-01T10:00:00.000000Z"}` + "\n")); err != nil {
+		t.Fatalf("Error writing rotated audit log: %v", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatalf("Error closing rotated audit log: %v", err)
+	}
+	rotated.Close()
+
+	// Other files, such as the apiserver's own log, are skipped
+	if err := os.WriteFile(filepath.Join(dir, "kube-apiserver.log"), []byte("I0301 10:00:00.000000 1 server.go:100] Starting\n"), 0o600); err != nil {
+		t.Fatalf("Error writing apiserver log: %v", err)
+	}
+
+	activity, err := LoadAuditLogs(dir)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]time.Time{
+		"alice": time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+		"admin": time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC),
+		"bob":   time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC),
+	}
+	for user, timestamp := range expected {
+		if !activity.Users[user].Equal(timestamp) {
+			t.Errorf("Expected user %s last seen at %s, got %s", user, timestamp, activity.Users[user])
+		}
+	}
+	if _, found := activity.Groups["ops"]; !found {
+		t.Errorf("Expected group ops of the impersonated user to be recorded")
+	}
+	if !activity.Since.Equal(time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected audit logs to go back to 2024-02-01, got %s", activity.Since)
+	}
+
+	if _, err := LoadAuditLogs(t.TempDir()); err == nil {
+		t.Errorf("Expected an error for a directory without audit events")
+	}
+}
+
+func TestRetrieveInactiveSubjectReason(t *testing.T) {
+	now := time.Now()
+	opts := common.Opts{
+		AuditLogActivity: &common.SubjectActivity{
+			Users:  map[string]time.Time{"alice": now.AddDate(0, 0, -1), "bob": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+			Groups: map[string]time.Time{"devs": now},
+			Since:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		SubjectInactiveDays: 90,
+	}
+
+	tests := []struct {
+		subject  rbacv1.Subject
+		expected string
+	}{
+		{rbacv1.Subject{Kind: rbacv1.UserKind, Name: "alice"}, ""},
+		{rbacv1.Subject{Kind: rbacv1.UserKind, Name: "bob"}, "User bob made no request in 90 days (last on 2024-01-02)"},
+		{rbacv1.Subject{Kind: rbacv1.UserKind, Name: "carol"}, "User carol made no request in 90 days"},
+		{rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "devs"}, ""},
+		{rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "ops"}, "Group ops made no request in 90 days"},
+		{rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "system:unauthenticated"}, ""},
+		{rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "default", Namespace: "default"}, ""},
+	}
+	for _, test := range tests {
+		if reason := retrieveInactiveSubjectReason(test.subject, opts); reason != test.expected {
+			t.Errorf("Expected %q for %s %s, got %q", test.expected, test.subject.Kind, test.subject.Name, reason)
+		}
+	}
+
+	// Logs that don't go back far enough can't tell whether a subject is inactive
+	opts.AuditLogActivity.Since = now.AddDate(0, 0, -7)
+	if reason := retrieveInactiveSubjectReason(rbacv1.Subject{Kind: rbacv1.UserKind, Name: "carol"}, opts); reason != "" {
+		t.Errorf("Expected no reason when audit logs don't cover the inactivity window, got %q", reason)
+	}
+}
@@ -0,0 +1,141 @@
+package kor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+// retrieveCsrFinalCondition returns the Denied, Failed or Approved condition of a request that has
+// reached its final state, Approved only counting once the certificate has been issued
+func retrieveCsrFinalCondition(csr certificatesv1.CertificateSigningRequest) *certificatesv1.CertificateSigningRequestCondition {
+	var approved *certificatesv1.CertificateSigningRequestCondition
+	for i, condition := range csr.Status.Conditions {
+		if condition.Status == corev1.ConditionFalse {
+			continue
+		}
+		switch condition.Type {
+		case certificatesv1.CertificateDenied, certificatesv1.CertificateFailed:
+			return &csr.Status.Conditions[i]
+		case certificatesv1.CertificateApproved:
+			approved = &csr.Status.Conditions[i]
+		}
+	}
+
+	if approved != nil && len(csr.Status.Certificate) > 0 {
+		return approved
+	}
+	return nil
+}
+
+func retrieveCsrConditionTime(csr certificatesv1.CertificateSigningRequest, condition certificatesv1.CertificateSigningRequestCondition) time.Time {
+	switch {
+	case !condition.LastUpdateTime.IsZero():
+		return condition.LastUpdateTime.Time
+	case !condition.LastTransitionTime.IsZero():
+		return condition.LastTransitionTime.Time
+	default:
+		return csr.CreationTimestamp.Time
+	}
+}
+
+func processCertificateSigningRequests(clientset kubernetes.Interface, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+	csrs, err := clientset.CertificatesV1().CertificateSigningRequests().List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
+	if err != nil {
+		return nil, err
+	}
+
+	var unusedCsrs []ResourceInfo
+	now := time.Now()
+	threshold := opts.CsrThreshold
+
+	for _, csr := range csrs.Items {
+		// Skip resources with ownerReferences if the general flag is set
+		if filterOpts.IgnoreOwnerReferences && len(csr.OwnerReferences) > 0 {
+			continue
+		}
+
+		if pass, _ := filter.SetObject(&csr).Run(filterOpts); pass {
+			continue
+		}
+
+		if csr.Labels["kor/used"] == "false" {
+			unusedCsrs = append(unusedCsrs, ResourceInfo{Name: csr.Name, Reason: unusedLabelReason})
+			continue
+		}
+
+		condition := retrieveCsrFinalCondition(csr)
+		if condition == nil {
+			continue
+		}
+
+		age := now.Sub(retrieveCsrConditionTime(csr, *condition))
+		if age < threshold {
+			continue
+		}
+
+		var reason string
+		switch condition.Type {
+		case certificatesv1.CertificateDenied:
+			reason = fmt.Sprintf("Denied %s ago", duration.HumanDuration(age))
+		case certificatesv1.CertificateFailed:
+			reason = fmt.Sprintf("Failed %s ago", duration.HumanDuration(age))
+		default:
+			reason = fmt.Sprintf("Approved and issued %s ago", duration.HumanDuration(age))
+		}
+		unusedCsrs = append(unusedCsrs, ResourceInfo{Name: csr.Name, Reason: reason})
+	}
+
+	return unusedCsrs, nil
+}
+
+func GetUnusedCertificateSigningRequests(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
+	resources := make(map[string]map[string][]ResourceInfo)
+	diff, err := processCertificateSigningRequests(clientset, filterOpts, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to process certificateSigningRequests: %v\n", err)
+	}
+	if opts.DeleteFlag {
+		if diff, err = DeleteResource(diff, clientset, "", "CertificateSigningRequest", opts.NoInteractive); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to delete CertificateSigningRequest %s: %v\n", diff, err)
+		}
+	}
+	switch opts.GroupBy {
+	case "namespace":
+		resources[""] = make(map[string][]ResourceInfo)
+		resources[""]["CertificateSigningRequest"] = diff
+	case "resource":
+		appendResources(resources, "CertificateSigningRequest", "", diff)
+	}
+
+	var outputBuffer bytes.Buffer
+	var jsonResponse []byte
+	switch outputFormat {
+	case "table":
+		outputBuffer = FormatOutput(resources, opts)
+	case "json", "yaml":
+		var err error
+		if jsonResponse, err = json.MarshalIndent(resources, "", "  "); err != nil {
+			return "", err
+		}
+	}
+
+	unusedCsrs, err := unusedResourceFormatter(outputFormat, outputBuffer, opts, jsonResponse)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+	}
+
+	return unusedCsrs, nil
+}
@@ -0,0 +1,69 @@
+package kor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func createTestCsr(name string, conditionType certificatesv1.RequestConditionType, updated time.Time, issued bool) *certificatesv1.CertificateSigningRequest {
+	csr := &certificatesv1.CertificateSigningRequest{
+		ObjectMeta: v1.ObjectMeta{Name: name},
+	}
+	if conditionType != "" {
+		csr.Status.Conditions = []certificatesv1.CertificateSigningRequestCondition{
+			{Type: conditionType, Status: corev1.ConditionTrue, LastUpdateTime: v1.NewTime(updated)},
+		}
+	}
+	if issued {
+		csr.Status.Certificate = []byte("certificate")
+	}
+	return csr
+}
+
+func TestProcessCertificateSigningRequests(t *testing.T) {
+	clientset := fake.NewClientset()
+	old := time.Now().Add(-72 * time.Hour)
+
+	csrs := []*certificatesv1.CertificateSigningRequest{
+		createTestCsr("pending", "", old, false),
+		createTestCsr("denied", certificatesv1.CertificateDenied, old, false),
+		createTestCsr("failed", certificatesv1.CertificateFailed, old, false),
+		createTestCsr("issued", certificatesv1.CertificateApproved, old, true),
+		createTestCsr("approved-not-issued", certificatesv1.CertificateApproved, old, false),
+		createTestCsr("recently-issued", certificatesv1.CertificateApproved, time.Now(), true),
+	}
+	for _, csr := range csrs {
+		if _, err := clientset.CertificatesV1().CertificateSigningRequests().Create(context.TODO(), csr, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake certificateSigningRequest: %v", err)
+		}
+	}
+
+	unusedCsrs, err := processCertificateSigningRequests(clientset, &filters.Options{}, common.NewOpts())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "denied", Reason: "Denied 3d ago"},
+		{Name: "failed", Reason: "Failed 3d ago"},
+		{Name: "issued", Reason: "Approved and issued 3d ago"},
+	}
+
+	if len(unusedCsrs) != len(expected) {
+		t.Fatalf("Expected %d unused certificateSigningRequests, got %d: %v", len(expected), len(unusedCsrs), unusedCsrs)
+	}
+	for i, csr := range unusedCsrs {
+		if csr != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], csr)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package kor
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// clusterCache holds the cluster-wide lookups needed to process a namespace,
+// so that commands going through every namespace do each of them once per run
+type clusterCache struct {
+	apiResourceIndex    *apiResourceIndex
+	namespaces          []corev1.Namespace
+	namespaceNames      map[string]bool
+	istioHosts          map[string]bool
+	istioRootNamespace  string
+	secretSources       *secretReferenceSources
+	clusterRoles        []rbacv1.ClusterRole
+	clusterRoleBindings []rbacv1.ClusterRoleBinding
+	// legacyTokenTrackingSince is nil until retrieved, and the zero time when legacy tokens aren't tracked
+	legacyTokenTrackingSince *time.Time
+}
+
+func newClusterCache() *clusterCache {
+	return &clusterCache{}
+}
+
+func (c *clusterCache) retrieveAPIResourceIndex(clientset kubernetes.Interface) (*apiResourceIndex, error) {
+	if c.apiResourceIndex == nil {
+		index, err := retrieveAPIResourceIndex(clientset.Discovery())
+		if err != nil {
+			return nil, err
+		}
+		c.apiResourceIndex = index
+	}
+	return c.apiResourceIndex, nil
+}
+
+func (c *clusterCache) retrieveNamespaces(clientset kubernetes.Interface) ([]corev1.Namespace, error) {
+	if c.namespaces == nil {
+		namespaces, err := clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		c.namespaces = namespaces.Items
+	}
+	return c.namespaces, nil
+}
+
+func (c *clusterCache) retrieveNamespaceNames(clientset kubernetes.Interface) (map[string]bool, error) {
+	if c.namespaceNames == nil {
+		namespaces, err := c.retrieveNamespaces(clientset)
+		if err != nil {
+			return nil, err
+		}
+		c.namespaceNames = make(map[string]bool, len(namespaces))
+		for _, namespace := range namespaces {
+			c.namespaceNames[namespace.Name] = true
+		}
+	}
+	return c.namespaceNames, nil
+}
+
+func (c *clusterCache) retrieveClusterRoles(clientset kubernetes.Interface) ([]rbacv1.ClusterRole, error) {
+	if c.clusterRoles == nil {
+		clusterRoles, err := clientset.RbacV1().ClusterRoles().List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		c.clusterRoles = clusterRoles.Items
+	}
+	return c.clusterRoles, nil
+}
+
+func (c *clusterCache) retrieveClusterRoleBindings(clientset kubernetes.Interface) ([]rbacv1.ClusterRoleBinding, error) {
+	if c.clusterRoleBindings == nil {
+		clusterRoleBindings, err := clientset.RbacV1().ClusterRoleBindings().List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		c.clusterRoleBindings = clusterRoleBindings.Items
+	}
+	return c.clusterRoleBindings, nil
+}
+
+func (c *clusterCache) retrieveIstioHosts(clientset kubernetes.Interface, dynamicClient dynamic.Interface) (map[string]bool, error) {
+	if c.istioHosts == nil {
+		serviceEntryGVR, err := retrieveIstioGVR(clientset, c, serviceEntryResource)
+		if err != nil {
+			return nil, err
+		}
+		istioHosts, err := retrieveIstioHosts(clientset, dynamicClient, serviceEntryGVR)
+		if err != nil {
+			return nil, err
+		}
+		c.istioHosts = istioHosts
+	}
+	return c.istioHosts, nil
+}
+
+func (c *clusterCache) retrieveIstioRootNamespace(clientset kubernetes.Interface) (string, error) {
+	if c.istioRootNamespace == "" {
+		rootNamespace, err := retrieveIstioRootNamespace(clientset)
+		if err != nil {
+			return "", err
+		}
+		c.istioRootNamespace = rootNamespace
+	}
+	return c.istioRootNamespace, nil
+}
+
+func (c *clusterCache) retrieveSecretReferenceSources(clientset kubernetes.Interface, dynamicClient dynamic.Interface) (*secretReferenceSources, error) {
+	if c.secretSources == nil {
+		sources, err := retrieveSecretReferenceSources(clientset, dynamicClient)
+		if err != nil {
+			return nil, err
+		}
+		c.secretSources = sources
+	}
+	return c.secretSources, nil
+}
+
+func (c *clusterCache) retrieveLegacyTokenTrackingSince(clientset kubernetes.Interface) (time.Time, error) {
+	if c.legacyTokenTrackingSince == nil {
+		since, err := retrieveLegacyTokenTrackingSince(clientset)
+		if err != nil {
+			return time.Time{}, err
+		}
+		c.legacyTokenTrackingSince = &since
+	}
+	return *c.legacyTokenTrackingSince, nil
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	v1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -43,7 +44,7 @@ func validateClusterRoleReference(crb v1.ClusterRoleBinding, clusterRoleNames ma
 	return nil
 }
 
-func processClusterRoleBindings(clientset kubernetes.Interface, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+func processClusterRoleBindings(clientset kubernetes.Interface, cache *clusterCache, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
 	clusterRoleBindingsList, err := clientset.RbacV1().ClusterRoleBindings().List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
 	if err != nil {
 		return nil, err
@@ -59,7 +60,13 @@ func processClusterRoleBindings(clientset kubernetes.Interface, filterOpts *filt
 		return nil, err
 	}
 
+	namespaceNames, err := cache.retrieveNamespaceNames(clientset)
+	if err != nil {
+		return nil, err
+	}
+
 	var unusedClusterRoleBindingNames []ResourceInfo
+	var staleClusterRoleBindings []ResourceInfo
 
 	for _, crb := range clusterRoleBindingsList.Items {
 		// Skip resources with ownerReferences if the general flag is set
@@ -92,6 +99,19 @@ func processClusterRoleBindings(clientset kubernetes.Interface, filterOpts *filt
 		// Check if ClusterRoleBinding uses valid subjects (ServiceAccounts and/or Users/Groups)
 		if !isUsingValidServiceAccountClusterScoped(crb.Subjects, clientset) {
 			unusedClusterRoleBindingNames = append(unusedClusterRoleBindingNames, ResourceInfo{Name: crb.Name, Reason: "ClusterRoleBinding references a non-existing ServiceAccount"})
+			continue
+		}
+
+		staleSubjects, fromAuditLog := retrieveStaleSubjects(crb.Subjects, "", namespaceNames, opts)
+		if len(staleSubjects) == 0 {
+			continue
+		}
+		if fromAuditLog && len(staleSubjects) == len(crb.Subjects) {
+			staleClusterRoleBindings = append(staleClusterRoleBindings, ResourceInfo{Name: crb.Name, Reason: "ClusterRoleBinding has no active subjects: " + strings.Join(staleSubjects, ", "), ReportOnly: true})
+		} else if len(staleSubjects) == len(crb.Subjects) {
+			unusedClusterRoleBindingNames = append(unusedClusterRoleBindingNames, ResourceInfo{Name: crb.Name, Reason: "ClusterRoleBinding has no active subjects: " + strings.Join(staleSubjects, ", ")})
+		} else {
+			staleClusterRoleBindings = append(staleClusterRoleBindings, ResourceInfo{Name: crb.Name, Reason: "ClusterRoleBinding has stale subjects: " + strings.Join(staleSubjects, ", "), ReportOnly: true})
 		}
 	}
 	if opts.DeleteFlag {
@@ -99,12 +119,12 @@ func processClusterRoleBindings(clientset kubernetes.Interface, filterOpts *filt
 			fmt.Fprintf(os.Stderr, "Failed to delete ClusterRoleBinding %s: %v\n", unusedClusterRoleBindingNames, err)
 		}
 	}
-	return unusedClusterRoleBindingNames, nil
+	return append(unusedClusterRoleBindingNames, staleClusterRoleBindings...), nil
 }
 
 func GetUnusedClusterRoleBindings(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
 	resources := make(map[string]map[string][]ResourceInfo)
-	diff, err := processClusterRoleBindings(clientset, filterOpts, opts)
+	diff, err := processClusterRoleBindings(clientset, newClusterCache(), filterOpts, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to process clusterrolebindings: %v\n", err)
 	}
@@ -166,7 +166,7 @@ func createTestClusterRoleBindings(t *testing.T) *fake.Clientset {
 func TestProcessClusterRoleBindings(t *testing.T) {
 	clientset := createTestClusterRoleBindings(t)
 
-	unusedClusterRoleBindings, err := processClusterRoleBindings(clientset, &filters.Options{}, common.Opts{})
+	unusedClusterRoleBindings, err := processClusterRoleBindings(clientset, newClusterCache(), &filters.Options{}, common.Opts{})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -255,7 +255,7 @@ func TestProcessClusterRoleBindingsWithMixedSubjects(t *testing.T) {
 		t.Fatalf("Error creating ClusterRoleBinding: %v", err)
 	}
 
-	unusedClusterRoleBindings, err := processClusterRoleBindings(clientset, &filters.Options{}, common.Opts{})
+	unusedClusterRoleBindings, err := processClusterRoleBindings(clientset, newClusterCache(), &filters.Options{}, common.Opts{})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -11,6 +11,7 @@ import (
 
 	v1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
 
@@ -151,17 +152,51 @@ func retrieveClusterRoleNames(clientset kubernetes.Interface, filterOpts *filter
 	return names, unusedClusterRoles, nil
 }
 
-func processClusterRoles(clientset kubernetes.Interface, filterOpts *filters.Options) ([]ResourceInfo, error) {
-	usedClusterRoles, err := retrieveUsedClusterRoles(clientset, filterOpts)
+// retrieveDeadClusterRoles reports the named ClusterRoles whose rules reference resources or objects that don't exist.
+// Aggregated ClusterRoles are skipped since their rules belong to the aggregated roles.
+func retrieveDeadClusterRoles(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, names []string) ([]ResourceInfo, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	clusterRoles, err := cache.retrieveClusterRoles(clientset)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := cache.retrieveAPIResourceIndex(clientset)
 	if err != nil {
 		return nil, err
 	}
 
+	var deadClusterRoles []ResourceInfo
+	for _, clusterRole := range clusterRoles {
+		if clusterRole.AggregationRule != nil || !slices.Contains(names, clusterRole.Name) {
+			continue
+		}
+		reason, err := retrieveDeadRulesReason("ClusterRole", clusterRole.Rules, index, dynamicClient, "")
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			deadClusterRoles = append(deadClusterRoles, ResourceInfo{Name: clusterRole.Name, Reason: reason, ReportOnly: true})
+		}
+	}
+	return deadClusterRoles, nil
+}
+
+// processClusterRoles returns the unused ClusterRoles, and the used ones with dead rules as report-only findings
+func processClusterRoles(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, filterOpts *filters.Options) ([]ResourceInfo, []ResourceInfo, error) {
+	usedClusterRoles, err := retrieveUsedClusterRoles(clientset, filterOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	usedClusterRoles = RemoveDuplicatesAndSort(usedClusterRoles)
 
 	clusterRoleNames, unusedClusterRoles, err := retrieveClusterRoleNames(clientset, filterOpts)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var diff []ResourceInfo
@@ -176,13 +211,19 @@ func processClusterRoles(clientset kubernetes.Interface, filterOpts *filters.Opt
 		diff = append(diff, ResourceInfo{Name: name, Reason: reason})
 	}
 
-	return diff, nil
+	deadClusterRoles, err := retrieveDeadClusterRoles(clientset, dynamicClient, cache, slices.DeleteFunc(clusterRoleNames, func(name string) bool {
+		return !slices.Contains(usedClusterRoles, name)
+	}))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to check the rules of ClusterRoles: %v\n", err)
+	}
 
+	return diff, deadClusterRoles, nil
 }
 
-func GetUnusedClusterRoles(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
+func GetUnusedClusterRoles(filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
 	resources := make(map[string]map[string][]ResourceInfo)
-	diff, err := processClusterRoles(clientset, filterOpts)
+	diff, deadClusterRoles, err := processClusterRoles(clientset, dynamicClient, newClusterCache(), filterOpts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to process cluster role : %v\n", err)
 	}
@@ -191,6 +232,7 @@ func GetUnusedClusterRoles(filterOpts *filters.Options, clientset kubernetes.Int
 			fmt.Fprintf(os.Stderr, "Failed to delete clusterRole %s : %v\n", diff, err)
 		}
 	}
+	diff = append(diff, deadClusterRoles...)
 	switch opts.GroupBy {
 	case "namespace":
 		resources[""] = make(map[string][]ResourceInfo)
@@ -160,7 +160,7 @@ func TestRetrieveClusterRoleNames(t *testing.T) {
 func TestProcessClusterRoles(t *testing.T) {
 	clientset := createTestClusterRoles(t)
 
-	unusedClusterRoles, err := processClusterRoles(clientset, &filters.Options{})
+	unusedClusterRoles, _, err := processClusterRoles(clientset, nil, newClusterCache(), &filters.Options{})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -178,7 +178,7 @@ func TestProcessClusterRolesWithOwnerReferences(t *testing.T) {
 	clientset := createTestClusterRolesWithOwnerReferences(t)
 
 	// Test with --ignore-owner-references=false (default behavior)
-	unusedClusterRoles, err := processClusterRoles(clientset, &filters.Options{})
+	unusedClusterRoles, _, err := processClusterRoles(clientset, nil, newClusterCache(), &filters.Options{})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -189,7 +189,7 @@ func TestProcessClusterRolesWithOwnerReferences(t *testing.T) {
 	}
 
 	// Test with --ignore-owner-references=true
-	unusedClusterRoles, err = processClusterRoles(clientset, &filters.Options{IgnoreOwnerReferences: true})
+	unusedClusterRoles, _, err = processClusterRoles(clientset, nil, newClusterCache(), &filters.Options{IgnoreOwnerReferences: true})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -216,7 +216,7 @@ func TestGetUnusedClusterRolesStructured(t *testing.T) {
 		GroupBy:       "namespace",
 	}
 
-	output, err := GetUnusedClusterRoles(&filters.Options{}, clientset, "json", opts)
+	output, err := GetUnusedClusterRoles(&filters.Options{}, clientset, nil, "json", opts)
 	if err != nil {
 		t.Fatalf("Error calling GetUnusedRolesStructured: %v", err)
 	}
@@ -253,7 +253,7 @@ func TestGetUnusedClusterRolesStructuredWithOwnerReferences(t *testing.T) {
 	}
 
 	// Test with --ignore-owner-references=true
-	output, err := GetUnusedClusterRoles(&filters.Options{IgnoreOwnerReferences: true}, clientset, "json", opts)
+	output, err := GetUnusedClusterRoles(&filters.Options{IgnoreOwnerReferences: true}, clientset, nil, "json", opts)
 	if err != nil {
 		t.Fatalf("Error calling GetUnusedClusterRolesStructured: %v", err)
 	}
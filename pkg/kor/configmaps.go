@@ -6,9 +6,17 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"regexp"
+	"slices"
+	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
 
@@ -19,20 +27,125 @@ import (
 //go:embed exceptions/configmaps/configmaps.json
 var configMapsConfig []byte
 
-func retrieveUsedCM(clientset kubernetes.Interface, namespace string) ([]string, []string, []string, []string, []string, error) {
+// configMapConsumer is a component that uses ConfigMaps without referencing them in a pod spec,
+// either by discovering them through a label or annotation convention or by reading their name from a flag
+type configMapConsumer struct {
+	Name string
+	// Selector is a label selector matching the ConfigMaps the consumer discovers
+	Selector string
+	// Annotation is an annotation key marking ConfigMaps the consumer writes to or reads from
+	Annotation string
+	// Flags are container arguments holding a ConfigMap reference in the form [namespace/]name
+	Flags []string
+}
+
+//go:embed exceptions/configmaps/consumers.json
+var configMapConsumersConfig []byte
+
+func unmarshalConfigMapConsumers(data []byte) ([]configMapConsumer, error) {
+	var config struct {
+		Consumers []configMapConsumer `json:"consumers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return config.Consumers, nil
+}
+
+// discovers reports whether the consumer picks up the ConfigMap through its label or annotation convention
+func (c configMapConsumer) discovers(configMap corev1.ConfigMap) (bool, error) {
+	if c.Selector != "" {
+		selector, err := labels.Parse(c.Selector)
+		if err != nil {
+			return false, fmt.Errorf("invalid selector for consumer %s: %v", c.Name, err)
+		}
+		if selector.Matches(labels.Set(configMap.Labels)) {
+			return true, nil
+		}
+	}
+	if c.Annotation != "" {
+		if _, found := configMap.Annotations[c.Annotation]; found {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// containerVariablePattern matches the $(VAR) references expanded in container commands and arguments
+var containerVariablePattern = regexp.MustCompile(`\$\(([A-Za-z_][A-Za-z0-9_.-]*)\)`)
+
+// retrieveContainerVariables returns the env variables of a container whose value is known from the pod spec,
+// with the downward API namespace resolved to the namespace being processed
+func retrieveContainerVariables(container corev1.Container, namespace string) map[string]string {
+	variables := make(map[string]string)
+	for _, env := range container.Env {
+		switch {
+		case env.ValueFrom == nil:
+			// Values may reference the variables declared before them
+			variables[env.Name] = expandContainerVariables(env.Value, variables)
+		case env.ValueFrom.FieldRef != nil && env.ValueFrom.FieldRef.FieldPath == "metadata.namespace":
+			variables[env.Name] = namespace
+		}
+	}
+	return variables
+}
+
+// expandContainerVariables replaces the $(VAR) references to known variables, leaving the others as they are
+func expandContainerVariables(value string, variables map[string]string) string {
+	return containerVariablePattern.ReplaceAllStringFunc(value, func(reference string) string {
+		if expanded, found := variables[containerVariablePattern.FindStringSubmatch(reference)[1]]; found {
+			return expanded
+		}
+		return reference
+	})
+}
+
+// retrieveFlagReferencedNames returns the names referenced by the given flags in the container
+// arguments, both as "--flag=value" and "--flag value", once $(VAR) references to the container
+// env are expanded. References to other namespaces are ignored.
+func retrieveFlagReferencedNames(containers []corev1.Container, namespace string, flags []string) []string {
+	var names []string
+	for _, container := range containers {
+		variables := retrieveContainerVariables(container, namespace)
+		var args []string
+		for _, arg := range slices.Concat(container.Command, container.Args) {
+			args = append(args, expandContainerVariables(arg, variables))
+		}
+		for i, arg := range args {
+			flag, value, found := strings.Cut(arg, "=")
+			if !slices.Contains(flags, flag) {
+				continue
+			}
+			if !found {
+				if i+1 == len(args) {
+					continue
+				}
+				value = args[i+1]
+			}
+
+			if refNamespace, name, namespaced := strings.Cut(value, "/"); !namespaced {
+				names = append(names, value)
+			} else if refNamespace == namespace {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// retrieveUsedCM returns ConfigMaps referenced by the pod specs of pods and of the templates of workloads
+// that may currently run no pods, together with those referenced by the flags of known consumers
+func retrieveUsedCM(namespace string, podSpecs []workloadPodSpec, consumers []configMapConsumer) ([]string, []string, []string, []string, []string, []string) {
 	var volumesCM []string
 	var envCM []string
 	var envFromCM []string
 	var envFromContainerCM []string
 	var envFromInitContainerCM []string
+	var consumerCM []string
 
-	pods, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		return nil, nil, nil, nil, nil, err
-	}
-
-	for _, pod := range pods.Items {
-		for _, volume := range pod.Spec.Volumes {
+	for _, podSpec := range podSpecs {
+		spec := podSpec.Spec
+		for _, volume := range spec.Volumes {
 			if volume.ConfigMap != nil {
 				volumesCM = append(volumesCM, volume.ConfigMap.Name)
 			}
@@ -44,7 +157,7 @@ func retrieveUsedCM(clientset kubernetes.Interface, namespace string) ([]string,
 				}
 			}
 		}
-		for _, container := range pod.Spec.Containers {
+		for _, container := range spec.Containers {
 			for _, env := range container.Env {
 				if env.ValueFrom != nil && env.ValueFrom.ConfigMapKeyRef != nil {
 					envCM = append(envCM, env.ValueFrom.ConfigMapKeyRef.Name)
@@ -61,7 +174,7 @@ func retrieveUsedCM(clientset kubernetes.Interface, namespace string) ([]string,
 				}
 			}
 		}
-		for _, initContainer := range pod.Spec.InitContainers {
+		for _, initContainer := range spec.InitContainers {
 			for _, volume := range initContainer.VolumeMounts {
 				if volume.Name != "" && volume.MountPath != "" {
 					volumesCM = append(volumesCM, volume.Name)
@@ -78,12 +191,29 @@ func retrieveUsedCM(clientset kubernetes.Interface, namespace string) ([]string,
 				}
 			}
 		}
+
+		containers := slices.Concat(spec.InitContainers, spec.Containers)
+		for _, consumer := range consumers {
+			if len(consumer.Flags) > 0 {
+				consumerCM = append(consumerCM, retrieveFlagReferencedNames(containers, namespace, consumer.Flags)...)
+			}
+		}
 	}
 
-	return volumesCM, envCM, envFromCM, envFromContainerCM, envFromInitContainerCM, nil
+	return volumesCM, envCM, envFromCM, envFromContainerCM, envFromInitContainerCM, consumerCM
+}
+
+func isDiscoveredByConsumer(configMap corev1.ConfigMap, consumers []configMapConsumer) (bool, error) {
+	for _, consumer := range consumers {
+		discovered, err := consumer.discovers(configMap)
+		if err != nil || discovered {
+			return discovered, err
+		}
+	}
+	return false, nil
 }
 
-func retrieveConfigMapNames(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options) ([]string, []string, error) {
+func retrieveConfigMapNames(clientset kubernetes.Interface, namespace string, consumers []configMapConsumer, filterOpts *filters.Options) ([]string, []string, error) {
 	configmaps, err := clientset.CoreV1().ConfigMaps(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
 	if err != nil {
 		return nil, nil, err
@@ -107,16 +237,50 @@ func retrieveConfigMapNames(clientset kubernetes.Interface, namespace string, fi
 			continue
 		}
 
+		discovered, err := isDiscoveredByConsumer(configmap, consumers)
+		if err != nil {
+			return nil, nil, err
+		}
+		if discovered {
+			continue
+		}
+
 		names = append(names, configmap.Name)
 	}
 	return names, unusedConfigmapNames, nil
 }
 
-func processNamespaceCM(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
-	volumesCM, envCM, envFromCM, envFromContainerCM, envFromInitContainerCM, err := retrieveUsedCM(clientset, namespace)
+// retrieveUnusedConfigMapKeys reports the unreferenced keys of the given ConfigMaps that pods only consume key by key
+func retrieveUnusedConfigMapKeys(clientset kubernetes.Interface, namespace string, podSpecs []workloadPodSpec, names []string, otherUses []string) ([]ResourceInfo, error) {
+	configmaps, err := clientset.CoreV1().ConfigMaps(namespace).List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
+
+	objectKeys := make(map[string][]string)
+	for _, configmap := range configmaps.Items {
+		if !slices.Contains(names, configmap.Name) {
+			continue
+		}
+		keys := slices.Collect(maps.Keys(configmap.Data))
+		keys = append(keys, slices.Collect(maps.Keys(configmap.BinaryData))...)
+		slices.Sort(keys)
+		objectKeys[configmap.Name] = keys
+	}
+
+	return retrieveUnreferencedKeys(retrieveKeyUsage(podSpecs, "ConfigMap"), objectKeys, otherUses), nil
+}
+
+func processNamespaceCM(clientset kubernetes.Interface, dynamicClient dynamic.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+	podSpecs, err := retrieveWorkloadPodSpecs(clientset, namespace)
+	if err != nil {
+		return nil, err
+	}
+	consumers, err := unmarshalConfigMapConsumers(configMapConsumersConfig)
+	if err != nil {
+		return nil, err
+	}
+	volumesCM, envCM, envFromCM, envFromContainerCM, envFromInitContainerCM, consumerCM := retrieveUsedCM(namespace, podSpecs, consumers)
 	config, err := unmarshalConfig(configMapsConfig)
 	if err != nil {
 		return nil, err
@@ -127,8 +291,14 @@ func processNamespaceCM(clientset kubernetes.Interface, namespace string, filter
 	envFromCM = RemoveDuplicatesAndSort(envFromCM)
 	envFromContainerCM = RemoveDuplicatesAndSort(envFromContainerCM)
 	envFromInitContainerCM = RemoveDuplicatesAndSort(envFromInitContainerCM)
+	consumerCM = RemoveDuplicatesAndSort(consumerCM)
+
+	configMapNames, unusedConfigmapNames, err := retrieveConfigMapNames(clientset, namespace, consumers, filterOpts)
+	if err != nil {
+		return nil, err
+	}
 
-	configMapNames, unusedConfigmapNames, err := retrieveConfigMapNames(clientset, namespace, filterOpts)
+	ruleCM, err := retrieveRuleReferencedNames(dynamicClient, namespace, schema.GroupResource{Resource: "configmaps"}, opts.ReferenceRules)
 	if err != nil {
 		return nil, err
 	}
@@ -140,6 +310,8 @@ func processNamespaceCM(clientset kubernetes.Interface, namespace string, filter
 		envFromCM,
 		envFromContainerCM,
 		envFromInitContainerCM,
+		consumerCM,
+		ruleCM,
 	}
 
 	for _, slice := range slicesToAppend {
@@ -147,8 +319,9 @@ func processNamespaceCM(clientset kubernetes.Interface, namespace string, filter
 	}
 
 	var diff []ResourceInfo
+	var keyCandidates []string
 
-	for _, name := range CalculateResourceDifference(usedConfigMaps, configMapNames) {
+	for _, name := range configMapNames {
 		exceptionFound, err := isResourceException(name, namespace, config.ExceptionConfigMaps)
 		if err != nil {
 			return nil, err
@@ -157,6 +330,10 @@ func processNamespaceCM(clientset kubernetes.Interface, namespace string, filter
 		if exceptionFound {
 			continue
 		}
+		if slices.Contains(usedConfigMaps, name) {
+			keyCandidates = append(keyCandidates, name)
+			continue
+		}
 		reason := "ConfigMap is not used in any pod or container"
 		diff = append(diff, ResourceInfo{Name: name, Reason: reason})
 	}
@@ -172,13 +349,22 @@ func processNamespaceCM(clientset kubernetes.Interface, namespace string, filter
 		}
 	}
 
+	// Unused keys belong to ConfigMaps still in use, so they are reported after the deletion step
+	if opts.UnusedKeys {
+		keyDiff, err := retrieveUnusedConfigMapKeys(clientset, namespace, podSpecs, keyCandidates, slices.Concat(consumerCM, ruleCM))
+		if err != nil {
+			return nil, err
+		}
+		diff = append(diff, keyDiff...)
+	}
+
 	return diff, nil
 }
 
-func GetUnusedConfigmaps(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
+func GetUnusedConfigmaps(filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
 	resources := make(map[string]map[string][]ResourceInfo)
 	for _, namespace := range filterOpts.Namespaces(clientset) {
-		diff, err := processNamespaceCM(clientset, namespace, filterOpts, opts)
+		diff, err := processNamespaceCM(clientset, dynamicClient, namespace, filterOpts, opts)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to process namespace %s: %v\n", namespace, err)
 			continue
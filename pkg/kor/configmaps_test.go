@@ -146,7 +146,11 @@ func createTestConfigmaps(t *testing.T) *fake.Clientset {
 func TestRetrieveConfigMapNames(t *testing.T) {
 	clientset := createTestConfigmaps(t)
 
-	configMapNames, _, err := retrieveConfigMapNames(clientset, testNamespace, &filters.Options{})
+	consumers, err := unmarshalConfigMapConsumers(configMapConsumersConfig)
+	if err != nil {
+		t.Fatalf("Error loading configmap consumers: %v", err)
+	}
+	configMapNames, _, err := retrieveConfigMapNames(clientset, testNamespace, consumers, &filters.Options{})
 
 	if err != nil {
 		t.Fatalf("Error retrieving configmap names: %v", err)
@@ -166,7 +170,7 @@ func TestRetrieveConfigMapNames(t *testing.T) {
 func TestProcessNamespaceCM(t *testing.T) {
 	clientset := createTestConfigmaps(t)
 
-	diff, err := processNamespaceCM(clientset, testNamespace, &filters.Options{}, common.Opts{})
+	diff, err := processNamespaceCM(clientset, nil, testNamespace, &filters.Options{}, common.Opts{})
 	if err != nil {
 		t.Fatalf("Error processing namespace CM: %v", err)
 	}
@@ -183,12 +187,13 @@ func TestProcessNamespaceCM(t *testing.T) {
 func TestRetrieveUsedCM(t *testing.T) {
 	clientset := createTestConfigmaps(t)
 
-	volumesCM, envCM, envFromCM, envFromContainerCM, envFromInitContainerCM, err := retrieveUsedCM(clientset, testNamespace)
-
+	podSpecs, err := retrieveWorkloadPodSpecs(clientset, testNamespace)
 	if err != nil {
-		t.Fatalf("Error retrieving used ConfigMaps: %v", err)
+		t.Fatalf("Error retrieving pod specs: %v", err)
 	}
 
+	volumesCM, envCM, envFromCM, envFromContainerCM, envFromInitContainerCM, _ := retrieveUsedCM(testNamespace, podSpecs, nil)
+
 	expectedVolumesCM := []string{
 		"configmap-1",
 	}
@@ -217,6 +222,180 @@ func TestRetrieveUsedCM(t *testing.T) {
 	}
 }
 
+func TestProcessNamespaceCMWithTemplatesAndConsumers(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	configMaps := []*corev1.ConfigMap{
+		CreateTestConfigmap(testNamespace, "scaled-down-cm", AppLabels),
+		CreateTestConfigmap(testNamespace, "nginx-config", AppLabels),
+		CreateTestConfigmap(testNamespace, "tcp-services", AppLabels),
+		CreateTestConfigmap(testNamespace, "udp-services", AppLabels),
+		CreateTestConfigmap(testNamespace, "dashboard", map[string]string{"grafana_dashboard": "1"}),
+		CreateTestConfigmap(testNamespace, "other-namespace-cm", AppLabels),
+		CreateTestConfigmap(testNamespace, "unused-cm", AppLabels),
+	}
+	for _, configMap := range configMaps {
+		if _, err := clientset.CoreV1().ConfigMaps(testNamespace).Create(context.TODO(), configMap, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake configmap: %v", err)
+		}
+	}
+
+	scaledDown := CreateTestDeployment(testNamespace, "scaled-down", 0, AppLabels)
+	scaledDown.Spec.Template.Spec.Volumes = []corev1.Volume{
+		{
+			Name:         "config",
+			VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "scaled-down-cm"}}},
+		},
+	}
+	controller := CreateTestDeployment(testNamespace, "ingress-nginx-controller", 1, AppLabels)
+	controller.Spec.Template.Spec.Containers = []corev1.Container{
+		{
+			Name: "controller",
+			Args: []string{
+				"/nginx-ingress-controller",
+				"--configmap=$(POD_NAMESPACE)/nginx-config",
+				"--tcp-services-configmap", "tcp-services",
+				"--udp-services-configmap=$(UDP_NAMESPACE)/udp-services",
+				"--udp-services-configmap=other-namespace/other-namespace-cm",
+			},
+			Env: []corev1.EnvVar{
+				{Name: "POD_NAMESPACE", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"}}},
+				{Name: "UDP_NAMESPACE", Value: "$(POD_NAMESPACE)"},
+			},
+		},
+	}
+	for _, deployment := range []*appsv1.Deployment{scaledDown, controller} {
+		if _, err := clientset.AppsV1().Deployments(testNamespace).Create(context.TODO(), deployment, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake deployment: %v", err)
+		}
+	}
+
+	diff, err := processNamespaceCM(clientset, nil, testNamespace, &filters.Options{}, common.Opts{})
+	if err != nil {
+		t.Fatalf("Error processing namespace CM: %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "other-namespace-cm", Reason: "ConfigMap is not used in any pod or container"},
+		{Name: "unused-cm", Reason: "ConfigMap is not used in any pod or container"},
+	}
+	if !equalResourceInfoSlices(diff, expected) {
+		t.Errorf("Expected diff %v, got %v", expected, diff)
+	}
+}
+
+func TestProcessNamespaceCMUnusedKeys(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	keyed := CreateTestConfigmap(testNamespace, "keyed-cm", AppLabels)
+	keyed.Data = map[string]string{"log-level": "info", "timeout": "30s", "legacy-flag": "true"}
+	keyed.BinaryData = map[string][]byte{"legacy.bin": []byte("data")}
+	mounted := CreateTestConfigmap(testNamespace, "mounted-cm", AppLabels)
+	mounted.Data = map[string]string{"a": "1", "b": "2"}
+	for _, configMap := range []*corev1.ConfigMap{keyed, mounted} {
+		if _, err := clientset.CoreV1().ConfigMaps(testNamespace).Create(context.TODO(), configMap, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake configmap: %v", err)
+		}
+	}
+
+	pod := CreateTestPod(testNamespace, "pod-1", "", []corev1.Volume{
+		{
+			Name: "keyed",
+			VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "keyed-cm"},
+				Items:                []corev1.KeyToPath{{Key: "timeout", Path: "timeout"}},
+			}},
+		},
+		{
+			Name:         "mounted",
+			VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "mounted-cm"}}},
+		},
+	}, AppLabels)
+	pod.Spec.Containers = []corev1.Container{
+		{
+			Env: []corev1.EnvVar{
+				{
+					Name:      "LOG_LEVEL",
+					ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "keyed-cm"}, Key: "log-level"}},
+				},
+			},
+		},
+	}
+	if _, err := clientset.CoreV1().Pods(testNamespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake pod: %v", err)
+	}
+
+	diff, err := processNamespaceCM(clientset, nil, testNamespace, &filters.Options{}, common.Opts{UnusedKeys: true})
+	if err != nil {
+		t.Fatalf("Error processing namespace CM: %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "keyed-cm", Key: "legacy-flag", Reason: "Key is not referenced by any consumer", ReportOnly: true},
+		{Name: "keyed-cm", Key: "legacy.bin", Reason: "Key is not referenced by any consumer", ReportOnly: true},
+	}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("Expected diff %v, got %v", expected, diff)
+	}
+}
+
+func TestGetUnusedConfigmapsUnusedKeysStructured(t *testing.T) {
+	clientset := fake.NewClientset()
+	if _, err := clientset.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: testNamespace},
+	}, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating namespace %s: %v", testNamespace, err)
+	}
+
+	keyed := CreateTestConfigmap(testNamespace, "keyed-cm", AppLabels)
+	keyed.Data = map[string]string{"log-level": "info", "legacy-flag": "true"}
+	if _, err := clientset.CoreV1().ConfigMaps(testNamespace).Create(context.TODO(), keyed, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake configmap: %v", err)
+	}
+	pod := CreateTestPod(testNamespace, "pod-1", "", nil, AppLabels)
+	pod.Spec.Containers = []corev1.Container{
+		{
+			Env: []corev1.EnvVar{
+				{
+					Name:      "LOG_LEVEL",
+					ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "keyed-cm"}, Key: "log-level"}},
+				},
+			},
+		},
+	}
+	if _, err := clientset.CoreV1().Pods(testNamespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake pod: %v", err)
+	}
+
+	opts := common.Opts{GroupBy: "namespace", UnusedKeys: true, ShowReason: true}
+	output, err := GetUnusedConfigmaps(&filters.Options{}, clientset, nil, "json", opts)
+	if err != nil {
+		t.Fatalf("Error calling GetUnusedConfigmaps: %v", err)
+	}
+
+	var actualOutput map[string]map[string][]ResourceInfo
+	if err := json.Unmarshal([]byte(output), &actualOutput); err != nil {
+		t.Fatalf("Error unmarshaling actual output: %v", err)
+	}
+	expected := []ResourceInfo{{Name: "keyed-cm", Key: "legacy-flag", Reason: "Key is not referenced by any consumer"}}
+	if !reflect.DeepEqual(actualOutput[testNamespace]["ConfigMap"], expected) {
+		t.Errorf("Expected %v, got %v", expected, actualOutput[testNamespace]["ConfigMap"])
+	}
+
+	opts.ShowReason = false
+	output, err = GetUnusedConfigmaps(&filters.Options{}, clientset, nil, "json", opts)
+	if err != nil {
+		t.Fatalf("Error calling GetUnusedConfigmaps: %v", err)
+	}
+	var names map[string]map[string][]string
+	if err := json.Unmarshal([]byte(output), &names); err != nil {
+		t.Fatalf("Error unmarshaling actual output: %v", err)
+	}
+	if !reflect.DeepEqual(names[testNamespace]["ConfigMap"], []string{"keyed-cm[legacy-flag]"}) {
+		t.Errorf("Expected the key in brackets, got %v", names[testNamespace]["ConfigMap"])
+	}
+}
+
 func TestGetUnusedConfigmapsStructured(t *testing.T) {
 	clientset := createTestConfigmaps(t)
 
@@ -229,7 +408,7 @@ func TestGetUnusedConfigmapsStructured(t *testing.T) {
 		GroupBy:       "namespace",
 	}
 
-	output, err := GetUnusedConfigmaps(&filters.Options{}, clientset, "json", opts)
+	output, err := GetUnusedConfigmaps(&filters.Options{}, clientset, nil, "json", opts)
 	if err != nil {
 		t.Fatalf("Error calling GetUnusedConfigmapsStructured: %v", err)
 	}
@@ -290,7 +469,7 @@ func TestFilterOwnerReferencedConfigMaps(t *testing.T) {
 
 	// Test without filter - should return both
 	filterOptsNoSkip := &filters.Options{IgnoreOwnerReferences: false}
-	unusedWithoutFilter, err := processNamespaceCM(clientset, testNamespace, filterOptsNoSkip, common.Opts{})
+	unusedWithoutFilter, err := processNamespaceCM(clientset, nil, testNamespace, filterOptsNoSkip, common.Opts{})
 	if err != nil {
 		t.Fatalf("Error retrieving unused configmaps: %v", err)
 	}
@@ -301,7 +480,7 @@ func TestFilterOwnerReferencedConfigMaps(t *testing.T) {
 
 	// Test with filter - should return only standalone
 	filterOptsWithSkip := &filters.Options{IgnoreOwnerReferences: true}
-	unusedWithFilter, err := processNamespaceCM(clientset, testNamespace, filterOptsWithSkip, common.Opts{})
+	unusedWithFilter, err := processNamespaceCM(clientset, nil, testNamespace, filterOptsWithSkip, common.Opts{})
 	if err != nil {
 		t.Fatalf("Error retrieving unused configmaps: %v", err)
 	}
@@ -0,0 +1,191 @@
+package kor
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+// defaultRevisionHistoryLimit is applied by the API server when revisionHistoryLimit is unset
+const defaultRevisionHistoryLimit = 10
+
+type revisionOwner struct {
+	kind                 string
+	name                 string
+	revisionHistoryLimit int32
+	// activeRevisions are never part of the history, e.g. the current and update revisions of a StatefulSet
+	activeRevisions []string
+}
+
+func retrieveRevisionOwners(clientset kubernetes.Interface, namespace string) (map[types.UID]revisionOwner, error) {
+	owners := make(map[types.UID]revisionOwner)
+
+	daemonSets, err := clientset.AppsV1().DaemonSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, ds := range daemonSets.Items {
+		limit := int32(defaultRevisionHistoryLimit)
+		if ds.Spec.RevisionHistoryLimit != nil {
+			limit = *ds.Spec.RevisionHistoryLimit
+		}
+		owners[ds.UID] = revisionOwner{kind: "DaemonSet", name: ds.Name, revisionHistoryLimit: limit}
+	}
+
+	statefulSets, err := clientset.AppsV1().StatefulSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, sts := range statefulSets.Items {
+		limit := int32(defaultRevisionHistoryLimit)
+		if sts.Spec.RevisionHistoryLimit != nil {
+			limit = *sts.Spec.RevisionHistoryLimit
+		}
+		owners[sts.UID] = revisionOwner{
+			kind:                 "StatefulSet",
+			name:                 sts.Name,
+			revisionHistoryLimit: limit,
+			activeRevisions:      []string{sts.Status.CurrentRevision, sts.Status.UpdateRevision},
+		}
+	}
+
+	return owners, nil
+}
+
+// retrieveRevisionsBeyondHistoryLimit returns the oldest revisions that exceed the owner's revisionHistoryLimit.
+// The newest revision is always considered active since DaemonSets don't record it in their status.
+func retrieveRevisionsBeyondHistoryLimit(owner revisionOwner, revisions []appsv1.ControllerRevision) []appsv1.ControllerRevision {
+	slices.SortFunc(revisions, func(a, b appsv1.ControllerRevision) int {
+		return cmp.Compare(b.Revision, a.Revision)
+	})
+
+	var history []appsv1.ControllerRevision
+	for i, revision := range revisions {
+		if i == 0 || slices.Contains(owner.activeRevisions, revision.Name) {
+			continue
+		}
+		history = append(history, revision)
+	}
+
+	if len(history) <= int(owner.revisionHistoryLimit) {
+		return nil
+	}
+	return history[owner.revisionHistoryLimit:]
+}
+
+func processNamespaceControllerRevisions(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+	revisionList, err := clientset.AppsV1().ControllerRevisions(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
+	if err != nil {
+		return nil, err
+	}
+
+	owners, err := retrieveRevisionOwners(clientset, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var unusedRevisions []ResourceInfo
+	revisionsByOwner := make(map[types.UID][]appsv1.ControllerRevision)
+
+	for _, revision := range revisionList.Items {
+		if pass, _ := filter.SetObject(&revision).Run(filterOpts); pass {
+			continue
+		}
+
+		if revision.Labels["kor/used"] == "false" {
+			unusedRevisions = append(unusedRevisions, ResourceInfo{Name: revision.Name, Reason: unusedLabelReason})
+			continue
+		}
+
+		// Skip resources with ownerReferences if the general flag is set
+		if filterOpts.IgnoreOwnerReferences && len(revision.OwnerReferences) > 0 {
+			continue
+		}
+
+		if len(revision.OwnerReferences) == 0 {
+			unusedRevisions = append(unusedRevisions, ResourceInfo{Name: revision.Name, Reason: "ControllerRevision has no owner"})
+			continue
+		}
+
+		// Revisions of other controllers have their own retention rules
+		controller := metav1.GetControllerOf(&revision)
+		if controller == nil || (controller.Kind != "DaemonSet" && controller.Kind != "StatefulSet") {
+			continue
+		}
+		if _, exists := owners[controller.UID]; !exists {
+			reason := fmt.Sprintf("ControllerRevision has no owner, %s %s no longer exists", controller.Kind, controller.Name)
+			unusedRevisions = append(unusedRevisions, ResourceInfo{Name: revision.Name, Reason: reason})
+			continue
+		}
+		revisionsByOwner[controller.UID] = append(revisionsByOwner[controller.UID], revision)
+	}
+
+	ownerUIDs := slices.SortedFunc(maps.Keys(revisionsByOwner), func(a, b types.UID) int {
+		return cmp.Or(cmp.Compare(owners[a].kind, owners[b].kind), cmp.Compare(owners[a].name, owners[b].name))
+	})
+	for _, uid := range ownerUIDs {
+		owner := owners[uid]
+		for _, revision := range retrieveRevisionsBeyondHistoryLimit(owner, revisionsByOwner[uid]) {
+			reason := fmt.Sprintf("Beyond revisionHistoryLimit %d of %s %s", owner.revisionHistoryLimit, owner.kind, owner.name)
+			unusedRevisions = append(unusedRevisions, ResourceInfo{Name: revision.Name, Reason: reason})
+		}
+	}
+
+	if opts.DeleteFlag {
+		if unusedRevisions, err = DeleteResource(unusedRevisions, clientset, namespace, "ControllerRevision", opts.NoInteractive); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to delete ControllerRevision %s in namespace %s: %v\n", unusedRevisions, namespace, err)
+		}
+	}
+
+	return unusedRevisions, nil
+}
+
+func GetUnusedControllerRevisions(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
+	resources := make(map[string]map[string][]ResourceInfo)
+	for _, namespace := range filterOpts.Namespaces(clientset) {
+		diff, err := processNamespaceControllerRevisions(clientset, namespace, filterOpts, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to process namespace %s: %v\n", namespace, err)
+			continue
+		}
+		switch opts.GroupBy {
+		case "namespace":
+			resources[namespace] = make(map[string][]ResourceInfo)
+			resources[namespace]["ControllerRevision"] = diff
+		case "resource":
+			appendResources(resources, "ControllerRevision", namespace, diff)
+		}
+	}
+
+	var outputBuffer bytes.Buffer
+	var jsonResponse []byte
+	switch outputFormat {
+	case "table":
+		outputBuffer = FormatOutput(resources, opts)
+	case "json", "yaml":
+		var err error
+		if jsonResponse, err = json.MarshalIndent(resources, "", "  "); err != nil {
+			return "", err
+		}
+	}
+
+	unusedControllerRevisions, err := unusedResourceFormatter(outputFormat, outputBuffer, opts, jsonResponse)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+	}
+
+	return unusedControllerRevisions, nil
+}
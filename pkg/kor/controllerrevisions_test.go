@@ -0,0 +1,90 @@
+package kor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func createTestControllerRevision(name string, revision int64, owner *v1.OwnerReference) *appsv1.ControllerRevision {
+	controllerRevision := &appsv1.ControllerRevision{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Revision:   revision,
+	}
+	if owner != nil {
+		controllerRevision.OwnerReferences = []v1.OwnerReference{*owner}
+	}
+	return controllerRevision
+}
+
+func TestProcessNamespaceControllerRevisions(t *testing.T) {
+	clientset := fake.NewClientset()
+	dsHistoryLimit, stsHistoryLimit, isController := int32(1), int32(0), true
+
+	ds := CreateTestDaemonSet(testNamespace, "agent", AppLabels, &appsv1.DaemonSetStatus{})
+	ds.UID = types.UID("ds-uid")
+	ds.Spec.RevisionHistoryLimit = &dsHistoryLimit
+	if _, err := clientset.AppsV1().DaemonSets(testNamespace).Create(context.TODO(), ds, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake daemonSet: %v", err)
+	}
+
+	sts := CreateTestStatefulSet(testNamespace, "db", 1, AppLabels)
+	sts.UID = types.UID("sts-uid")
+	sts.Spec.RevisionHistoryLimit = &stsHistoryLimit
+	sts.Status.CurrentRevision = "db-1"
+	sts.Status.UpdateRevision = "db-2"
+	if _, err := clientset.AppsV1().StatefulSets(testNamespace).Create(context.TODO(), sts, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake statefulSet: %v", err)
+	}
+
+	dsOwner := &v1.OwnerReference{Kind: "DaemonSet", Name: "agent", UID: "ds-uid", Controller: &isController}
+	stsOwner := &v1.OwnerReference{Kind: "StatefulSet", Name: "db", UID: "sts-uid", Controller: &isController}
+	otherOwner := &v1.OwnerReference{Kind: "VirtualMachine", Name: "vm", UID: "vm-uid", Controller: &isController}
+
+	revisions := []*appsv1.ControllerRevision{
+		createTestControllerRevision("agent-1", 1, dsOwner),
+		createTestControllerRevision("agent-2", 2, dsOwner),
+		createTestControllerRevision("agent-3", 3, dsOwner),
+		createTestControllerRevision("db-0", 0, stsOwner),
+		createTestControllerRevision("db-1", 1, stsOwner),
+		createTestControllerRevision("db-2", 2, stsOwner),
+		createTestControllerRevision("vm-1", 1, otherOwner),
+		createTestControllerRevision("vm-2", 2, otherOwner),
+		createTestControllerRevision("gone-1", 1, &v1.OwnerReference{Kind: "StatefulSet", Name: "gone", UID: "gone-uid", Controller: &isController}),
+		createTestControllerRevision("standalone", 1, nil),
+	}
+	for _, revision := range revisions {
+		if _, err := clientset.AppsV1().ControllerRevisions(testNamespace).Create(context.TODO(), revision, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake controllerRevision: %v", err)
+		}
+	}
+
+	unusedRevisions, err := processNamespaceControllerRevisions(clientset, testNamespace, &filters.Options{}, common.Opts{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "gone-1", Reason: "ControllerRevision has no owner, StatefulSet gone no longer exists"},
+		{Name: "standalone", Reason: "ControllerRevision has no owner"},
+		{Name: "agent-1", Reason: fmt.Sprintf("Beyond revisionHistoryLimit %d of DaemonSet agent", 1)},
+		{Name: "db-0", Reason: fmt.Sprintf("Beyond revisionHistoryLimit %d of StatefulSet db", 0)},
+	}
+
+	if len(unusedRevisions) != len(expected) {
+		t.Fatalf("Expected %d unused controllerRevisions, got %d: %v", len(expected), len(unusedRevisions), unusedRevisions)
+	}
+	for i, revision := range unusedRevisions {
+		if revision != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], revision)
+		}
+	}
+}
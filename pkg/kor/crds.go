@@ -7,11 +7,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
 
 	"github.com/yonahd/kor/pkg/common"
@@ -21,6 +28,11 @@ import (
 //go:embed exceptions/crds/crds.json
 var crdsConfig []byte
 
+// crdControllerAnnotation names the Deployment ("namespace/name") reconciling a CRD's instances
+const crdControllerAnnotation = "kor/controller"
+
+var deploymentGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
 func processCrds(apiExtClient apiextensionsclientset.Interface, dynamicClient dynamic.Interface, filterOpts *filters.Options) ([]ResourceInfo, error) {
 	var unusedCRDs []ResourceInfo
 
@@ -99,7 +111,216 @@ func processCrds(apiExtClient apiextensionsclientset.Interface, dynamicClient dy
 	return unusedCRDs, nil
 }
 
-func GetUnusedCrds(_ *filters.Options, apiExtClient apiextensionsclientset.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
+// retrieveCrdVersion returns the version instances should be listed with, preferring the storage version
+func retrieveCrdVersion(crd apiextensionsv1.CustomResourceDefinition) string {
+	var version string
+	for _, v := range crd.Spec.Versions {
+		if !v.Served {
+			continue
+		}
+		if v.Storage {
+			return v.Name
+		}
+		if version == "" {
+			version = v.Name
+		}
+	}
+	return version
+}
+
+func retrieveAPIResource(discoveryClient discovery.DiscoveryInterface, cache map[string][]metav1.APIResource, apiVersion, kind string) (*metav1.APIResource, error) {
+	resources, ok := cache[apiVersion]
+	if !ok {
+		resourceList, err := discoveryClient.ServerResourcesForGroupVersion(apiVersion)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if resourceList != nil {
+			resources = resourceList.APIResources
+		}
+		cache[apiVersion] = resources
+	}
+
+	for _, resource := range resources {
+		if resource.Kind == kind && !strings.Contains(resource.Name, "/") {
+			return &resource, nil
+		}
+	}
+	return nil, nil
+}
+
+// ownerReferenceExists reports whether the object an owner reference points to still exists with the same UID
+func ownerReferenceExists(discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface, cache map[string][]metav1.APIResource, namespace string, owner metav1.OwnerReference) (bool, error) {
+	apiResource, err := retrieveAPIResource(discoveryClient, cache, owner.APIVersion, owner.Kind)
+	if err != nil {
+		return false, err
+	}
+	// The owner's API is no longer served, so the owner cannot exist
+	if apiResource == nil {
+		return false, nil
+	}
+
+	gv, err := schema.ParseGroupVersion(owner.APIVersion)
+	if err != nil {
+		return false, err
+	}
+	if !apiResource.Namespaced {
+		namespace = ""
+	}
+
+	ownerObject, err := dynamicClient.Resource(gv.WithResource(apiResource.Name)).Namespace(namespace).Get(context.TODO(), owner.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return ownerObject.GetUID() == owner.UID, nil
+}
+
+// retrieveMissingOwnerReason explains why an instance is orphaned, which is only the case once none of its owners exist
+func retrieveMissingOwnerReason(discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface, cache map[string][]metav1.APIResource, namespace string, owners []metav1.OwnerReference) (string, error) {
+	var reasons []string
+	for _, owner := range owners {
+		exists, err := ownerReferenceExists(discoveryClient, dynamicClient, cache, namespace, owner)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return "", nil
+		}
+		reasons = append(reasons, missingOwnerReason(owner))
+	}
+	return strings.Join(reasons, ", "), nil
+}
+
+func isCrdControllerRunning(dynamicClient dynamic.Interface, controller string) (bool, error) {
+	namespace, name, found := strings.Cut(controller, "/")
+	if !found {
+		return false, fmt.Errorf("invalid %s annotation %q, expected namespace/name", crdControllerAnnotation, controller)
+	}
+
+	deployment, err := dynamicClient.Resource(deploymentGVR).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	availableReplicas, _, err := unstructured.NestedInt64(deployment.Object, "status", "availableReplicas")
+	if err != nil {
+		return false, err
+	}
+	return availableReplicas > 0, nil
+}
+
+// processCrdInstances reports custom resource instances that are orphans, grouped by namespace and kind.group
+func processCrdInstances(clientset kubernetes.Interface, apiExtClient apiextensionsclientset.Interface, dynamicClient dynamic.Interface, filterOpts *filters.Options, opts common.Opts) (map[string]map[string][]ResourceInfo, error) {
+	crds, err := apiExtClient.ApiextensionsV1().CustomResourceDefinitions().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := unmarshalConfig(crdsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := filterOpts.Namespaces(clientset)
+	discoveryCache := make(map[string][]metav1.APIResource)
+	orphans := make(map[string]map[string][]ResourceInfo)
+
+	for _, crd := range crds.Items {
+		exceptionFound, err := isResourceException(crd.Name, crd.Namespace, config.ExceptionCrds)
+		if err != nil {
+			return nil, err
+		}
+		if exceptionFound {
+			continue
+		}
+
+		version := retrieveCrdVersion(crd)
+		if version == "" {
+			continue
+		}
+
+		controllerRunning := true
+		controller := crd.Annotations[crdControllerAnnotation]
+		if controller != "" {
+			if controllerRunning, err = isCrdControllerRunning(dynamicClient, controller); err != nil {
+				return nil, err
+			}
+		}
+
+		gvr := schema.GroupVersionResource{Group: crd.Spec.Group, Version: version, Resource: crd.Spec.Names.Plural}
+		instances, err := dynamicClient.Resource(gvr).Namespace(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
+		if err != nil {
+			// If we get an error querying the resource, skip this CRD
+			continue
+		}
+
+		// Kinds are only unique within their group
+		kind := schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}.String()
+		for _, instance := range instances.Items {
+			namespace := instance.GetNamespace()
+			if namespace != "" && !slices.Contains(namespaces, namespace) {
+				continue
+			}
+
+			if pass, _ := filter.SetObject(&instance).Run(filterOpts); pass {
+				continue
+			}
+
+			info := ResourceInfo{Name: instance.GetName()}
+			if instance.GetLabels()["kor/used"] == "false" {
+				info.Reason = unusedLabelReason
+			} else if info.Reason, err = retrieveMissingOwnerReason(apiExtClient.Discovery(), dynamicClient, discoveryCache, namespace, instance.GetOwnerReferences()); err != nil {
+				return nil, err
+			}
+
+			if info.Reason == "" && !controllerRunning {
+				info.Reason = fmt.Sprintf("Controller Deployment %s is not running", controller)
+				// Scaled to zero during maintenance or a rollout, the instances are still wanted
+				info.ReportOnly = true
+			}
+
+			// A lagging observedGeneration may just be a reconcile in progress
+			if info.Reason == "" {
+				observedGeneration, found, _ := unstructured.NestedInt64(instance.Object, "status", "observedGeneration")
+				if found && observedGeneration < instance.GetGeneration() {
+					info.Reason = fmt.Sprintf("Status observedGeneration %d is behind generation %d", observedGeneration, instance.GetGeneration())
+					info.ReportOnly = true
+				}
+			}
+
+			if info.Reason == "" {
+				continue
+			}
+
+			if orphans[namespace] == nil {
+				orphans[namespace] = make(map[string][]ResourceInfo)
+			}
+			orphans[namespace][kind] = append(orphans[namespace][kind], info)
+		}
+
+		if opts.DeleteFlag {
+			for namespace, kinds := range orphans {
+				if kinds[kind] == nil {
+					continue
+				}
+				if kinds[kind], err = DeleteDynamicResource(kinds[kind], dynamicClient, namespace, gvr, kind, opts.NoInteractive); err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to delete %s %s in namespace %s: %v\n", kind, kinds[kind], namespace, err)
+				}
+			}
+		}
+	}
+
+	return orphans, nil
+}
+
+func GetUnusedCrds(filterOpts *filters.Options, clientset kubernetes.Interface, apiExtClient apiextensionsclientset.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
 	resources := make(map[string]map[string][]ResourceInfo)
 	diff, err := processCrds(apiExtClient, dynamicClient, &filters.Options{})
 	if err != nil {
@@ -113,6 +334,26 @@ func GetUnusedCrds(_ *filters.Options, apiExtClient apiextensionsclientset.Inter
 		appendResources(resources, "Crd", "", diff)
 	}
 
+	if opts.CrdInstances {
+		instances, err := processCrdInstances(clientset, apiExtClient, dynamicClient, filterOpts, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to process custom resource instances: %v\n", err)
+		}
+		for namespace, kinds := range instances {
+			for kind, instanceDiff := range kinds {
+				switch opts.GroupBy {
+				case "namespace":
+					if resources[namespace] == nil {
+						resources[namespace] = make(map[string][]ResourceInfo)
+					}
+					resources[namespace][kind] = instanceDiff
+				case "resource":
+					appendResources(resources, kind, namespace, instanceDiff)
+				}
+			}
+		}
+	}
+
 	var outputBuffer bytes.Buffer
 	var jsonResponse []byte
 	switch outputFormat {
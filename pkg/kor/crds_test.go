@@ -1,8 +1,11 @@
 package kor
 
 import (
+	"context"
+	"reflect"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
@@ -10,8 +13,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 
+	"github.com/yonahd/kor/pkg/common"
 	"github.com/yonahd/kor/pkg/filters"
 )
 
@@ -154,6 +159,110 @@ func TestProcessCrds(t *testing.T) {
 	}
 }
 
+func TestProcessCrdInstances(t *testing.T) {
+	widgets := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "example.com",
+			Scope: apiextensionsv1.NamespaceScoped,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets", Singular: "widget", Kind: "Widget"},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true, Storage: true},
+			},
+		},
+	}
+	gadgets := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "gadgets.example.com",
+			Annotations: map[string]string{crdControllerAnnotation: "operators/gadget-operator"},
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "example.com",
+			Scope: apiextensionsv1.NamespaceScoped,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "gadgets", Singular: "gadget", Kind: "Gadget"},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true, Storage: true},
+			},
+		},
+	}
+	legacyWidgets := widgets.DeepCopy()
+	legacyWidgets.Name = "widgets.legacy.example.com"
+	legacyWidgets.Spec.Group = "legacy.example.com"
+	apiExtClient := apiextensionsfake.NewClientset(widgets, gadgets, legacyWidgets)
+	apiExtClient.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment", Namespaced: true}},
+		},
+	}
+
+	liveDeployment := CreateTestUnstructered("Deployment", "apps/v1", testNamespace, "live-deploy")
+	liveDeployment.SetUID("live-uid")
+	stoppedOperator := CreateTestUnstructered("Deployment", "apps/v1", "operators", "gadget-operator")
+
+	owned := CreateTestUnstructered("Widget", "example.com/v1", testNamespace, "owned")
+	owned.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "live-deploy", UID: "live-uid"}})
+	orphaned := CreateTestUnstructered("Widget", "example.com/v1", testNamespace, "orphaned")
+	orphaned.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "gone-deploy", UID: "gone-uid"}})
+	partiallyOwned := CreateTestUnstructered("Widget", "example.com/v1", testNamespace, "partially-owned")
+	partiallyOwned.SetOwnerReferences([]metav1.OwnerReference{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "gone-deploy", UID: "gone-uid"},
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "live-deploy", UID: "live-uid"},
+	})
+	excluded := CreateTestUnstructered("Widget", "example.com/v1", "excluded", "excluded")
+	excluded.SetLabels(map[string]string{"kor/used": "false"})
+	legacyWidget := CreateTestUnstructered("Widget", "legacy.example.com/v1", testNamespace, "legacy")
+	legacyWidget.SetLabels(map[string]string{"kor/used": "false"})
+	stale := CreateTestUnstructered("Widget", "example.com/v1", testNamespace, "stale")
+	stale.SetGeneration(3)
+	stale.Object["status"] = map[string]interface{}{"observedGeneration": int64(2)}
+	reconciled := CreateTestUnstructered("Widget", "example.com/v1", testNamespace, "reconciled")
+	reconciled.SetGeneration(2)
+	reconciled.Object["status"] = map[string]interface{}{"observedGeneration": int64(2)}
+	gadget := CreateTestUnstructered("Gadget", "example.com/v1", testNamespace, "gadget")
+
+	gvrToListKind := map[schema.GroupVersionResource]string{
+		{Group: "example.com", Version: "v1", Resource: "widgets"}:        "WidgetList",
+		{Group: "example.com", Version: "v1", Resource: "gadgets"}:        "GadgetList",
+		{Group: "legacy.example.com", Version: "v1", Resource: "widgets"}: "WidgetList",
+		deploymentGVR: "DeploymentList",
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), gvrToListKind,
+		liveDeployment, stoppedOperator, owned, orphaned, partiallyOwned, excluded, legacyWidget, stale, reconciled, gadget)
+
+	clientset := fake.NewClientset()
+	for _, name := range []string{testNamespace, "excluded"} {
+		if _, err := clientset.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating namespace %s: %v", name, err)
+		}
+	}
+
+	filterOpts := &filters.Options{ExcludeNamespaces: []string{"excluded"}}
+	orphans, err := processCrdInstances(clientset, apiExtClient, dynamicClient, filterOpts, common.Opts{})
+	if err != nil {
+		t.Fatalf("Error processing CRD instances: %v", err)
+	}
+
+	expected := map[string]map[string][]ResourceInfo{
+		testNamespace: {
+			"Widget.example.com": {
+				{Name: "orphaned", Reason: "Owner Deployment gone-deploy no longer exists"},
+				{Name: "stale", Reason: "Status observedGeneration 2 is behind generation 3", ReportOnly: true},
+			},
+			"Widget.legacy.example.com": {
+				{Name: "legacy", Reason: "Marked with unused label"},
+			},
+			"Gadget.example.com": {
+				{Name: "gadget", Reason: "Controller Deployment operators/gadget-operator is not running", ReportOnly: true},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(orphans, expected) {
+		t.Errorf("Expected %v, got %v", expected, orphans)
+	}
+}
+
 func init() {
 	// Internal types (REQUIRED for fake client)
 	if err := apiextensions.AddToScheme(clientgoscheme.Scheme); err != nil {
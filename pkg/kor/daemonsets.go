@@ -7,8 +7,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/duration"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/yonahd/kor/pkg/common"
@@ -18,6 +25,126 @@ import (
 //go:embed exceptions/daemonsets/daemonsets.json
 var daemonsetsConfig []byte
 
+// daemonSetDefaultTolerations are taint keys the DaemonSet controller tolerates for every DaemonSet pod
+var daemonSetDefaultTolerations = []string{
+	"node.kubernetes.io/not-ready",
+	"node.kubernetes.io/unreachable",
+	"node.kubernetes.io/disk-pressure",
+	"node.kubernetes.io/memory-pressure",
+	"node.kubernetes.io/pid-pressure",
+	"node.kubernetes.io/unschedulable",
+}
+
+func toleratesTaint(toleration corev1.Toleration, taint corev1.Taint) bool {
+	if toleration.Effect != "" && toleration.Effect != taint.Effect {
+		return false
+	}
+	// An empty key with the Exists operator tolerates every taint
+	if toleration.Key != "" && toleration.Key != taint.Key {
+		return false
+	}
+	switch toleration.Operator {
+	case corev1.TolerationOpExists:
+		return true
+	case corev1.TolerationOpEqual, "":
+		return toleration.Value == taint.Value
+	}
+	return false
+}
+
+func toleratesNode(spec corev1.PodSpec, node corev1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule || slices.Contains(daemonSetDefaultTolerations, taint.Key) {
+			continue
+		}
+		if taint.Key == "node.kubernetes.io/network-unavailable" && spec.HostNetwork {
+			continue
+		}
+		if !slices.ContainsFunc(spec.Tolerations, func(toleration corev1.Toleration) bool { return toleratesTaint(toleration, taint) }) {
+			return false
+		}
+	}
+	return true
+}
+
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+func nodeSelectorRequirementsMatch(requirements []corev1.NodeSelectorRequirement, set labels.Set) (bool, error) {
+	selector := labels.NewSelector()
+	for _, requirement := range requirements {
+		operator, ok := nodeSelectorOperators[requirement.Operator]
+		if !ok {
+			return false, fmt.Errorf("unsupported node selector operator %q", requirement.Operator)
+		}
+		parsed, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+		if err != nil {
+			return false, err
+		}
+		selector = selector.Add(*parsed)
+	}
+	return selector.Matches(set), nil
+}
+
+// matchesNodeAffinity evaluates the nodeSelector and the required node affinity of a pod spec against a node
+func matchesNodeAffinity(spec corev1.PodSpec, node corev1.Node) (bool, error) {
+	if !labels.SelectorFromSet(spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false, nil
+	}
+	if spec.Affinity == nil || spec.Affinity.NodeAffinity == nil || spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true, nil
+	}
+
+	nodeFields := labels.Set{"metadata.name": node.Name}
+	for _, term := range spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+			continue
+		}
+		labelsMatch, err := nodeSelectorRequirementsMatch(term.MatchExpressions, labels.Set(node.Labels))
+		if err != nil {
+			return false, err
+		}
+		fieldsMatch, err := nodeSelectorRequirementsMatch(term.MatchFields, nodeFields)
+		if err != nil {
+			return false, err
+		}
+		if labelsMatch && fieldsMatch {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// retrieveDaemonSetSchedulingReason explains why a DaemonSet has no pods scheduled
+func retrieveDaemonSetSchedulingReason(daemonSet appsv1.DaemonSet, nodes []corev1.Node) (string, error) {
+	spec := daemonSet.Spec.Template.Spec
+	var matchingNodes int
+	for _, node := range nodes {
+		matches, err := matchesNodeAffinity(spec, node)
+		if err != nil {
+			return "", err
+		}
+		if !matches {
+			continue
+		}
+		matchingNodes++
+		if toleratesNode(spec, node) {
+			return "DaemonSet has no replicas", nil
+		}
+	}
+
+	if matchingNodes == 0 {
+		return "DaemonSet node selector and affinity match no nodes", nil
+	}
+	return fmt.Sprintf("DaemonSet does not tolerate the taints of its %d matching nodes", matchingNodes), nil
+}
+
 func processNamespaceDaemonSets(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
 	daemonSetsList, err := clientset.AppsV1().DaemonSets(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
 	if err != nil {
@@ -29,7 +156,19 @@ func processNamespaceDaemonSets(clientset kubernetes.Interface, namespace string
 		return nil, err
 	}
 
+	nodes, err := clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
 	var daemonSetsWithoutReplicas []ResourceInfo
+	var failingDaemonSets []ResourceInfo
+	now := time.Now()
 
 	for _, daemonSet := range daemonSetsList.Items {
 		// Skip resources with ownerReferences if the general flag is set
@@ -57,8 +196,20 @@ func processNamespaceDaemonSets(clientset kubernetes.Interface, namespace string
 		}
 
 		if daemonSet.Status.CurrentNumberScheduled == 0 {
-			reason := "DaemonSet has no replicas"
+			reason, err := retrieveDaemonSetSchedulingReason(daemonSet, nodes.Items)
+			if err != nil {
+				return nil, err
+			}
 			daemonSetsWithoutReplicas = append(daemonSetsWithoutReplicas, ResourceInfo{Name: daemonSet.Name, Reason: reason})
+			continue
+		}
+
+		if daemonSet.Status.NumberAvailable > 0 || daemonSet.Status.NumberUnavailable == 0 {
+			continue
+		}
+		if age := now.Sub(retrieveUnavailableSince(&daemonSet, pods.Items)); age >= opts.UnavailableThreshold {
+			reason := fmt.Sprintf("All %d DaemonSet pods are failing for %s", daemonSet.Status.CurrentNumberScheduled, duration.HumanDuration(age))
+			failingDaemonSets = append(failingDaemonSets, ResourceInfo{Name: daemonSet.Name, Reason: reason, ReportOnly: true})
 		}
 	}
 	if opts.DeleteFlag {
@@ -66,7 +217,8 @@ func processNamespaceDaemonSets(clientset kubernetes.Interface, namespace string
 			fmt.Fprintf(os.Stderr, "Failed to delete DaemonSet %s in namespace %s: %v\n", daemonSetsWithoutReplicas, namespace, err)
 		}
 	}
-	return daemonSetsWithoutReplicas, nil
+
+	return append(daemonSetsWithoutReplicas, failingDaemonSets...), nil
 }
 
 func GetUnusedDaemonSets(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
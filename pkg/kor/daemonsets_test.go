@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"reflect"
 	"testing"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -150,6 +151,79 @@ func TestProcessNamespaceDaemonSetsWithOwnerReferences(t *testing.T) {
 	}
 }
 
+func TestProcessNamespaceDaemonSetsScheduling(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	gpuNode := CreateTestNode("gpu-node")
+	gpuNode.Labels = map[string]string{"accelerator": "gpu"}
+	gpuNode.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+	workerNode := CreateTestNode("worker-node")
+	workerNode.Labels = map[string]string{"kubernetes.io/os": "linux"}
+	workerNode.Spec.Taints = []corev1.Taint{{Key: "node.kubernetes.io/unschedulable", Effect: corev1.TaintEffectNoSchedule}}
+	for _, node := range []*corev1.Node{gpuNode, workerNode} {
+		if _, err := clientset.CoreV1().Nodes().Create(context.TODO(), node, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake Node: %v", err)
+		}
+	}
+
+	noMatch := CreateTestDaemonSet(testNamespace, "no-match", AppLabels, &appsv1.DaemonSetStatus{})
+	noMatch.Spec.Template.Spec.NodeSelector = map[string]string{"kubernetes.io/os": "windows"}
+
+	noMatchAffinity := CreateTestDaemonSet(testNamespace, "no-match-affinity", AppLabels, &appsv1.DaemonSetStatus{})
+	noMatchAffinity.Spec.Template.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+			{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "accelerator", Operator: corev1.NodeSelectorOpIn, Values: []string{"tpu"}}}},
+			{MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"other-node"}}}},
+		}},
+	}}
+
+	untolerated := CreateTestDaemonSet(testNamespace, "untolerated", AppLabels, &appsv1.DaemonSetStatus{})
+	untolerated.Spec.Template.Spec.NodeSelector = map[string]string{"accelerator": "gpu"}
+
+	tolerated := CreateTestDaemonSet(testNamespace, "tolerated", AppLabels, &appsv1.DaemonSetStatus{})
+	tolerated.Spec.Template.Spec.NodeSelector = map[string]string{"accelerator": "gpu"}
+	tolerated.Spec.Template.Spec.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}
+
+	failing := CreateTestDaemonSet(testNamespace, "failing", AppLabels, &appsv1.DaemonSetStatus{
+		CurrentNumberScheduled: 2,
+		NumberUnavailable:      2,
+	})
+	failing.CreationTimestamp = v1.NewTime(time.Now().Add(-48 * time.Hour))
+
+	recentlyFailing := CreateTestDaemonSet(testNamespace, "recently-failing", AppLabels, &appsv1.DaemonSetStatus{
+		CurrentNumberScheduled: 2,
+		NumberUnavailable:      2,
+	})
+	recentlyFailing.CreationTimestamp = v1.NewTime(time.Now().Add(-time.Hour))
+
+	for _, daemonSet := range []*appsv1.DaemonSet{noMatch, noMatchAffinity, untolerated, tolerated, failing, recentlyFailing} {
+		if _, err := clientset.AppsV1().DaemonSets(testNamespace).Create(context.TODO(), daemonSet, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake DaemonSet: %v", err)
+		}
+	}
+
+	unusedDaemonSets, err := processNamespaceDaemonSets(clientset, testNamespace, &filters.Options{}, common.Opts{DeleteFlag: true, NoInteractive: true, UnavailableThreshold: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "no-match-DELETED", Reason: "DaemonSet node selector and affinity match no nodes"},
+		{Name: "no-match-affinity-DELETED", Reason: "DaemonSet node selector and affinity match no nodes"},
+		{Name: "tolerated-DELETED", Reason: "DaemonSet has no replicas"},
+		{Name: "untolerated-DELETED", Reason: "DaemonSet does not tolerate the taints of its 1 matching nodes"},
+		{Name: "failing", Reason: "All 2 DaemonSet pods are failing for 2d", ReportOnly: true},
+	}
+	if !reflect.DeepEqual(expected, unusedDaemonSets) {
+		t.Errorf("Expected %v, got %v", expected, unusedDaemonSets)
+	}
+
+	// Failing DaemonSets are reported but never deleted
+	if _, err := clientset.AppsV1().DaemonSets(testNamespace).Get(context.TODO(), "failing", v1.GetOptions{}); err != nil {
+		t.Errorf("Expected failing DaemonSet to be kept, got %v", err)
+	}
+}
+
 func TestGetUnusedDaemonSetsStructured(t *testing.T) {
 	clientset := createTestDaemonSets(t)
 
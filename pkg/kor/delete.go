@@ -7,11 +7,16 @@ import (
 	"reflect"
 	"strings"
 
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	batchv1 "k8s.io/api/batch/v1"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
+	flowcontrolv1 "k8s.io/api/flowcontrol/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	nodev1 "k8s.io/api/node/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -93,6 +98,33 @@ func DeleteResourceCmd() map[string]func(clientset kubernetes.Interface, namespa
 		"PriorityClass": func(clientset kubernetes.Interface, namespace, name string) error {
 			return clientset.SchedulingV1().PriorityClasses().Delete(context.TODO(), name, metav1.DeleteOptions{})
 		},
+		"Lease": func(clientset kubernetes.Interface, namespace, name string) error {
+			return clientset.CoordinationV1().Leases(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+		},
+		"PodTemplate": func(clientset kubernetes.Interface, namespace, name string) error {
+			return clientset.CoreV1().PodTemplates(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+		},
+		"ControllerRevision": func(clientset kubernetes.Interface, namespace, name string) error {
+			return clientset.AppsV1().ControllerRevisions(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+		},
+		"RuntimeClass": func(clientset kubernetes.Interface, namespace, name string) error {
+			return clientset.NodeV1().RuntimeClasses().Delete(context.TODO(), name, metav1.DeleteOptions{})
+		},
+		"FlowSchema": func(clientset kubernetes.Interface, namespace, name string) error {
+			return clientset.FlowcontrolV1().FlowSchemas().Delete(context.TODO(), name, metav1.DeleteOptions{})
+		},
+		"PriorityLevelConfiguration": func(clientset kubernetes.Interface, namespace, name string) error {
+			return clientset.FlowcontrolV1().PriorityLevelConfigurations().Delete(context.TODO(), name, metav1.DeleteOptions{})
+		},
+		"ValidatingAdmissionPolicy": func(clientset kubernetes.Interface, namespace, name string) error {
+			return clientset.AdmissionregistrationV1().ValidatingAdmissionPolicies().Delete(context.TODO(), name, metav1.DeleteOptions{})
+		},
+		"ValidatingAdmissionPolicyBinding": func(clientset kubernetes.Interface, namespace, name string) error {
+			return clientset.AdmissionregistrationV1().ValidatingAdmissionPolicyBindings().Delete(context.TODO(), name, metav1.DeleteOptions{})
+		},
+		"CertificateSigningRequest": func(clientset kubernetes.Interface, namespace, name string) error {
+			return clientset.CertificatesV1().CertificateSigningRequests().Delete(context.TODO(), name, metav1.DeleteOptions{})
+		},
 	}
 
 	return deleteResourceApiMap
@@ -188,6 +220,24 @@ func updateResource(clientset kubernetes.Interface, namespace, resourceType stri
 		return clientset.RbacV1().RoleBindings(namespace).Update(context.TODO(), resource.(*rbacv1.RoleBinding), metav1.UpdateOptions{})
 	case "VolumeAttachment":
 		return clientset.StorageV1().VolumeAttachments().Update(context.TODO(), resource.(*storagev1.VolumeAttachment), metav1.UpdateOptions{})
+	case "Lease":
+		return clientset.CoordinationV1().Leases(namespace).Update(context.TODO(), resource.(*coordinationv1.Lease), metav1.UpdateOptions{})
+	case "PodTemplate":
+		return clientset.CoreV1().PodTemplates(namespace).Update(context.TODO(), resource.(*corev1.PodTemplate), metav1.UpdateOptions{})
+	case "ControllerRevision":
+		return clientset.AppsV1().ControllerRevisions(namespace).Update(context.TODO(), resource.(*appsv1.ControllerRevision), metav1.UpdateOptions{})
+	case "RuntimeClass":
+		return clientset.NodeV1().RuntimeClasses().Update(context.TODO(), resource.(*nodev1.RuntimeClass), metav1.UpdateOptions{})
+	case "FlowSchema":
+		return clientset.FlowcontrolV1().FlowSchemas().Update(context.TODO(), resource.(*flowcontrolv1.FlowSchema), metav1.UpdateOptions{})
+	case "PriorityLevelConfiguration":
+		return clientset.FlowcontrolV1().PriorityLevelConfigurations().Update(context.TODO(), resource.(*flowcontrolv1.PriorityLevelConfiguration), metav1.UpdateOptions{})
+	case "ValidatingAdmissionPolicy":
+		return clientset.AdmissionregistrationV1().ValidatingAdmissionPolicies().Update(context.TODO(), resource.(*admissionregistrationv1.ValidatingAdmissionPolicy), metav1.UpdateOptions{})
+	case "ValidatingAdmissionPolicyBinding":
+		return clientset.AdmissionregistrationV1().ValidatingAdmissionPolicyBindings().Update(context.TODO(), resource.(*admissionregistrationv1.ValidatingAdmissionPolicyBinding), metav1.UpdateOptions{})
+	case "CertificateSigningRequest":
+		return clientset.CertificatesV1().CertificateSigningRequests().Update(context.TODO(), resource.(*certificatesv1.CertificateSigningRequest), metav1.UpdateOptions{})
 	}
 	return nil, fmt.Errorf("resource type '%s' is not supported", resourceType)
 }
@@ -238,6 +288,24 @@ func getResource(clientset kubernetes.Interface, namespace, resourceType, resour
 		return clientset.RbacV1().RoleBindings(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
 	case "volumeAttachment":
 		return clientset.StorageV1().VolumeAttachments().Get(context.TODO(), resourceName, metav1.GetOptions{})
+	case "Lease":
+		return clientset.CoordinationV1().Leases(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
+	case "PodTemplate":
+		return clientset.CoreV1().PodTemplates(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
+	case "ControllerRevision":
+		return clientset.AppsV1().ControllerRevisions(namespace).Get(context.TODO(), resourceName, metav1.GetOptions{})
+	case "RuntimeClass":
+		return clientset.NodeV1().RuntimeClasses().Get(context.TODO(), resourceName, metav1.GetOptions{})
+	case "FlowSchema":
+		return clientset.FlowcontrolV1().FlowSchemas().Get(context.TODO(), resourceName, metav1.GetOptions{})
+	case "PriorityLevelConfiguration":
+		return clientset.FlowcontrolV1().PriorityLevelConfigurations().Get(context.TODO(), resourceName, metav1.GetOptions{})
+	case "ValidatingAdmissionPolicy":
+		return clientset.AdmissionregistrationV1().ValidatingAdmissionPolicies().Get(context.TODO(), resourceName, metav1.GetOptions{})
+	case "ValidatingAdmissionPolicyBinding":
+		return clientset.AdmissionregistrationV1().ValidatingAdmissionPolicyBindings().Get(context.TODO(), resourceName, metav1.GetOptions{})
+	case "CertificateSigningRequest":
+		return clientset.CertificatesV1().CertificateSigningRequests().Get(context.TODO(), resourceName, metav1.GetOptions{})
 	}
 	return nil, fmt.Errorf("resource type '%s' is not supported", resourceType)
 }
@@ -245,6 +313,10 @@ func getResource(clientset kubernetes.Interface, namespace, resourceType, resour
 func DeleteResourceWithFinalizer(resources []ResourceInfo, dynamicClient dynamic.Interface, namespace string, gvr schema.GroupVersionResource, noInteractive bool) ([]ResourceInfo, error) {
 	var remainingResources []ResourceInfo
 	for _, resource := range resources {
+		if resource.ReportOnly {
+			remainingResources = append(remainingResources, resource)
+			continue
+		}
 		if !noInteractive {
 			fmt.Printf("Do you want to delete %s %s in namespace %s? (Y/N): ", gvr.Resource, resource.Name, namespace)
 			var confirmation string
@@ -294,12 +366,19 @@ func DeleteResourceWithFinalizer(resources []ResourceInfo, dynamicClient dynamic
 	return remainingResources, nil
 }
 
-func DeleteResource(diff []ResourceInfo, clientset kubernetes.Interface, namespace, resourceType string, noInteractive bool) ([]ResourceInfo, error) {
+// promptAndDeleteResources asks for confirmation unless noInteractive is set, offers to flag declined resources
+// as in use and deletes the rest, returning the remaining diff with deleted resources suffixed by -DELETED.
+// A nil remove means deleting resourceType is not supported.
+func promptAndDeleteResources(diff []ResourceInfo, namespace, resourceType string, noInteractive bool, flag, remove func(name string) error) []ResourceInfo {
 	deletedDiff := []ResourceInfo{}
 
 	for _, resource := range diff {
-		deleteFunc, exists := DeleteResourceCmd()[resourceType]
-		if !exists {
+		if resource.ReportOnly {
+			deletedDiff = append(deletedDiff, resource)
+			continue
+		}
+
+		if remove == nil {
 			fmt.Printf("Resource type '%s' is not supported\n", resource.Name)
 			continue
 		}
@@ -325,17 +404,16 @@ func DeleteResource(diff []ResourceInfo, clientset kubernetes.Interface, namespa
 				}
 
 				if strings.ToLower(inUse) == "y" || strings.ToLower(inUse) == "yes" {
-					if err := FlagResource(clientset, namespace, resourceType, resource.Name); err != nil {
+					if err := flag(resource.Name); err != nil {
 						fmt.Fprintf(os.Stderr, "Failed to flag resource %s %s in namespace %s as In Use: %v\n", resourceType, resource.Name, namespace, err)
 					}
-					continue
 				}
 				continue
 			}
 		}
 
 		fmt.Printf("Deleting %s %s in namespace %s\n", resourceType, resource.Name, namespace)
-		if err := deleteFunc(clientset, namespace, resource.Name); err != nil {
+		if err := remove(resource.Name); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to delete %s %s in namespace %s: %v\n", resourceType, resource.Name, namespace, err)
 			continue
 		}
@@ -344,5 +422,30 @@ func DeleteResource(diff []ResourceInfo, clientset kubernetes.Interface, namespa
 		deletedDiff = append(deletedDiff, deletedResource)
 	}
 
-	return deletedDiff, nil
+	return deletedDiff
+}
+
+func DeleteResource(diff []ResourceInfo, clientset kubernetes.Interface, namespace, resourceType string, noInteractive bool) ([]ResourceInfo, error) {
+	flag := func(name string) error {
+		return FlagResource(clientset, namespace, resourceType, name)
+	}
+	var remove func(name string) error
+	if deleteFunc, exists := DeleteResourceCmd()[resourceType]; exists {
+		remove = func(name string) error {
+			return deleteFunc(clientset, namespace, name)
+		}
+	}
+
+	return promptAndDeleteResources(diff, namespace, resourceType, noInteractive, flag, remove), nil
+}
+
+func DeleteDynamicResource(diff []ResourceInfo, dynamicClient dynamic.Interface, namespace string, gvr schema.GroupVersionResource, resourceType string, noInteractive bool) ([]ResourceInfo, error) {
+	flag := func(name string) error {
+		return FlagDynamicResource(dynamicClient, namespace, gvr, name)
+	}
+	remove := func(name string) error {
+		return dynamicClient.Resource(gvr).Namespace(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	}
+
+	return promptAndDeleteResources(diff, namespace, resourceType, noInteractive, flag, remove), nil
 }
@@ -7,8 +7,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/yonahd/kor/pkg/common"
@@ -18,6 +23,30 @@ import (
 //go:embed exceptions/deployments/deployments.json
 var deploymentsConfig []byte
 
+// retrieveDeploymentIssue explains why a Deployment can't run its pods, or returns an empty string
+func retrieveDeploymentIssue(deployment appsv1.Deployment, objects *namespaceObjects, threshold time.Duration, now time.Time) string {
+	if missing := objects.missingReferences(deployment.Spec.Template.Spec); len(missing) > 0 {
+		return "Pod template references missing " + strings.Join(missing, ", ")
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			return "Rollout exceeded its progress deadline"
+		}
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type != appsv1.DeploymentAvailable || condition.Status != corev1.ConditionFalse || deployment.Status.AvailableReplicas > 0 {
+			continue
+		}
+		if age := now.Sub(condition.LastTransitionTime.Time); age >= threshold {
+			return fmt.Sprintf("No available replicas for %s", duration.HumanDuration(age))
+		}
+	}
+
+	return ""
+}
+
 func processNamespaceDeployments(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
 	deploymentsList, err := clientset.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
 	if err != nil {
@@ -29,7 +58,14 @@ func processNamespaceDeployments(clientset kubernetes.Interface, namespace strin
 		return nil, err
 	}
 
+	objects, err := retrieveNamespaceObjects(clientset, namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	var deploymentsWithoutReplicas []ResourceInfo
+	var unavailableDeployments []ResourceInfo
+	now := time.Now()
 
 	for _, deployment := range deploymentsList.Items {
 		// Skip resources with ownerReferences if the general flag is set
@@ -59,6 +95,11 @@ func processNamespaceDeployments(clientset kubernetes.Interface, namespace strin
 		if *deployment.Spec.Replicas == 0 {
 			reason := "Deployment has no replicas"
 			deploymentsWithoutReplicas = append(deploymentsWithoutReplicas, ResourceInfo{Name: deployment.Name, Reason: reason})
+			continue
+		}
+
+		if reason := retrieveDeploymentIssue(deployment, objects, opts.UnavailableThreshold, now); reason != "" {
+			unavailableDeployments = append(unavailableDeployments, ResourceInfo{Name: deployment.Name, Reason: reason, ReportOnly: true})
 		}
 	}
 	if opts.DeleteFlag {
@@ -67,7 +108,7 @@ func processNamespaceDeployments(clientset kubernetes.Interface, namespace strin
 		}
 	}
 
-	return deploymentsWithoutReplicas, nil
+	return append(deploymentsWithoutReplicas, unavailableDeployments...), nil
 }
 
 func GetUnusedDeployments(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"reflect"
 	"testing"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -72,6 +73,74 @@ func TestProcessNamespaceDeployments(t *testing.T) {
 	}
 }
 
+func TestProcessNamespaceDeploymentIssues(t *testing.T) {
+	clientset := fake.NewClientset()
+	threeDaysAgo := v1.NewTime(time.Now().Add(-72 * time.Hour))
+
+	stalled := CreateTestDeployment(testNamespace, "stalled", 3, AppLabels)
+	stalled.Status = appsv1.DeploymentStatus{
+		AvailableReplicas: 2,
+		Conditions: []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"},
+		},
+	}
+
+	unavailable := CreateTestDeployment(testNamespace, "unavailable", 1, AppLabels)
+	unavailable.Status = appsv1.DeploymentStatus{
+		Conditions: []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse, LastTransitionTime: threeDaysAgo},
+		},
+	}
+
+	recentlyUnavailable := CreateTestDeployment(testNamespace, "recently-unavailable", 1, AppLabels)
+	recentlyUnavailable.Status = appsv1.DeploymentStatus{
+		Conditions: []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse, LastTransitionTime: v1.Now()},
+		},
+	}
+
+	missingRefs := CreateTestDeployment(testNamespace, "missing-refs", 1, AppLabels)
+	optional := true
+	missingRefs.Spec.Template.Spec = corev1.PodSpec{
+		ServiceAccountName: "missing-sa",
+		Containers: []corev1.Container{
+			{
+				EnvFrom: []corev1.EnvFromSource{
+					{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "missing-cm"}}},
+					{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "optional-secret"}, Optional: &optional}},
+				},
+			},
+		},
+		Volumes: []corev1.Volume{
+			{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "missing-pvc"}}},
+			{Name: "tls", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "existing-secret"}}},
+		},
+	}
+
+	if _, err := clientset.CoreV1().Secrets(testNamespace).Create(context.TODO(), CreateTestSecret(testNamespace, "existing-secret", AppLabels), v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake secret: %v", err)
+	}
+	for _, deployment := range []*appsv1.Deployment{stalled, unavailable, recentlyUnavailable, missingRefs} {
+		if _, err := clientset.AppsV1().Deployments(testNamespace).Create(context.TODO(), deployment, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake deployment: %v", err)
+		}
+	}
+
+	issues, err := processNamespaceDeployments(clientset, testNamespace, &filters.Options{}, common.Opts{UnavailableThreshold: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "missing-refs", Reason: "Pod template references missing ServiceAccount missing-sa, ConfigMap missing-cm, PersistentVolumeClaim missing-pvc", ReportOnly: true},
+		{Name: "stalled", Reason: "Rollout exceeded its progress deadline", ReportOnly: true},
+		{Name: "unavailable", Reason: "No available replicas for 3d", ReportOnly: true},
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Expected %v, got %v", expected, issues)
+	}
+}
+
 func TestGetUnusedDeploymentsStructured(t *testing.T) {
 	clientset := createTestDeployments(t)
 
@@ -0,0 +1,107 @@
+package kor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	flowcontrolv1 "k8s.io/api/flowcontrol/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+// isAPFAutoUpdated reports whether the API server maintains the object as one of the mandatory or suggested
+// API Priority and Fairness configurations, in which case it would be recreated after deletion
+func isAPFAutoUpdated(object metav1.Object) bool {
+	return object.GetAnnotations()[flowcontrolv1.AutoUpdateAnnotationKey] == "true"
+}
+
+func processFlowSchemas(clientset kubernetes.Interface, filterOpts *filters.Options) ([]ResourceInfo, error) {
+	flowSchemas, err := clientset.FlowcontrolV1().FlowSchemas().List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
+	if err != nil {
+		return nil, err
+	}
+
+	plcs, err := clientset.FlowcontrolV1().PriorityLevelConfigurations().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	existingPriorityLevels := make(map[string]bool, len(plcs.Items))
+	for _, plc := range plcs.Items {
+		existingPriorityLevels[plc.Name] = true
+	}
+
+	var unusedFlowSchemas []ResourceInfo
+
+	for _, fs := range flowSchemas.Items {
+		// Skip resources with ownerReferences if the general flag is set
+		if filterOpts.IgnoreOwnerReferences && len(fs.OwnerReferences) > 0 {
+			continue
+		}
+
+		if pass, _ := filter.SetObject(&fs).Run(filterOpts); pass {
+			continue
+		}
+
+		if fs.Labels["kor/used"] == "false" {
+			unusedFlowSchemas = append(unusedFlowSchemas, ResourceInfo{Name: fs.Name, Reason: unusedLabelReason})
+			continue
+		}
+
+		if isAPFAutoUpdated(&fs) {
+			continue
+		}
+
+		if priorityLevel := fs.Spec.PriorityLevelConfiguration.Name; !existingPriorityLevels[priorityLevel] {
+			reason := fmt.Sprintf("References missing PriorityLevelConfiguration %s", priorityLevel)
+			unusedFlowSchemas = append(unusedFlowSchemas, ResourceInfo{Name: fs.Name, Reason: reason})
+		}
+	}
+
+	return unusedFlowSchemas, nil
+}
+
+func GetUnusedFlowSchemas(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
+	resources := make(map[string]map[string][]ResourceInfo)
+	diff, err := processFlowSchemas(clientset, filterOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to process flowSchemas: %v\n", err)
+	}
+	if opts.DeleteFlag {
+		if diff, err = DeleteResource(diff, clientset, "", "FlowSchema", opts.NoInteractive); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to delete FlowSchema %s: %v\n", diff, err)
+		}
+	}
+	switch opts.GroupBy {
+	case "namespace":
+		resources[""] = make(map[string][]ResourceInfo)
+		resources[""]["FlowSchema"] = diff
+	case "resource":
+		appendResources(resources, "FlowSchema", "", diff)
+	}
+
+	var outputBuffer bytes.Buffer
+	var jsonResponse []byte
+	switch outputFormat {
+	case "table":
+		outputBuffer = FormatOutput(resources, opts)
+	case "json", "yaml":
+		var err error
+		if jsonResponse, err = json.MarshalIndent(resources, "", "  "); err != nil {
+			return "", err
+		}
+	}
+
+	unusedFlowSchemas, err := unusedResourceFormatter(outputFormat, outputBuffer, opts, jsonResponse)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+	}
+
+	return unusedFlowSchemas, nil
+}
@@ -0,0 +1,76 @@
+package kor
+
+import (
+	"context"
+	"testing"
+
+	flowcontrolv1 "k8s.io/api/flowcontrol/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func createTestFlowSchema(name, priorityLevel string) *flowcontrolv1.FlowSchema {
+	return &flowcontrolv1.FlowSchema{
+		ObjectMeta: v1.ObjectMeta{Name: name},
+		Spec: flowcontrolv1.FlowSchemaSpec{
+			PriorityLevelConfiguration: flowcontrolv1.PriorityLevelConfigurationReference{Name: priorityLevel},
+		},
+	}
+}
+
+func createTestPriorityLevelConfiguration(name string) *flowcontrolv1.PriorityLevelConfiguration {
+	return &flowcontrolv1.PriorityLevelConfiguration{
+		ObjectMeta: v1.ObjectMeta{Name: name},
+		Spec:       flowcontrolv1.PriorityLevelConfigurationSpec{Type: flowcontrolv1.PriorityLevelEnablementLimited},
+	}
+}
+
+func createTestFlowControlResources(t *testing.T) *fake.Clientset {
+	clientset := fake.NewClientset()
+
+	autoUpdated := map[string]string{flowcontrolv1.AutoUpdateAnnotationKey: "true"}
+
+	suggestedPriorityLevel := createTestPriorityLevelConfiguration("workload-low")
+	suggestedPriorityLevel.Annotations = autoUpdated
+	priorityLevels := []*flowcontrolv1.PriorityLevelConfiguration{
+		createTestPriorityLevelConfiguration("batch"),
+		createTestPriorityLevelConfiguration("unreferenced"),
+		suggestedPriorityLevel,
+	}
+	for _, plc := range priorityLevels {
+		if _, err := clientset.FlowcontrolV1().PriorityLevelConfigurations().Create(context.TODO(), plc, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake priorityLevelConfiguration: %v", err)
+		}
+	}
+
+	suggestedFlowSchema := createTestFlowSchema("service-accounts", "removed-suggested-level")
+	suggestedFlowSchema.Annotations = autoUpdated
+	flowSchemas := []*flowcontrolv1.FlowSchema{
+		createTestFlowSchema("batch-jobs", "batch"),
+		createTestFlowSchema("dangling", "deleted-level"),
+		suggestedFlowSchema,
+	}
+	for _, fs := range flowSchemas {
+		if _, err := clientset.FlowcontrolV1().FlowSchemas().Create(context.TODO(), fs, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake flowSchema: %v", err)
+		}
+	}
+
+	return clientset
+}
+
+func TestProcessFlowSchemas(t *testing.T) {
+	clientset := createTestFlowControlResources(t)
+
+	unusedFlowSchemas, err := processFlowSchemas(clientset, &filters.Options{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := ResourceInfo{Name: "dangling", Reason: "References missing PriorityLevelConfiguration deleted-level"}
+	if len(unusedFlowSchemas) != 1 || unusedFlowSchemas[0] != expected {
+		t.Errorf("Expected %v, got %v", expected, unusedFlowSchemas)
+	}
+}
@@ -14,8 +14,28 @@ import (
 )
 
 type ResourceInfo struct {
-	Name   string `json:"name"`
+	Name string `json:"name"`
+	// Key is set on findings about a single ConfigMap or Secret key, Name is then the object holding it
+	Key    string `json:"key,omitempty"`
 	Reason string `json:"reason,omitempty"`
+	// ReportOnly marks findings about resources that are misconfigured or risky rather than unused.
+	// They are printed like any other finding, but the delete functions always keep them in place.
+	ReportOnly bool `json:"-"`
+}
+
+func (r ResourceInfo) String() string {
+	if r.Reason == "" {
+		return r.displayName()
+	}
+	return fmt.Sprintf("%s (%s)", r.displayName(), r.Reason)
+}
+
+// displayName is the name shown in tables and name lists, with the key of key findings in brackets
+func (r ResourceInfo) displayName() string {
+	if r.Key == "" {
+		return r.Name
+	}
+	return fmt.Sprintf("%s[%s]", r.Name, r.Key)
 }
 
 func getTableRow(index int, columns ...string) []string {
@@ -54,7 +74,7 @@ func unusedResourceFormatter(outputFormat string, outputBuffer bytes.Buffer, opt
 						if _, ok := namespaces[namespace]; !ok {
 							namespaces[namespace] = make(map[string][]string)
 						}
-						namespaces[namespace][resourceType] = append(namespaces[namespace][resourceType], info.Name)
+						namespaces[namespace][resourceType] = append(namespaces[namespace][resourceType], info.displayName())
 					}
 				}
 			}
@@ -112,7 +132,7 @@ func formatOutputForNamespace(namespace string, resources map[string][]ResourceI
 	var index int
 	for resourceType, diff := range resources {
 		for _, info := range diff {
-			row := getTableRow(index, resourceType, info.Name)
+			row := getTableRow(index, resourceType, info.displayName())
 			if opts.ShowReason && info.Reason != "" {
 				row = append(row, info.Reason)
 			}
@@ -146,7 +166,7 @@ func formatOutputForResource(resource string, resources map[string][]ResourceInf
 	var index int
 	for ns, infos := range resources {
 		for _, info := range infos {
-			row := getTableRow(index, ns, info.Name)
+			row := getTableRow(index, ns, info.displayName())
 			if opts.ShowReason && info.Reason != "" {
 				row = append(row, info.Reason)
 			}
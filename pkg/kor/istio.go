@@ -0,0 +1,399 @@
+package kor
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+const (
+	// Selectors of policies in the root namespace apply to workloads in every namespace. Unless set in the
+	// mesh config of the istio ConfigMap, the root namespace is the one Istio is installed in.
+	defaultIstioRootNamespace = "istio-system"
+	istioMeshConfigMap        = "istio"
+	// istioFallbackVersion is served by every Istio release kor supports, it's used when discovery doesn't list a resource
+	istioFallbackVersion = "v1beta1"
+	clusterDomainSuffix  = ".svc.cluster.local"
+
+	unresolvedVirtualServiceReason  = "VirtualService routes to hosts that resolve to no Service or ServiceEntry"
+	unresolvedDestinationRuleReason = "DestinationRule host matches no Service or ServiceEntry"
+	noPodSelectedBySidecarReason    = "Sidecar workloadSelector matches no pods"
+	noPodSelectedByEntryReason      = "ServiceEntry workloadSelector matches no pods"
+	noPodSelectedByAuthzReason      = "AuthorizationPolicy selector matches no pods"
+	noPodSelectedByPeerAuthnReason  = "PeerAuthentication selector matches no pods"
+)
+
+var (
+	virtualServiceResource      = schema.GroupResource{Group: "networking.istio.io", Resource: "virtualservices"}
+	destinationRuleResource     = schema.GroupResource{Group: "networking.istio.io", Resource: "destinationrules"}
+	serviceEntryResource        = schema.GroupResource{Group: "networking.istio.io", Resource: "serviceentries"}
+	sidecarResource             = schema.GroupResource{Group: "networking.istio.io", Resource: "sidecars"}
+	authorizationPolicyResource = schema.GroupResource{Group: "security.istio.io", Resource: "authorizationpolicies"}
+	peerAuthenticationResource  = schema.GroupResource{Group: "security.istio.io", Resource: "peerauthentications"}
+)
+
+type namespaceDynamicProcessor func(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error)
+
+// retrieveIstioGVR returns the preferred version the cluster serves for an Istio resource
+func retrieveIstioGVR(clientset kubernetes.Interface, cache *clusterCache, resource schema.GroupResource) (schema.GroupVersionResource, error) {
+	index, err := cache.retrieveAPIResourceIndex(clientset)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	if _, served := index.resources[resource.Group][resource.Resource]; served {
+		return resource.WithVersion(index.preferredVersions[resource.Group]), nil
+	}
+	return resource.WithVersion(istioFallbackVersion), nil
+}
+
+// retrieveIstioRootNamespace reads the root namespace from the mesh config of the istio ConfigMap
+func retrieveIstioRootNamespace(clientset kubernetes.Interface) (string, error) {
+	configMap, err := clientset.CoreV1().ConfigMaps(defaultIstioRootNamespace).Get(context.TODO(), istioMeshConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return defaultIstioRootNamespace, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var meshConfig struct {
+		RootNamespace string `json:"rootNamespace"`
+	}
+	if err := yaml.Unmarshal([]byte(configMap.Data["mesh"]), &meshConfig); err != nil {
+		return "", fmt.Errorf("failed to parse the mesh config of ConfigMap %s/%s: %v", defaultIstioRootNamespace, istioMeshConfigMap, err)
+	}
+	return cmp.Or(meshConfig.RootNamespace, defaultIstioRootNamespace), nil
+}
+
+// retrieveIstioHosts returns the FQDN of every Service in the cluster together with
+// the hosts declared by ServiceEntries, which is what Istio resolves rule hosts against
+func retrieveIstioHosts(clientset kubernetes.Interface, dynamicClient dynamic.Interface, serviceEntryGVR schema.GroupVersionResource) (map[string]bool, error) {
+	knownHosts := make(map[string]bool)
+
+	services, err := clientset.CoreV1().Services(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, svc := range services.Items {
+		knownHosts[svc.Name+"."+svc.Namespace+clusterDomainSuffix] = true
+	}
+
+	serviceEntries, err := dynamicClient.Resource(serviceEntryGVR).Namespace(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, se := range serviceEntries.Items {
+		hosts, _, err := unstructured.NestedStringSlice(se.Object, "spec", "hosts")
+		if err != nil {
+			return nil, err
+		}
+		for _, host := range hosts {
+			knownHosts[host] = true
+		}
+	}
+
+	return knownHosts, nil
+}
+
+// expandIstioHost turns a short host name into the FQDN Istio would use for it
+func expandIstioHost(host, namespace string) string {
+	switch strings.Count(host, ".") {
+	case 0:
+		return host + "." + namespace + clusterDomainSuffix
+	case 1:
+		return host + clusterDomainSuffix
+	}
+	if strings.HasSuffix(host, ".svc") {
+		return host + ".cluster.local"
+	}
+	return host
+}
+
+func isIstioHostResolved(host, namespace string, knownHosts map[string]bool) bool {
+	if host == "" {
+		return false
+	}
+
+	if strings.HasPrefix(host, "*") {
+		suffix := strings.TrimPrefix(host, "*")
+		for known := range knownHosts {
+			if strings.HasSuffix(known, suffix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if knownHosts[host] || knownHosts[expandIstioHost(host, namespace)] {
+		return true
+	}
+
+	// ServiceEntries may declare wildcard hosts
+	for known := range knownHosts {
+		if strings.HasPrefix(known, "*") && strings.HasSuffix(host, strings.TrimPrefix(known, "*")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func retrieveVirtualServiceDestinations(vs unstructured.Unstructured) []string {
+	var hosts []string
+	for _, routeType := range []string{"http", "tcp", "tls"} {
+		routes, _, _ := unstructured.NestedSlice(vs.Object, "spec", routeType)
+		for _, route := range routes {
+			routeMap, ok := route.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			destinations, _, _ := unstructured.NestedSlice(routeMap, "route")
+			for _, destination := range destinations {
+				destinationMap, ok := destination.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if host, found, _ := unstructured.NestedString(destinationMap, "destination", "host"); found {
+					hosts = append(hosts, host)
+				}
+			}
+			if host, found, _ := unstructured.NestedString(routeMap, "mirror", "host"); found {
+				hosts = append(hosts, host)
+			}
+		}
+	}
+	return hosts
+}
+
+// isAnyPodSelectedByIstioSelector checks the label map at the given path against the pods
+// in the namespace. A missing or empty selector applies to every workload in scope.
+func isAnyPodSelectedByIstioSelector(clientset kubernetes.Interface, namespace, rootNamespace string, resource unstructured.Unstructured, fields ...string) (bool, error) {
+	matchLabels, found, err := unstructured.NestedStringMap(resource.Object, fields...)
+	if err != nil {
+		return false, err
+	}
+	if !found || len(matchLabels) == 0 {
+		return true, nil
+	}
+
+	if namespace == rootNamespace {
+		namespace = metav1.NamespaceAll
+	}
+
+	pods, err := retrievePodsForSelector(clientset, namespace, &metav1.LabelSelector{MatchLabels: matchLabels})
+	if err != nil {
+		return false, err
+	}
+
+	return len(pods) > 0, nil
+}
+
+// retrieveIstioRoot returns the root namespace set with --istio-root-namespace, or else the one of the mesh config
+func retrieveIstioRoot(clientset kubernetes.Interface, cache *clusterCache, opts common.Opts) (string, error) {
+	if opts.IstioRootNamespace != "" {
+		return opts.IstioRootNamespace, nil
+	}
+	return cache.retrieveIstioRootNamespace(clientset)
+}
+
+func processNamespaceIstioResources(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, resource schema.GroupResource, resourceType, reason string, isUsed func(resource unstructured.Unstructured) (bool, error), filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+	gvr, err := retrieveIstioGVR(clientset, cache, resource)
+	if err != nil {
+		return nil, err
+	}
+	resourceList, err := dynamicClient.Resource(gvr).Namespace(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
+	if err != nil {
+		return nil, err
+	}
+
+	var diff []ResourceInfo
+
+	for _, resource := range resourceList.Items {
+		// Skip resources with ownerReferences if the general flag is set
+		if filterOpts.IgnoreOwnerReferences && len(resource.GetOwnerReferences()) > 0 {
+			continue
+		}
+
+		if pass, _ := filter.SetObject(&resource).Run(filterOpts); pass {
+			continue
+		}
+
+		if resource.GetLabels()["kor/used"] == "false" {
+			diff = append(diff, ResourceInfo{Name: resource.GetName(), Reason: unusedLabelReason})
+			continue
+		}
+
+		used, err := isUsed(resource)
+		if err != nil {
+			return nil, err
+		}
+		if !used {
+			diff = append(diff, ResourceInfo{Name: resource.GetName(), Reason: reason})
+		}
+	}
+
+	if opts.DeleteFlag {
+		if diff, err = DeleteDynamicResource(diff, dynamicClient, namespace, gvr, resourceType, opts.NoInteractive); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to delete %s %s in namespace %s: %v\n", resourceType, diff, namespace, err)
+		}
+	}
+
+	return diff, nil
+}
+
+func processNamespaceVirtualServices(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+	knownHosts, err := cache.retrieveIstioHosts(clientset, dynamicClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return processNamespaceIstioResources(clientset, dynamicClient, cache, namespace, virtualServiceResource, "VirtualService", unresolvedVirtualServiceReason, func(vs unstructured.Unstructured) (bool, error) {
+		destinations := retrieveVirtualServiceDestinations(vs)
+		// Delegate VirtualServices carry no destinations of their own
+		if len(destinations) == 0 {
+			return true, nil
+		}
+		for _, host := range destinations {
+			if isIstioHostResolved(host, namespace, knownHosts) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}, filterOpts, opts)
+}
+
+func processNamespaceDestinationRules(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+	knownHosts, err := cache.retrieveIstioHosts(clientset, dynamicClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return processNamespaceIstioResources(clientset, dynamicClient, cache, namespace, destinationRuleResource, "DestinationRule", unresolvedDestinationRuleReason, func(dr unstructured.Unstructured) (bool, error) {
+		host, _, err := unstructured.NestedString(dr.Object, "spec", "host")
+		if err != nil {
+			return false, err
+		}
+		return isIstioHostResolved(host, namespace, knownHosts), nil
+	}, filterOpts, opts)
+}
+
+func processNamespaceServiceEntries(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+	rootNamespace, err := retrieveIstioRoot(clientset, cache, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return processNamespaceIstioResources(clientset, dynamicClient, cache, namespace, serviceEntryResource, "ServiceEntry", noPodSelectedByEntryReason, func(se unstructured.Unstructured) (bool, error) {
+		return isAnyPodSelectedByIstioSelector(clientset, namespace, rootNamespace, se, "spec", "workloadSelector", "labels")
+	}, filterOpts, opts)
+}
+
+func processNamespaceSidecars(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+	rootNamespace, err := retrieveIstioRoot(clientset, cache, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return processNamespaceIstioResources(clientset, dynamicClient, cache, namespace, sidecarResource, "Sidecar", noPodSelectedBySidecarReason, func(sidecar unstructured.Unstructured) (bool, error) {
+		return isAnyPodSelectedByIstioSelector(clientset, namespace, rootNamespace, sidecar, "spec", "workloadSelector", "labels")
+	}, filterOpts, opts)
+}
+
+func processNamespaceAuthorizationPolicies(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+	rootNamespace, err := retrieveIstioRoot(clientset, cache, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return processNamespaceIstioResources(clientset, dynamicClient, cache, namespace, authorizationPolicyResource, "AuthorizationPolicy", noPodSelectedByAuthzReason, func(policy unstructured.Unstructured) (bool, error) {
+		return isAnyPodSelectedByIstioSelector(clientset, namespace, rootNamespace, policy, "spec", "selector", "matchLabels")
+	}, filterOpts, opts)
+}
+
+func processNamespacePeerAuthentications(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+	rootNamespace, err := retrieveIstioRoot(clientset, cache, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return processNamespaceIstioResources(clientset, dynamicClient, cache, namespace, peerAuthenticationResource, "PeerAuthentication", noPodSelectedByPeerAuthnReason, func(policy unstructured.Unstructured) (bool, error) {
+		return isAnyPodSelectedByIstioSelector(clientset, namespace, rootNamespace, policy, "spec", "selector", "matchLabels")
+	}, filterOpts, opts)
+}
+
+func getUnusedNamespacedDynamic(resourceType string, process namespaceDynamicProcessor, filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
+	resources := make(map[string]map[string][]ResourceInfo)
+	cache := newClusterCache()
+	for _, namespace := range filterOpts.Namespaces(clientset) {
+		diff, err := process(clientset, dynamicClient, cache, namespace, filterOpts, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to process namespace %s: %v\n", namespace, err)
+			continue
+		}
+		switch opts.GroupBy {
+		case "namespace":
+			resources[namespace] = make(map[string][]ResourceInfo)
+			resources[namespace][resourceType] = diff
+		case "resource":
+			appendResources(resources, resourceType, namespace, diff)
+		}
+	}
+
+	var outputBuffer bytes.Buffer
+	var jsonResponse []byte
+	switch outputFormat {
+	case "table":
+		outputBuffer = FormatOutput(resources, opts)
+	case "json", "yaml":
+		var err error
+		if jsonResponse, err = json.MarshalIndent(resources, "", "  "); err != nil {
+			return "", err
+		}
+	}
+
+	unused, err := unusedResourceFormatter(outputFormat, outputBuffer, opts, jsonResponse)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+	}
+
+	return unused, nil
+}
+
+func GetUnusedVirtualServices(filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
+	return getUnusedNamespacedDynamic("VirtualService", processNamespaceVirtualServices, filterOpts, clientset, dynamicClient, outputFormat, opts)
+}
+
+func GetUnusedDestinationRules(filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
+	return getUnusedNamespacedDynamic("DestinationRule", processNamespaceDestinationRules, filterOpts, clientset, dynamicClient, outputFormat, opts)
+}
+
+func GetUnusedServiceEntries(filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
+	return getUnusedNamespacedDynamic("ServiceEntry", processNamespaceServiceEntries, filterOpts, clientset, dynamicClient, outputFormat, opts)
+}
+
+func GetUnusedSidecars(filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
+	return getUnusedNamespacedDynamic("Sidecar", processNamespaceSidecars, filterOpts, clientset, dynamicClient, outputFormat, opts)
+}
+
+func GetUnusedAuthorizationPolicies(filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
+	return getUnusedNamespacedDynamic("AuthorizationPolicy", processNamespaceAuthorizationPolicies, filterOpts, clientset, dynamicClient, outputFormat, opts)
+}
+
+func GetUnusedPeerAuthentications(filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
+	return getUnusedNamespacedDynamic("PeerAuthentication", processNamespacePeerAuthentications, filterOpts, clientset, dynamicClient, outputFormat, opts)
+}
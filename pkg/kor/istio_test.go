@@ -0,0 +1,247 @@
+package kor
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func createTestIstioResource(kind string, gvr schema.GroupVersionResource, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	resource := CreateTestUnstructered(kind, gvr.GroupVersion().String(), testNamespace, name)
+	resource.Object["spec"] = spec
+	return resource
+}
+
+func createTestIstioResources(t *testing.T) (*fake.Clientset, *dynamicfake.FakeDynamicClient) {
+	clientset := fake.NewClientset()
+	clientset.Resources = []*v1.APIResourceList{
+		{GroupVersion: "networking.istio.io/v1", APIResources: []v1.APIResource{
+			{Name: "virtualservices", Namespaced: true, Kind: "VirtualService"},
+			{Name: "destinationrules", Namespaced: true, Kind: "DestinationRule"},
+			{Name: "serviceentries", Namespaced: true, Kind: "ServiceEntry"},
+			{Name: "sidecars", Namespaced: true, Kind: "Sidecar"},
+		}},
+		{GroupVersion: "security.istio.io/v1", APIResources: []v1.APIResource{
+			{Name: "authorizationpolicies", Namespaced: true, Kind: "AuthorizationPolicy"},
+			{Name: "peerauthentications", Namespaced: true, Kind: "PeerAuthentication"},
+		}},
+	}
+
+	_, err := clientset.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{
+		ObjectMeta: v1.ObjectMeta{Name: testNamespace},
+	}, v1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Error creating namespace %s: %v", testNamespace, err)
+	}
+
+	_, err = clientset.CoreV1().Services(testNamespace).Create(context.TODO(), CreateTestService(testNamespace, "reviews"), v1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Error creating fake service: %v", err)
+	}
+
+	_, err = clientset.CoreV1().Pods(testNamespace).Create(context.TODO(), CreateTestPod(testNamespace, "reviews-pod", "", nil, map[string]string{"app": "reviews"}), v1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Error creating fake pod: %v", err)
+	}
+
+	route := func(host string) map[string]interface{} {
+		return map[string]interface{}{
+			"http": []interface{}{
+				map[string]interface{}{
+					"route": []interface{}{
+						map[string]interface{}{"destination": map[string]interface{}{"host": host}},
+					},
+				},
+			},
+		}
+	}
+	selector := func(key string, app string) map[string]interface{} {
+		return map[string]interface{}{key: map[string]interface{}{"app": app}}
+	}
+
+	objects := []runtime.Object{
+		createTestIstioResource("ServiceEntry", serviceEntryResource.WithVersion("v1"), "external", map[string]interface{}{
+			"hosts": []interface{}{"api.example.com"},
+		}),
+		createTestIstioResource("ServiceEntry", serviceEntryResource.WithVersion("v1"), "mesh-external", map[string]interface{}{
+			"hosts":            []interface{}{"vm.example.com"},
+			"workloadSelector": selector("labels", "vm"),
+		}),
+		createTestIstioResource("VirtualService", virtualServiceResource.WithVersion("v1"), "vs-short-name", route("reviews")),
+		createTestIstioResource("VirtualService", virtualServiceResource.WithVersion("v1"), "vs-fqdn", route("reviews."+testNamespace+".svc.cluster.local")),
+		createTestIstioResource("VirtualService", virtualServiceResource.WithVersion("v1"), "vs-service-entry", route("api.example.com")),
+		createTestIstioResource("VirtualService", virtualServiceResource.WithVersion("v1"), "vs-dangling", route("ratings")),
+		createTestIstioResource("DestinationRule", destinationRuleResource.WithVersion("v1"), "dr-used", map[string]interface{}{"host": "reviews"}),
+		createTestIstioResource("DestinationRule", destinationRuleResource.WithVersion("v1"), "dr-wildcard", map[string]interface{}{"host": "*.example.com"}),
+		createTestIstioResource("DestinationRule", destinationRuleResource.WithVersion("v1"), "dr-dangling", map[string]interface{}{"host": "ratings." + testNamespace}),
+		createTestIstioResource("Sidecar", sidecarResource.WithVersion("v1"), "sidecar-default", map[string]interface{}{}),
+		createTestIstioResource("Sidecar", sidecarResource.WithVersion("v1"), "sidecar-used", map[string]interface{}{
+			"workloadSelector": selector("labels", "reviews"),
+		}),
+		createTestIstioResource("Sidecar", sidecarResource.WithVersion("v1"), "sidecar-unused", map[string]interface{}{
+			"workloadSelector": selector("labels", "ratings"),
+		}),
+		createTestIstioResource("AuthorizationPolicy", authorizationPolicyResource.WithVersion("v1"), "authz-used", map[string]interface{}{
+			"selector": selector("matchLabels", "reviews"),
+		}),
+		createTestIstioResource("AuthorizationPolicy", authorizationPolicyResource.WithVersion("v1"), "authz-unused", map[string]interface{}{
+			"selector": selector("matchLabels", "ratings"),
+		}),
+		createTestIstioResource("PeerAuthentication", peerAuthenticationResource.WithVersion("v1"), "peerauthn-namespace", map[string]interface{}{}),
+		createTestIstioResource("PeerAuthentication", peerAuthenticationResource.WithVersion("v1"), "peerauthn-unused", map[string]interface{}{
+			"selector": selector("matchLabels", "ratings"),
+		}),
+	}
+
+	gvrToListKind := map[schema.GroupVersionResource]string{
+		virtualServiceResource.WithVersion("v1"):      "VirtualServiceList",
+		destinationRuleResource.WithVersion("v1"):     "DestinationRuleList",
+		serviceEntryResource.WithVersion("v1"):        "ServiceEntryList",
+		sidecarResource.WithVersion("v1"):             "SidecarList",
+		authorizationPolicyResource.WithVersion("v1"): "AuthorizationPolicyList",
+		peerAuthenticationResource.WithVersion("v1"):  "PeerAuthenticationList",
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), gvrToListKind, objects...)
+
+	return clientset, dynamicClient
+}
+
+func TestIsIstioHostResolved(t *testing.T) {
+	knownHosts := map[string]bool{
+		"reviews.bookinfo.svc.cluster.local": true,
+		"api.example.com":                    true,
+		"*.wikipedia.org":                    true,
+	}
+
+	tests := []struct {
+		name      string
+		host      string
+		namespace string
+		expected  bool
+	}{
+		{"ShortName", "reviews", "bookinfo", true},
+		{"ShortNameOtherNamespace", "reviews", "default", false},
+		{"NamespacedName", "reviews.bookinfo", "default", true},
+		{"SvcName", "reviews.bookinfo.svc", "default", true},
+		{"FQDN", "reviews.bookinfo.svc.cluster.local", "default", true},
+		{"ServiceEntryHost", "api.example.com", "default", true},
+		{"ServiceEntryWildcardHost", "en.wikipedia.org", "default", true},
+		{"WildcardRuleHost", "*.example.com", "default", true},
+		{"WildcardRuleHostNoMatch", "*.example.org", "default", false},
+		{"UnknownHost", "ratings", "bookinfo", false},
+		{"EmptyHost", "", "bookinfo", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := isIstioHostResolved(tt.host, tt.namespace, knownHosts); result != tt.expected {
+				t.Errorf("Expected %v for host %q, got %v", tt.expected, tt.host, result)
+			}
+		})
+	}
+}
+
+func TestProcessNamespaceIstioResources(t *testing.T) {
+	clientset, dynamicClient := createTestIstioResources(t)
+
+	tests := []struct {
+		name     string
+		process  namespaceDynamicProcessor
+		expected []string
+		reason   string
+	}{
+		{"VirtualServices", processNamespaceVirtualServices, []string{"vs-dangling"}, unresolvedVirtualServiceReason},
+		{"DestinationRules", processNamespaceDestinationRules, []string{"dr-dangling"}, unresolvedDestinationRuleReason},
+		{"ServiceEntries", processNamespaceServiceEntries, []string{"mesh-external"}, noPodSelectedByEntryReason},
+		{"Sidecars", processNamespaceSidecars, []string{"sidecar-unused"}, noPodSelectedBySidecarReason},
+		{"AuthorizationPolicies", processNamespaceAuthorizationPolicies, []string{"authz-unused"}, noPodSelectedByAuthzReason},
+		{"PeerAuthentications", processNamespacePeerAuthentications, []string{"peerauthn-unused"}, noPodSelectedByPeerAuthnReason},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unused, err := tt.process(clientset, dynamicClient, newClusterCache(), testNamespace, &filters.Options{}, common.Opts{})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if len(unused) != len(tt.expected) {
+				t.Fatalf("Expected %d unused resources, got %d: %v", len(tt.expected), len(unused), unused)
+			}
+
+			for i, resource := range unused {
+				if resource.Name != tt.expected[i] {
+					t.Errorf("Expected %s, got %s", tt.expected[i], resource.Name)
+				}
+				if resource.Reason != tt.reason {
+					t.Errorf("Expected reason %q, got %q", tt.reason, resource.Reason)
+				}
+			}
+		})
+	}
+}
+
+func TestRetrieveIstioGVR(t *testing.T) {
+	clientset, _ := createTestIstioResources(t)
+
+	gvr, err := retrieveIstioGVR(clientset, newClusterCache(), sidecarResource)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if gvr != sidecarResource.WithVersion("v1") {
+		t.Errorf("Expected the served version v1, got %v", gvr)
+	}
+
+	gvr, err = retrieveIstioGVR(fake.NewClientset(), newClusterCache(), sidecarResource)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if gvr != sidecarResource.WithVersion(istioFallbackVersion) {
+		t.Errorf("Expected the fallback version %s, got %v", istioFallbackVersion, gvr)
+	}
+}
+
+func TestIstioRootNamespacePolicies(t *testing.T) {
+	clientset, _ := createTestIstioResources(t)
+
+	meshConfig := CreateTestConfigmap(defaultIstioRootNamespace, istioMeshConfigMap, nil)
+	meshConfig.Data = map[string]string{"mesh": "rootNamespace: mesh-root\n"}
+	if _, err := clientset.CoreV1().ConfigMaps(defaultIstioRootNamespace).Create(context.TODO(), meshConfig, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake configmap: %v", err)
+	}
+
+	policy := createTestIstioResource("AuthorizationPolicy", authorizationPolicyResource.WithVersion("v1"), "mesh-wide", map[string]interface{}{
+		"selector": map[string]interface{}{"matchLabels": map[string]interface{}{"app": "reviews"}},
+	})
+	policy.SetNamespace("mesh-root")
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		authorizationPolicyResource.WithVersion("v1"): "AuthorizationPolicyList",
+	}, policy)
+
+	// The policy selects pods of another namespace, which only counts from the root namespace
+	unused, err := processNamespaceAuthorizationPolicies(clientset, dynamicClient, newClusterCache(), "mesh-root", &filters.Options{}, common.Opts{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(unused) != 0 {
+		t.Errorf("Expected the policy of the mesh config root namespace to be used, got %v", unused)
+	}
+
+	unused, err = processNamespaceAuthorizationPolicies(clientset, dynamicClient, newClusterCache(), "mesh-root", &filters.Options{}, common.Opts{IstioRootNamespace: "other-root"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(unused) != 1 || unused[0].Name != "mesh-wide" {
+		t.Errorf("Expected mesh-wide to be unused outside of the root namespace set with --istio-root-namespace, got %v", unused)
+	}
+}
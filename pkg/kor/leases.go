@@ -0,0 +1,150 @@
+package kor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+// retrieveLeaseLastRenewal returns the last time the lease was renewed or acquired, falling back to its creation
+func retrieveLeaseLastRenewal(lease coordinationv1.Lease) time.Time {
+	switch {
+	case lease.Spec.RenewTime != nil:
+		return lease.Spec.RenewTime.Time
+	case lease.Spec.AcquireTime != nil:
+		return lease.Spec.AcquireTime.Time
+	default:
+		return lease.CreationTimestamp.Time
+	}
+}
+
+// isLeaseHolderPodRunning checks whether a pod named after the holder identity exists in the lease namespace.
+// client-go leader election uses "<hostname>_<uuid>" as identity, so the suffix is stripped as well.
+func isLeaseHolderPodRunning(clientset kubernetes.Interface, namespace, holderIdentity string) (bool, error) {
+	candidates := []string{holderIdentity}
+	if podName, _, found := strings.Cut(holderIdentity, "_"); found {
+		candidates = append(candidates, podName)
+	}
+
+	for _, name := range candidates {
+		_, err := clientset.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err == nil {
+			return true, nil
+		}
+		if !errors.IsNotFound(err) {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+func processNamespaceLeases(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+	leaseList, err := clientset.CoordinationV1().Leases(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
+	if err != nil {
+		return nil, err
+	}
+
+	var unusedLeases []ResourceInfo
+	now := time.Now()
+	renewThreshold := opts.LeaseRenewThreshold
+
+	for _, lease := range leaseList.Items {
+		if pass, _ := filter.SetObject(&lease).Run(filterOpts); pass {
+			continue
+		}
+
+		if lease.Labels["kor/used"] == "false" {
+			unusedLeases = append(unusedLeases, ResourceInfo{Name: lease.Name, Reason: unusedLabelReason})
+			continue
+		}
+
+		// Leases owned by another object (e.g. node heartbeats) are garbage collected along with their owner
+		if len(lease.OwnerReferences) > 0 {
+			continue
+		}
+
+		sinceRenewal := now.Sub(retrieveLeaseLastRenewal(lease))
+		if sinceRenewal < renewThreshold {
+			continue
+		}
+
+		holderIdentity := ""
+		if lease.Spec.HolderIdentity != nil {
+			holderIdentity = *lease.Spec.HolderIdentity
+		}
+
+		if holderIdentity == "" {
+			reason := fmt.Sprintf("Lease has no holder and was last renewed %s ago", duration.HumanDuration(sinceRenewal))
+			unusedLeases = append(unusedLeases, ResourceInfo{Name: lease.Name, Reason: reason})
+			continue
+		}
+
+		running, err := isLeaseHolderPodRunning(clientset, namespace, holderIdentity)
+		if err != nil {
+			return nil, err
+		}
+		if !running {
+			reason := fmt.Sprintf("Holder %s has not renewed for %s and its pod no longer exists", holderIdentity, duration.HumanDuration(sinceRenewal))
+			unusedLeases = append(unusedLeases, ResourceInfo{Name: lease.Name, Reason: reason})
+		}
+	}
+
+	if opts.DeleteFlag {
+		if unusedLeases, err = DeleteResource(unusedLeases, clientset, namespace, "Lease", opts.NoInteractive); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to delete Lease %s in namespace %s: %v\n", unusedLeases, namespace, err)
+		}
+	}
+
+	return unusedLeases, nil
+}
+
+func GetUnusedLeases(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
+	resources := make(map[string]map[string][]ResourceInfo)
+	for _, namespace := range filterOpts.Namespaces(clientset) {
+		diff, err := processNamespaceLeases(clientset, namespace, filterOpts, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to process namespace %s: %v\n", namespace, err)
+			continue
+		}
+		switch opts.GroupBy {
+		case "namespace":
+			resources[namespace] = make(map[string][]ResourceInfo)
+			resources[namespace]["Lease"] = diff
+		case "resource":
+			appendResources(resources, "Lease", namespace, diff)
+		}
+	}
+
+	var outputBuffer bytes.Buffer
+	var jsonResponse []byte
+	switch outputFormat {
+	case "table":
+		outputBuffer = FormatOutput(resources, opts)
+	case "json", "yaml":
+		var err error
+		if jsonResponse, err = json.MarshalIndent(resources, "", "  "); err != nil {
+			return "", err
+		}
+	}
+
+	unusedLeases, err := unusedResourceFormatter(outputFormat, outputBuffer, opts, jsonResponse)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+	}
+
+	return unusedLeases, nil
+}
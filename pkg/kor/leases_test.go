@@ -0,0 +1,88 @@
+package kor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func createTestLease(name, holderIdentity string, renewTime time.Time) *coordinationv1.Lease {
+	lease := &coordinationv1.Lease{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec:       coordinationv1.LeaseSpec{RenewTime: &v1.MicroTime{Time: renewTime}},
+	}
+	if holderIdentity != "" {
+		lease.Spec.HolderIdentity = &holderIdentity
+	}
+	return lease
+}
+
+func createTestLeases(t *testing.T) *fake.Clientset {
+	clientset := fake.NewClientset()
+
+	_, err := clientset.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{
+		ObjectMeta: v1.ObjectMeta{Name: testNamespace},
+	}, v1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Error creating namespace %s: %v", testNamespace, err)
+	}
+
+	_, err = clientset.CoreV1().Pods(testNamespace).Create(context.TODO(), CreateTestPod(testNamespace, "operator-7d9f", "", nil, AppLabels), v1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Error creating fake pod: %v", err)
+	}
+
+	stale := time.Now().Add(-48 * time.Hour)
+	ownedLease := createTestLease("node-lease", "gone-node", stale)
+	ownedLease.OwnerReferences = []v1.OwnerReference{{Kind: "Node", Name: "gone-node", APIVersion: "v1"}}
+	unusedLabelLease := createTestLease("marked-lease", "operator-7d9f", time.Now())
+	unusedLabelLease.Labels = map[string]string{"kor/used": "false"}
+
+	leases := []*coordinationv1.Lease{
+		createTestLease("active-lease", "operator-7d9f_2c1e7a10", time.Now()),
+		createTestLease("stale-running-holder", "operator-7d9f_2c1e7a10", stale),
+		createTestLease("stale-gone-holder", "operator-5b2c_8e4f1d22", stale),
+		createTestLease("released-lease", "", stale),
+		ownedLease,
+		unusedLabelLease,
+	}
+	for _, lease := range leases {
+		if _, err := clientset.CoordinationV1().Leases(testNamespace).Create(context.TODO(), lease, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake lease: %v", err)
+		}
+	}
+
+	return clientset
+}
+
+func TestProcessNamespaceLeases(t *testing.T) {
+	clientset := createTestLeases(t)
+
+	unusedLeases, err := processNamespaceLeases(clientset, testNamespace, &filters.Options{}, common.NewOpts())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "marked-lease", Reason: unusedLabelReason},
+		{Name: "released-lease", Reason: "Lease has no holder and was last renewed 2d ago"},
+		{Name: "stale-gone-holder", Reason: "Holder operator-5b2c_8e4f1d22 has not renewed for 2d and its pod no longer exists"},
+	}
+
+	if len(unusedLeases) != len(expected) {
+		t.Fatalf("Expected %d unused leases, got %d: %v", len(expected), len(unusedLeases), unusedLeases)
+	}
+	for i, lease := range unusedLeases {
+		if lease != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], lease)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
@@ -36,8 +37,9 @@ func getCanonicalResourceType(resourceName string) string {
 	return resourceName
 }
 
-func retrieveNoNamespaceDiff(clientset kubernetes.Interface, apiExtClient apiextensionsclientset.Interface, dynamicClient dynamic.Interface, resourceList []string, filterOpts *filters.Options, opts common.Opts) ([]ResourceDiff, []string) {
+func retrieveNoNamespaceDiff(clientset kubernetes.Interface, apiExtClient apiextensionsclientset.Interface, dynamicClient dynamic.Interface, cache *clusterCache, resourceList []string, filterOpts *filters.Options, opts common.Opts) ([]ResourceDiff, []string, resource.Quantity) {
 	var noNamespaceDiff []ResourceDiff
+	var reclaimable resource.Quantity
 	markedForRemoval := make([]bool, len(resourceList))
 	updatedResourceList := resourceList
 
@@ -49,15 +51,16 @@ func retrieveNoNamespaceDiff(clientset kubernetes.Interface, apiExtClient apiext
 			noNamespaceDiff = append(noNamespaceDiff, crdDiff)
 			markedForRemoval[counter] = true
 		case "persistentvolume":
-			pvDiff := getUnusedPvs(clientset, filterOpts)
+			var pvDiff ResourceDiff
+			pvDiff, reclaimable = getUnusedPvs(clientset, filterOpts)
 			noNamespaceDiff = append(noNamespaceDiff, pvDiff)
 			markedForRemoval[counter] = true
 		case "clusterrole":
-			clusterRoleDiff := getUnusedClusterRoles(clientset, filterOpts)
+			clusterRoleDiff := getUnusedClusterRoles(clientset, dynamicClient, cache, filterOpts)
 			noNamespaceDiff = append(noNamespaceDiff, clusterRoleDiff)
 			markedForRemoval[counter] = true
 		case "clusterrolebinding":
-			clusterRoleBindingDiff := getUnusedClusterRoleBindings(clientset, filterOpts, opts)
+			clusterRoleBindingDiff := getUnusedClusterRoleBindings(clientset, cache, filterOpts, opts)
 			noNamespaceDiff = append(noNamespaceDiff, clusterRoleBindingDiff)
 			markedForRemoval[counter] = true
 		case "storageclass":
@@ -72,6 +75,34 @@ func retrieveNoNamespaceDiff(clientset kubernetes.Interface, apiExtClient apiext
 			pcDiff := getUnusedPriorityClasses(clientset, filterOpts)
 			noNamespaceDiff = append(noNamespaceDiff, pcDiff)
 			markedForRemoval[counter] = true
+		case "runtimeclass":
+			runtimeClassDiff := getUnusedRuntimeClasses(clientset, filterOpts)
+			noNamespaceDiff = append(noNamespaceDiff, runtimeClassDiff)
+			markedForRemoval[counter] = true
+		case "flowschema":
+			flowSchemaDiff := getUnusedFlowSchemas(clientset, filterOpts)
+			noNamespaceDiff = append(noNamespaceDiff, flowSchemaDiff)
+			markedForRemoval[counter] = true
+		case "prioritylevelconfiguration":
+			priorityLevelDiff := getUnusedPriorityLevelConfigurations(clientset, filterOpts)
+			noNamespaceDiff = append(noNamespaceDiff, priorityLevelDiff)
+			markedForRemoval[counter] = true
+		case "validatingadmissionpolicy":
+			policyDiff := getUnusedValidatingAdmissionPolicies(clientset, filterOpts)
+			noNamespaceDiff = append(noNamespaceDiff, policyDiff)
+			markedForRemoval[counter] = true
+		case "validatingadmissionpolicybinding":
+			bindingDiff := getUnusedValidatingAdmissionPolicyBindings(clientset, dynamicClient, filterOpts)
+			noNamespaceDiff = append(noNamespaceDiff, bindingDiff)
+			markedForRemoval[counter] = true
+		case "certificatesigningrequest":
+			csrDiff := getUnusedCertificateSigningRequests(clientset, filterOpts, opts)
+			noNamespaceDiff = append(noNamespaceDiff, csrDiff)
+			markedForRemoval[counter] = true
+		case "node":
+			nodeDiff := getUnusedNodes(clientset, filterOpts, opts)
+			noNamespaceDiff = append(noNamespaceDiff, nodeDiff)
+			markedForRemoval[counter] = true
 		}
 	}
 
@@ -83,29 +114,29 @@ func retrieveNoNamespaceDiff(clientset kubernetes.Interface, apiExtClient apiext
 		}
 	}
 
-	return noNamespaceDiff, clearedResourceList
+	return noNamespaceDiff, clearedResourceList, reclaimable
 }
 
-func retrieveNamespaceDiffs(clientset kubernetes.Interface, namespace string, resourceList []string, filterOpts *filters.Options, opts common.Opts) []ResourceDiff {
+func retrieveNamespaceDiffs(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, resourceList []string, filterOpts *filters.Options, opts common.Opts) []ResourceDiff {
 	var allDiffs []ResourceDiff
 	for _, resource := range resourceList {
 		var diffResult ResourceDiff
 		canonicalType := getCanonicalResourceType(resource)
 		switch canonicalType {
 		case "configmap":
-			diffResult = getUnusedCMs(clientset, namespace, filterOpts, opts)
+			diffResult = getUnusedCMs(clientset, dynamicClient, namespace, filterOpts, opts)
 		case "service":
-			diffResult = getUnusedSVCs(clientset, namespace, filterOpts, opts)
+			diffResult = getUnusedSVCs(clientset, dynamicClient, namespace, filterOpts, opts)
 		case "secret":
-			diffResult = getUnusedSecrets(clientset, namespace, filterOpts, opts)
+			diffResult = getUnusedSecrets(clientset, dynamicClient, cache, namespace, filterOpts, opts)
 		case "serviceaccount":
-			diffResult = getUnusedServiceAccounts(clientset, namespace, filterOpts, opts)
+			diffResult = getUnusedServiceAccounts(clientset, cache, namespace, filterOpts, opts)
 		case "deployment":
 			diffResult = getUnusedDeployments(clientset, namespace, filterOpts, opts)
 		case "statefulset":
 			diffResult = getUnusedStatefulSets(clientset, namespace, filterOpts, opts)
 		case "role":
-			diffResult = getUnusedRoles(clientset, namespace, filterOpts, opts)
+			diffResult = getUnusedRoles(clientset, dynamicClient, cache, namespace, filterOpts, opts)
 		case "horizontalpodautoscaler":
 			diffResult = getUnusedHpas(clientset, namespace, filterOpts, opts)
 		case "persistentvolumeclaim":
@@ -123,9 +154,27 @@ func retrieveNamespaceDiffs(clientset kubernetes.Interface, namespace string, re
 		case "daemonset":
 			diffResult = getUnusedDaemonSets(clientset, namespace, filterOpts, opts)
 		case "networkpolicy":
-			diffResult = getUnusedNetworkPolicies(clientset, namespace, filterOpts, opts)
+			diffResult = getUnusedNetworkPolicies(clientset, cache, namespace, filterOpts, opts)
 		case "rolebinding":
-			diffResult = getUnusedRoleBindings(clientset, namespace, filterOpts, opts)
+			diffResult = getUnusedRoleBindings(clientset, cache, namespace, filterOpts, opts)
+		case "lease":
+			diffResult = getUnusedLeases(clientset, namespace, filterOpts, opts)
+		case "podtemplate":
+			diffResult = getUnusedPodTemplates(clientset, dynamicClient, namespace, filterOpts, opts)
+		case "controllerrevision":
+			diffResult = getUnusedControllerRevisions(clientset, namespace, filterOpts, opts)
+		case "virtualservice":
+			diffResult = getUnusedVirtualServices(clientset, dynamicClient, cache, namespace, filterOpts, opts)
+		case "destinationrule":
+			diffResult = getUnusedDestinationRules(clientset, dynamicClient, cache, namespace, filterOpts, opts)
+		case "serviceentry":
+			diffResult = getUnusedServiceEntries(clientset, dynamicClient, cache, namespace, filterOpts, opts)
+		case "sidecar":
+			diffResult = getUnusedSidecars(clientset, dynamicClient, cache, namespace, filterOpts, opts)
+		case "authorizationpolicy":
+			diffResult = getUnusedAuthorizationPolicies(clientset, dynamicClient, cache, namespace, filterOpts, opts)
+		case "peerauthentication":
+			diffResult = getUnusedPeerAuthentications(clientset, dynamicClient, cache, namespace, filterOpts, opts)
 		default:
 			fmt.Printf("resource type %q is not supported\n", resource)
 		}
@@ -144,7 +193,8 @@ func GetUnusedMulti(resourceNames string, filterOpts *filters.Options, clientset
 		resources[""] = make(map[string][]ResourceInfo)
 	}
 
-	noNamespaceDiff, resourceList := retrieveNoNamespaceDiff(clientset, apiExtClient, dynamicClient, resourceList, filterOpts, opts)
+	cache := newClusterCache()
+	noNamespaceDiff, resourceList, reclaimable := retrieveNoNamespaceDiff(clientset, apiExtClient, dynamicClient, cache, resourceList, filterOpts, opts)
 	if len(noNamespaceDiff) != 0 {
 		for _, diff := range noNamespaceDiff {
 			if len(diff.diff) != 0 {
@@ -164,7 +214,7 @@ func GetUnusedMulti(resourceNames string, filterOpts *filters.Options, clientset
 	}
 
 	for _, namespace := range namespaces {
-		allDiffs := retrieveNamespaceDiffs(clientset, namespace, resourceList, filterOpts, opts)
+		allDiffs := retrieveNamespaceDiffs(clientset, dynamicClient, cache, namespace, resourceList, filterOpts, opts)
 		if opts.GroupBy == "namespace" {
 			resources[namespace] = make(map[string][]ResourceInfo)
 		}
@@ -195,11 +245,12 @@ func GetUnusedMulti(resourceNames string, filterOpts *filters.Options, clientset
 			return "", err
 		}
 	}
+	writeReclaimableStorage(&outputBuffer, outputFormat, reclaimable)
 
 	unusedMulti, err := unusedResourceFormatter(outputFormat, outputBuffer, opts, jsonResponse)
 	if err != nil {
 		fmt.Printf("err: %v\n", err)
 	}
 
-	return unusedMulti, nil
+	return addReclaimableStorage(unusedMulti, outputFormat, reclaimable)
 }
@@ -4,9 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 
@@ -61,7 +67,7 @@ func TestRetrieveNamespaceDiff(t *testing.T) {
 	resourceList := []string{"cm", "pdb", "deployment"}
 	filterOpts := &filters.Options{}
 
-	namespaceDiff := retrieveNamespaceDiffs(clientset, testNamespace, resourceList, filterOpts, common.Opts{})
+	namespaceDiff := retrieveNamespaceDiffs(clientset, nil, newClusterCache(), testNamespace, resourceList, filterOpts, common.Opts{})
 
 	if len(namespaceDiff) != 3 {
 		t.Fatalf("Expected 3 diffs, got %d", len(namespaceDiff))
@@ -202,3 +208,229 @@ func TestGetUnusedMultiWithMultipleResources(t *testing.T) {
 
 	t.Logf("Multi-resource output: %s", output)
 }
+
+func TestGetUnusedMultiDeleteKeepsReportOnly(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	ResourceKindList = map[string]ResourceKind{
+		"pod":                   {Plural: "pods", ShortNames: []string{"po"}},
+		"deployment":            {Plural: "deployments", ShortNames: []string{"deploy"}},
+		"statefulset":           {Plural: "statefulsets", ShortNames: []string{"sts"}},
+		"daemonset":             {Plural: "daemonsets", ShortNames: []string{"ds"}},
+		"persistentvolumeclaim": {Plural: "persistentvolumeclaims", ShortNames: []string{"pvc"}},
+		"storageclass":          {Plural: "storageclasses", ShortNames: []string{"sc"}},
+		"serviceaccount":        {Plural: "serviceaccounts", ShortNames: []string{"sa"}},
+		"role":                  {Plural: "roles"},
+		"clusterrole":           {Plural: "clusterroles"},
+		"rolebinding":           {Plural: "rolebindings"},
+		"networkpolicy":         {Plural: "networkpolicies", ShortNames: []string{"netpol"}},
+	}
+	clientset.Resources = []*v1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []v1.APIResource{{Name: "pods", Namespaced: true, Kind: "Pod"}}},
+	}
+
+	_, err := clientset.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{
+		ObjectMeta: v1.ObjectMeta{Name: testNamespace},
+	}, v1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Error creating namespace %s: %v", testNamespace, err)
+	}
+
+	evicted := CreateTestPod(testNamespace, "evicted", "", nil, AppLabels)
+	evicted.Status = corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"}
+	bare := CreateTestPod(testNamespace, "bare", "", []corev1.Volume{
+		{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "lost"}}},
+	}, AppLabels)
+	bare.Status = corev1.PodStatus{Phase: corev1.PodRunning}
+	for _, pod := range []*corev1.Pod{evicted, bare} {
+		if _, err := clientset.CoreV1().Pods(testNamespace).Create(context.TODO(), pod, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake pod: %v", err)
+		}
+	}
+
+	brokenDeployment := CreateTestDeployment(testNamespace, "broken", 1, AppLabels)
+	brokenDeployment.Spec.Template.Spec.ServiceAccountName = "missing-sa"
+	for _, deployment := range []*appsv1.Deployment{CreateTestDeployment(testNamespace, "scaled-down", 0, AppLabels), brokenDeployment} {
+		if _, err := clientset.AppsV1().Deployments(testNamespace).Create(context.TODO(), deployment, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake deployment: %v", err)
+		}
+	}
+
+	brokenStatefulSet := CreateTestStatefulSet(testNamespace, "broken", 1, AppLabels)
+	brokenStatefulSet.Spec.Template.Spec.ServiceAccountName = "missing-sa"
+	for _, statefulSet := range []*appsv1.StatefulSet{CreateTestStatefulSet(testNamespace, "scaled-down", 0, AppLabels), brokenStatefulSet} {
+		if _, err := clientset.AppsV1().StatefulSets(testNamespace).Create(context.TODO(), statefulSet, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake statefulset: %v", err)
+		}
+	}
+
+	failingDaemonSet := CreateTestDaemonSet(testNamespace, "failing", AppLabels, &appsv1.DaemonSetStatus{CurrentNumberScheduled: 1, NumberUnavailable: 1})
+	if _, err := clientset.AppsV1().DaemonSets(testNamespace).Create(context.TODO(), failingDaemonSet, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake daemonset: %v", err)
+	}
+
+	lostPvc := CreateTestPvc(testNamespace, "lost", AppLabels, "orphaned-provisioner")
+	lostPvc.Status.Phase = corev1.ClaimLost
+	if _, err := clientset.CoreV1().PersistentVolumeClaims(testNamespace).Create(context.TODO(), lostPvc, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake pvc: %v", err)
+	}
+
+	// The lost PVC uses a StorageClass whose provisioner is gone
+	if _, err := clientset.StorageV1().StorageClasses().Create(context.TODO(), CreateTestStorageClass("orphaned-provisioner", "gone.example.com"), v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake StorageClass: %v", err)
+	}
+
+	if _, err := clientset.CoreV1().ServiceAccounts(testNamespace).Create(context.TODO(), CreateTestServiceAccount(testNamespace, "ci-deployer", AppLabels), v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake ServiceAccount: %v", err)
+	}
+	adminRole := &rbacv1.ClusterRole{
+		ObjectMeta: v1.ObjectMeta{Name: "super-admin"},
+		Rules:      []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}},
+	}
+	if _, err := clientset.RbacV1().ClusterRoles().Create(context.TODO(), adminRole, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake ClusterRole: %v", err)
+	}
+	adminBinding := CreateTestClusterRoleBindingRoleRef(testNamespace, "ci-deployer", "ci-deployer", CreateTestRoleRefForClusterRole("super-admin"))
+	if _, err := clientset.RbacV1().ClusterRoleBindings().Create(context.TODO(), adminBinding, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake ClusterRoleBinding: %v", err)
+	}
+
+	widgetsRule := rbacv1.PolicyRule{APIGroups: []string{"example.com"}, Resources: []string{"widgets"}, Verbs: []string{"get"}}
+	widgetRole := CreateTestRole(testNamespace, "widget-reader", AppLabels)
+	widgetRole.Rules = []rbacv1.PolicyRule{widgetsRule}
+	if _, err := clientset.RbacV1().Roles(testNamespace).Create(context.TODO(), widgetRole, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake Role: %v", err)
+	}
+	widgetRoleBinding := CreateTestRoleBinding(testNamespace, "widget-reader", "ci-deployer", CreateTestRoleRef("widget-reader"))
+	if _, err := clientset.RbacV1().RoleBindings(testNamespace).Create(context.TODO(), widgetRoleBinding, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake RoleBinding: %v", err)
+	}
+	widgetClusterRole := &rbacv1.ClusterRole{ObjectMeta: v1.ObjectMeta{Name: "widget-reader"}, Rules: []rbacv1.PolicyRule{widgetsRule}}
+	if _, err := clientset.RbacV1().ClusterRoles().Create(context.TODO(), widgetClusterRole, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake ClusterRole: %v", err)
+	}
+	widgetClusterRoleBinding := CreateTestClusterRoleBindingRoleRef(testNamespace, "widget-reader", "ci-deployer", CreateTestRoleRefForClusterRole("widget-reader"))
+	if _, err := clientset.RbacV1().ClusterRoleBindings().Create(context.TODO(), widgetClusterRoleBinding, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake ClusterRoleBinding: %v", err)
+	}
+
+	partiallyStaleBinding := CreateTestRoleBinding(testNamespace, "partially-stale", "", CreateTestRoleRef("widget-reader"))
+	partiallyStaleBinding.Subjects = []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "alice"}, {Kind: rbacv1.GroupKind, Name: "contractors"}}
+	if _, err := clientset.RbacV1().RoleBindings(testNamespace).Create(context.TODO(), partiallyStaleBinding, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake RoleBinding: %v", err)
+	}
+
+	// The bare pod is still selected by the NetworkPolicy whose peer matches no namespace
+	missingPeerIngress := []networkingv1.NetworkPolicyIngressRule{{
+		From: []networkingv1.NetworkPolicyPeer{
+			{NamespaceSelector: v1.SetAsLabelSelector(map[string]string{"team": "missing"})},
+			{PodSelector: v1.SetAsLabelSelector(AppLabels)},
+		},
+	}}
+	for _, netpol := range []*networkingv1.NetworkPolicy{
+		CreateTestNetworkPolicy("selects-nothing", testNamespace, AppLabels, *v1.SetAsLabelSelector(map[string]string{"app": "gone"}), nil, nil),
+		CreateTestNetworkPolicy("missing-peer", testNamespace, AppLabels, *v1.SetAsLabelSelector(AppLabels), missingPeerIngress, nil),
+	} {
+		if _, err := clientset.NetworkingV1().NetworkPolicies(testNamespace).Create(context.TODO(), netpol, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake networkpolicy: %v", err)
+		}
+	}
+
+	opts := common.Opts{
+		DeleteFlag:    true,
+		NoInteractive: true,
+		GroupBy:       "namespace",
+		AuditLogActivity: &common.SubjectActivity{
+			Users:  map[string]time.Time{"alice": time.Now()},
+			Groups: map[string]time.Time{},
+			Since:  time.Now().AddDate(-1, 0, 0),
+		},
+	}
+	if _, err := GetUnusedMulti("clusterrole,role,rolebinding,sc,sa,pvc,pod,deployment,statefulset,daemonset,netpol", &filters.Options{}, clientset, nil, nil, "json", opts); err != nil {
+		t.Fatalf("Error calling GetUnusedMulti: %v", err)
+	}
+
+	assertDeleted := func(kind, name string, err error, expectDeleted bool) {
+		t.Helper()
+		if expectDeleted && err == nil {
+			t.Errorf("Expected %s %s to be deleted", kind, name)
+		}
+		if !expectDeleted && err != nil {
+			t.Errorf("Expected report-only %s %s to survive, got %v", kind, name, err)
+		}
+	}
+
+	_, err = clientset.CoreV1().Pods(testNamespace).Get(context.TODO(), "evicted", v1.GetOptions{})
+	assertDeleted("Pod", "evicted", err, true)
+	_, err = clientset.CoreV1().Pods(testNamespace).Get(context.TODO(), "bare", v1.GetOptions{})
+	assertDeleted("Pod", "bare", err, false)
+	_, err = clientset.AppsV1().Deployments(testNamespace).Get(context.TODO(), "scaled-down", v1.GetOptions{})
+	assertDeleted("Deployment", "scaled-down", err, true)
+	_, err = clientset.AppsV1().Deployments(testNamespace).Get(context.TODO(), "broken", v1.GetOptions{})
+	assertDeleted("Deployment", "broken", err, false)
+	_, err = clientset.AppsV1().StatefulSets(testNamespace).Get(context.TODO(), "scaled-down", v1.GetOptions{})
+	assertDeleted("StatefulSet", "scaled-down", err, true)
+	_, err = clientset.AppsV1().StatefulSets(testNamespace).Get(context.TODO(), "broken", v1.GetOptions{})
+	assertDeleted("StatefulSet", "broken", err, false)
+	_, err = clientset.AppsV1().DaemonSets(testNamespace).Get(context.TODO(), "failing", v1.GetOptions{})
+	assertDeleted("DaemonSet", "failing", err, false)
+	_, err = clientset.CoreV1().PersistentVolumeClaims(testNamespace).Get(context.TODO(), "lost", v1.GetOptions{})
+	assertDeleted("PersistentVolumeClaim", "lost", err, false)
+	_, err = clientset.StorageV1().StorageClasses().Get(context.TODO(), "orphaned-provisioner", v1.GetOptions{})
+	assertDeleted("StorageClass", "orphaned-provisioner", err, false)
+	_, err = clientset.CoreV1().ServiceAccounts(testNamespace).Get(context.TODO(), "ci-deployer", v1.GetOptions{})
+	assertDeleted("ServiceAccount", "ci-deployer", err, false)
+	_, err = clientset.RbacV1().Roles(testNamespace).Get(context.TODO(), "widget-reader", v1.GetOptions{})
+	assertDeleted("Role", "widget-reader", err, false)
+	_, err = clientset.RbacV1().ClusterRoles().Get(context.TODO(), "widget-reader", v1.GetOptions{})
+	assertDeleted("ClusterRole", "widget-reader", err, false)
+	_, err = clientset.RbacV1().RoleBindings(testNamespace).Get(context.TODO(), "partially-stale", v1.GetOptions{})
+	assertDeleted("RoleBinding", "partially-stale", err, false)
+	_, err = clientset.NetworkingV1().NetworkPolicies(testNamespace).Get(context.TODO(), "selects-nothing", v1.GetOptions{})
+	assertDeleted("NetworkPolicy", "selects-nothing", err, true)
+	_, err = clientset.NetworkingV1().NetworkPolicies(testNamespace).Get(context.TODO(), "missing-peer", v1.GetOptions{})
+	assertDeleted("NetworkPolicy", "missing-peer", err, false)
+}
+
+func TestGetUnusedMultiReclaimableStorage(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	pv := CreateTestPv("available", string(corev1.VolumeAvailable), AppLabels, "fast")
+	pv.Spec.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")}
+	if _, err := clientset.CoreV1().PersistentVolumes().Create(context.TODO(), pv, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake PV: %v", err)
+	}
+
+	output, err := GetUnusedMulti("persistentvolume", &filters.Options{}, clientset, nil, nil, "table", common.Opts{GroupBy: "namespace"})
+	if err != nil {
+		t.Fatalf("Error calling GetUnusedMulti: %v", err)
+	}
+
+	if !strings.Contains(output, "Reclaimable storage: 10Gi") {
+		t.Errorf("Expected the reclaimable storage in the output, got:\n%s", output)
+	}
+
+	output, err = GetUnusedMulti("persistentvolume", &filters.Options{}, clientset, nil, nil, "json", common.Opts{GroupBy: "namespace"})
+	if err != nil {
+		t.Fatalf("Error calling GetUnusedMulti: %v", err)
+	}
+
+	var structured map[string]interface{}
+	if err := json.Unmarshal([]byte(output), &structured); err != nil {
+		t.Fatalf("Error unmarshaling output: %v", err)
+	}
+	if structured[reclaimableStorageKey] != "10Gi" {
+		t.Errorf("Expected the reclaimable storage in the json output, got:\n%s", output)
+	}
+	if _, ok := structured[""]; !ok {
+		t.Errorf("Expected the PVs to stay in the json output, got:\n%s", output)
+	}
+
+	output, err = GetUnusedMulti("persistentvolume", &filters.Options{}, clientset, nil, nil, "yaml", common.Opts{GroupBy: "namespace"})
+	if err != nil {
+		t.Fatalf("Error calling GetUnusedMulti: %v", err)
+	}
+	if !strings.Contains(output, reclaimableStorageKey+": 10Gi") {
+		t.Errorf("Expected the reclaimable storage in the yaml output, got:\n%s", output)
+	}
+}
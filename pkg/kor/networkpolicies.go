@@ -2,15 +2,21 @@ package kor
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/netip"
 	"os"
 	"slices"
+	"strings"
 
 	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/yonahd/kor/pkg/common"
@@ -38,6 +44,239 @@ func retrievePodsForSelector(clientset kubernetes.Interface, namespace string, s
 	return podList.Items, nil
 }
 
+// isPrefixCovered reports whether the union of the except prefixes covers prefix, splitting it in halves
+// only as deep as the excepts inside it go
+func isPrefixCovered(prefix netip.Prefix, excepts []netip.Prefix) bool {
+	overlaps := false
+	for _, except := range excepts {
+		if except.Bits() <= prefix.Bits() && except.Contains(prefix.Addr()) {
+			return true
+		}
+		if except.Bits() > prefix.Bits() && prefix.Contains(except.Addr()) {
+			overlaps = true
+		}
+	}
+	if !overlaps {
+		return false
+	}
+
+	bits := prefix.Bits() + 1
+	upper := prefix.Addr().AsSlice()
+	upper[prefix.Bits()/8] |= 0x80 >> (prefix.Bits() % 8)
+	upperAddr, _ := netip.AddrFromSlice(upper)
+	return isPrefixCovered(netip.PrefixFrom(prefix.Addr(), bits), excepts) &&
+		isPrefixCovered(netip.PrefixFrom(upperAddr, bits), excepts)
+}
+
+// isIPBlockFullyExcepted reports whether the except entries cover the whole CIDR, so the peer matches no address
+func isIPBlockFullyExcepted(ipBlock *networkingv1.IPBlock) bool {
+	cidr, err := netip.ParsePrefix(ipBlock.CIDR)
+	if err != nil {
+		return false
+	}
+	var excepts []netip.Prefix
+	for _, except := range ipBlock.Except {
+		if exceptPrefix, err := netip.ParsePrefix(except); err == nil {
+			excepts = append(excepts, exceptPrefix.Masked())
+		}
+	}
+	return isPrefixCovered(cidr.Masked(), excepts)
+}
+
+// retrievePeerIssue explains why a peer matches nothing, for the peers kor can judge without a pod selector,
+// or returns an empty string
+func retrievePeerIssue(clientset kubernetes.Interface, cache *clusterCache, peer networkingv1.NetworkPolicyPeer) (string, error) {
+	if peer.IPBlock != nil {
+		if isIPBlockFullyExcepted(peer.IPBlock) {
+			return fmt.Sprintf("ipBlock %s is entirely excepted", peer.IPBlock.CIDR), nil
+		}
+		return "", nil
+	}
+	if peer.NamespaceSelector == nil || peer.PodSelector != nil {
+		return "", nil
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(peer.NamespaceSelector)
+	if err != nil {
+		return "", err
+	}
+	namespaces, err := cache.retrieveNamespaces(clientset)
+	if err != nil {
+		return "", err
+	}
+	if !slices.ContainsFunc(namespaces, func(ns v1.Namespace) bool { return labelSelector.Matches(labels.Set(ns.Labels)) }) {
+		return fmt.Sprintf("namespaceSelector %s matches no namespace", metav1.FormatLabelSelector(peer.NamespaceSelector)), nil
+	}
+	return "", nil
+}
+
+func formatNetworkPolicyPort(port networkingv1.NetworkPolicyPort) string {
+	protocol := v1.ProtocolTCP
+	if port.Protocol != nil {
+		protocol = *port.Protocol
+	}
+	if port.EndPort != nil {
+		return fmt.Sprintf("%s-%d/%s", port.Port.String(), *port.EndPort, protocol)
+	}
+	return fmt.Sprintf("%s/%s", port.Port.String(), protocol)
+}
+
+// isPortExposed reports whether a container of the selected pods declares a port matching a rule port
+func isPortExposed(pods []v1.Pod, port networkingv1.NetworkPolicyPort) bool {
+	// A rule port without a port number matches all ports of its protocol
+	if port.Port == nil {
+		return true
+	}
+	// Declaring container ports is optional, so pods that declare none may listen on any port
+	if !slices.ContainsFunc(pods, func(pod v1.Pod) bool {
+		return slices.ContainsFunc(slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers), func(container v1.Container) bool {
+			return len(container.Ports) > 0
+		})
+	}) {
+		return true
+	}
+	protocol := v1.ProtocolTCP
+	if port.Protocol != nil {
+		protocol = *port.Protocol
+	}
+	endPort := port.Port.IntVal
+	if port.EndPort != nil {
+		endPort = *port.EndPort
+	}
+
+	for _, pod := range pods {
+		for _, container := range slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers) {
+			for _, containerPort := range container.Ports {
+				if cmp.Or(containerPort.Protocol, v1.ProtocolTCP) != protocol {
+					continue
+				}
+				if port.Port.Type == intstr.String {
+					if containerPort.Name == port.Port.StrVal {
+						return true
+					}
+					continue
+				}
+				if containerPort.ContainerPort >= port.Port.IntVal && containerPort.ContainerPort <= endPort {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// retrieveNetworkPolicyRuleIssues describes the peers and ports of a used policy's rules that match nothing.
+// Egress ports refer to the peers' containers, so only ingress ports are checked against the selected pods.
+func retrieveNetworkPolicyRuleIssues(clientset kubernetes.Interface, cache *clusterCache, netpol networkingv1.NetworkPolicy, pods []v1.Pod) ([]string, error) {
+	var issues []string
+	for i, rule := range netpol.Spec.Ingress {
+		for _, peer := range rule.From {
+			issue, err := retrievePeerIssue(clientset, cache, peer)
+			if err != nil {
+				return nil, err
+			}
+			if issue != "" {
+				issues = append(issues, fmt.Sprintf("ingress rule %d %s", i+1, issue))
+			}
+		}
+		for _, port := range rule.Ports {
+			if !isPortExposed(pods, port) {
+				issues = append(issues, fmt.Sprintf("ingress rule %d port %s is exposed by no selected container", i+1, formatNetworkPolicyPort(port)))
+			}
+		}
+	}
+	for i, rule := range netpol.Spec.Egress {
+		for _, peer := range rule.To {
+			issue, err := retrievePeerIssue(clientset, cache, peer)
+			if err != nil {
+				return nil, err
+			}
+			if issue != "" {
+				issues = append(issues, fmt.Sprintf("egress rule %d %s", i+1, issue))
+			}
+		}
+	}
+	return issues, nil
+}
+
+// retrievePolicyTypes returns the policy types of a NetworkPolicy, defaulting them the way the API server does
+func retrievePolicyTypes(netpol networkingv1.NetworkPolicy) []networkingv1.PolicyType {
+	if len(netpol.Spec.PolicyTypes) > 0 {
+		return netpol.Spec.PolicyTypes
+	}
+	policyTypes := []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	if len(netpol.Spec.Egress) > 0 {
+		policyTypes = append(policyTypes, networkingv1.PolicyTypeEgress)
+	}
+	return policyTypes
+}
+
+// isSubsetOf reports whether every item of covered is semantically equal to an item of items,
+// where an empty items list matches everything
+func isSubsetOf[T any](items, covered []T) bool {
+	if len(items) == 0 {
+		return true
+	}
+	if len(covered) == 0 {
+		return false
+	}
+	for _, item := range covered {
+		if !slices.ContainsFunc(items, func(candidate T) bool {
+			return equality.Semantic.DeepEqual(candidate, item)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// isNetworkPolicyShadowedBy reports whether policy selects all pods of netpol, isolates them the same way
+// and allows at least the traffic allowed by each of netpol's rules, making netpol redundant
+func isNetworkPolicyShadowedBy(netpol, policy networkingv1.NetworkPolicy, selectedPods map[string][]v1.Pod) bool {
+	for _, policyType := range retrievePolicyTypes(netpol) {
+		if !slices.Contains(retrievePolicyTypes(policy), policyType) {
+			return false
+		}
+	}
+
+	for _, pod := range selectedPods[netpol.Name] {
+		if !slices.ContainsFunc(selectedPods[policy.Name], func(selected v1.Pod) bool { return selected.Name == pod.Name }) {
+			return false
+		}
+	}
+
+	for _, rule := range netpol.Spec.Ingress {
+		if !slices.ContainsFunc(policy.Spec.Ingress, func(candidate networkingv1.NetworkPolicyIngressRule) bool {
+			return isSubsetOf(candidate.From, rule.From) && isSubsetOf(candidate.Ports, rule.Ports)
+		}) {
+			return false
+		}
+	}
+	for _, rule := range netpol.Spec.Egress {
+		if !slices.ContainsFunc(policy.Spec.Egress, func(candidate networkingv1.NetworkPolicyEgressRule) bool {
+			return isSubsetOf(candidate.To, rule.To) && isSubsetOf(candidate.Ports, rule.Ports)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// retrieveShadowingNetworkPolicy returns the name of a policy that makes netpol redundant, or an empty string.
+// Of two equivalent policies only the one sorting last is reported, so that one of them is kept.
+func retrieveShadowingNetworkPolicy(netpol networkingv1.NetworkPolicy, netpols []networkingv1.NetworkPolicy, selectedPods map[string][]v1.Pod) string {
+	for _, policy := range netpols {
+		if policy.Name == netpol.Name || !isNetworkPolicyShadowedBy(netpol, policy, selectedPods) {
+			continue
+		}
+		if isNetworkPolicyShadowedBy(policy, netpol, selectedPods) && netpol.Name < policy.Name {
+			continue
+		}
+		return policy.Name
+	}
+	return ""
+}
+
 func isAnyPodMatchedInSources(clientset kubernetes.Interface, sources []networkingv1.NetworkPolicyPeer) (bool, error) {
 	// If this field is empty or missing, this rule matches all pods
 	if len(sources) == 0 {
@@ -45,8 +284,11 @@ func isAnyPodMatchedInSources(clientset kubernetes.Interface, sources []networki
 	}
 
 	for _, netpolPeer := range sources {
-		// If ipBlock is specified, assume the source is in use
+		// If ipBlock is specified, assume the source is in use unless it excepts its whole CIDR
 		if netpolPeer.IPBlock != nil {
+			if isIPBlockFullyExcepted(netpolPeer.IPBlock) {
+				continue
+			}
 			return true, nil
 		}
 
@@ -116,13 +358,15 @@ func isAnyEgressRuleUsed(clientset kubernetes.Interface, netpol networkingv1.Net
 	return false, nil
 }
 
-func processNamespaceNetworkPolicies(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+func processNamespaceNetworkPolicies(clientset kubernetes.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
 	netpolList, err := clientset.NetworkingV1().NetworkPolicies(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
 	if err != nil {
 		return nil, err
 	}
 
 	var unusedNetpols []ResourceInfo
+	var usedNetpols []networkingv1.NetworkPolicy
+	selectedPods := make(map[string][]v1.Pod)
 
 	for _, netpol := range netpolList.Items {
 		if pass, _ := filter.SetObject(&netpol).Run(filterOpts); pass {
@@ -148,34 +392,57 @@ func processNamespaceNetworkPolicies(clientset kubernetes.Interface, namespace s
 			unusedNetpols = append(unusedNetpols, ResourceInfo{Name: netpol.Name, Reason: noPodAppliedReason})
 			continue
 		}
+		selectedPods[netpol.Name] = pods
 
 		if used, err := isAnyIngressRuleUsed(clientset, netpol); err != nil {
 			return nil, err
 		} else if used {
+			usedNetpols = append(usedNetpols, netpol)
 			continue
 		}
 
 		if used, err := isAnyEgressRuleUsed(clientset, netpol); err != nil {
 			return nil, err
 		} else if used {
+			usedNetpols = append(usedNetpols, netpol)
 			continue
 		}
 
 		unusedNetpols = append(unusedNetpols, ResourceInfo{Name: netpol.Name, Reason: noPodAppliedByRulesReason})
 	}
+	// Shadowing policies may be narrowed later and peers may start matching new namespaces
+	for _, netpol := range usedNetpols {
+		if shadowing := retrieveShadowingNetworkPolicy(netpol, usedNetpols, selectedPods); shadowing != "" {
+			reason := fmt.Sprintf("NetworkPolicy is shadowed by %s, which selects all of its pods with a superset of its rules", shadowing)
+			unusedNetpols = append(unusedNetpols, ResourceInfo{Name: netpol.Name, Reason: reason, ReportOnly: true})
+			continue
+		}
+
+		issues, err := retrieveNetworkPolicyRuleIssues(clientset, cache, netpol, selectedPods[netpol.Name])
+		if err != nil {
+			return nil, err
+		}
+		if len(issues) > 0 {
+			reason := "NetworkPolicy has peers/ports that match nothing: " + strings.Join(issues, "; ")
+			unusedNetpols = append(unusedNetpols, ResourceInfo{Name: netpol.Name, Reason: reason, ReportOnly: true})
+		}
+	}
+
 	if opts.DeleteFlag {
-		if unusedNetpols, err := DeleteResource(unusedNetpols, clientset, namespace, "NetworkPolicy", opts.NoInteractive); err != nil {
+		if unusedNetpols, err = DeleteResource(unusedNetpols, clientset, namespace, "NetworkPolicy", opts.NoInteractive); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to delete NetworkPolicy %s in namespace %s: %v\n", unusedNetpols, namespace, err)
 		}
 	}
+
 	return unusedNetpols, nil
 }
 
 func GetUnusedNetworkPolicies(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
 	resources := make(map[string]map[string][]ResourceInfo)
+	cache := newClusterCache()
 
 	for _, namespace := range filterOpts.Namespaces(clientset) {
-		diff, err := processNamespaceNetworkPolicies(clientset, namespace, filterOpts, opts)
+		diff, err := processNamespaceNetworkPolicies(clientset, cache, namespace, filterOpts, opts)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to process namespace %s: %v\n", namespace, err)
 			continue
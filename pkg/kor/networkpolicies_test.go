@@ -10,6 +10,7 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 
@@ -241,7 +242,7 @@ func TestIsAnyEgressRuleUsed(t *testing.T) {
 func TestProcessNamespaceNetworkPolicies(t *testing.T) {
 	clientset := createTestNetworkPolicies(t)
 
-	unusedNetpols, err := processNamespaceNetworkPolicies(clientset, testNamespace, &filters.Options{}, common.Opts{})
+	unusedNetpols, err := processNamespaceNetworkPolicies(clientset, newClusterCache(), testNamespace, &filters.Options{}, common.Opts{})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -251,6 +252,10 @@ func TestProcessNamespaceNetworkPolicies(t *testing.T) {
 		"netpol-3",
 		"netpol-7",
 		"netpol-9",
+		// shadowed by the allow-all ingress of netpol-5
+		"netpol-4",
+		"netpol-6",
+		"netpol-8",
 	}
 
 	if len(unusedNetpols) != len(expectedUnusedNetpols) {
@@ -268,7 +273,7 @@ func TestProcessNamespaceNetworkPoliciesWithOwnerReferences(t *testing.T) {
 	clientset := createTestNetworkPoliciesWithOwnerReferences(t)
 
 	// --ignore-owner-references=false (varsayılan)
-	unusedNetpols, err := processNamespaceNetworkPolicies(clientset, testNamespace, &filters.Options{}, common.Opts{})
+	unusedNetpols, err := processNamespaceNetworkPolicies(clientset, newClusterCache(), testNamespace, &filters.Options{}, common.Opts{})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -277,7 +282,7 @@ func TestProcessNamespaceNetworkPoliciesWithOwnerReferences(t *testing.T) {
 	}
 
 	// --ignore-owner-references=true
-	unusedNetpols, err = processNamespaceNetworkPolicies(clientset, testNamespace, &filters.Options{IgnoreOwnerReferences: true}, common.Opts{})
+	unusedNetpols, err = processNamespaceNetworkPolicies(clientset, newClusterCache(), testNamespace, &filters.Options{IgnoreOwnerReferences: true}, common.Opts{})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -313,6 +318,9 @@ func TestGetUnusedNetworkPolicies(t *testing.T) {
 				"netpol-3",
 				"netpol-7",
 				"netpol-9",
+				"netpol-4",
+				"netpol-6",
+				"netpol-8",
 			},
 		},
 	}
@@ -333,3 +341,74 @@ func init() {
 	scheme.Scheme = runtime.NewScheme()
 	_ = networkingv1.AddToScheme(scheme.Scheme)
 }
+
+func TestProcessNamespaceNetworkPoliciesRuleAnalysis(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	_, err := clientset.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{
+		ObjectMeta: v1.ObjectMeta{Name: testNamespace, Labels: map[string]string{"team": "a"}},
+	}, v1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Error creating namespace %s: %v", testNamespace, err)
+	}
+
+	webLabels := map[string]string{"app": "web"}
+	pod := CreateTestPod(testNamespace, "web", "", nil, webLabels)
+	pod.Spec.Containers = []corev1.Container{{Name: "web", Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}}}}
+	if _, err := clientset.CoreV1().Pods(testNamespace).Create(context.TODO(), pod, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake pod: %v", err)
+	}
+	// Pods declaring no container ports may listen on any port
+	workerLabels := map[string]string{"app": "worker"}
+	if _, err := clientset.CoreV1().Pods(testNamespace).Create(context.TODO(), CreateTestPod(testNamespace, "worker", "", nil, workerLabels), v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake pod: %v", err)
+	}
+
+	webPeer := networkingv1.NetworkPolicyPeer{PodSelector: v1.SetAsLabelSelector(webLabels)}
+	nsPeerIngress := []networkingv1.NetworkPolicyIngressRule{{
+		From: []networkingv1.NetworkPolicyPeer{
+			{NamespaceSelector: v1.SetAsLabelSelector(map[string]string{"team": "missing"})},
+			webPeer,
+		},
+	}}
+	port := func(port intstr.IntOrString) networkingv1.NetworkPolicyPort {
+		return networkingv1.NetworkPolicyPort{Port: &port}
+	}
+
+	netpols := []*networkingv1.NetworkPolicy{
+		CreateTestNetworkPolicy("ipblock", testNamespace, AppLabels, *v1.SetAsLabelSelector(webLabels), nil, []networkingv1.NetworkPolicyEgressRule{
+			{To: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/24", Except: []string{"10.0.0.0/25", "10.0.0.128/25"}}}}},
+			{To: []networkingv1.NetworkPolicyPeer{webPeer}},
+		}),
+		CreateTestNetworkPolicy("ns-peer", testNamespace, AppLabels, *v1.SetAsLabelSelector(webLabels), nsPeerIngress, nil),
+		CreateTestNetworkPolicy("ports", testNamespace, AppLabels, *v1.SetAsLabelSelector(webLabels), []networkingv1.NetworkPolicyIngressRule{{
+			From:  []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}}},
+			Ports: []networkingv1.NetworkPolicyPort{port(intstr.FromInt32(8080)), port(intstr.FromInt32(9090)), port(intstr.FromString("http"))},
+		}}, nil),
+		CreateTestNetworkPolicy("undeclared-ports", testNamespace, AppLabels, *v1.SetAsLabelSelector(workerLabels), []networkingv1.NetworkPolicyIngressRule{{
+			From:  []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}}},
+			Ports: []networkingv1.NetworkPolicyPort{port(intstr.FromInt32(9090))},
+		}}, nil),
+		CreateTestNetworkPolicy("z-duplicate", testNamespace, AppLabels, *v1.SetAsLabelSelector(webLabels), nsPeerIngress, nil),
+	}
+	for _, netpol := range netpols {
+		if _, err := clientset.NetworkingV1().NetworkPolicies(testNamespace).Create(context.TODO(), netpol, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake networkpolicy: %v", err)
+		}
+	}
+
+	unusedNetpols, err := processNamespaceNetworkPolicies(clientset, newClusterCache(), testNamespace, &filters.Options{}, common.Opts{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "ipblock", Reason: "NetworkPolicy has peers/ports that match nothing: egress rule 1 ipBlock 10.0.0.0/24 is entirely excepted", ReportOnly: true},
+		{Name: "ns-peer", Reason: "NetworkPolicy has peers/ports that match nothing: ingress rule 1 namespaceSelector team=missing matches no namespace", ReportOnly: true},
+		{Name: "ports", Reason: "NetworkPolicy has peers/ports that match nothing: ingress rule 1 port 9090/TCP is exposed by no selected container", ReportOnly: true},
+		{Name: "z-duplicate", Reason: "NetworkPolicy is shadowed by ns-peer, which selects all of its pods with a superset of its rules", ReportOnly: true},
+	}
+	if !reflect.DeepEqual(expected, unusedNetpols) {
+		t.Errorf("Expected %v, got %v", expected, unusedNetpols)
+	}
+}
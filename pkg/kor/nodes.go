@@ -0,0 +1,191 @@
+package kor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+const controlPlaneNodeLabel = "node-role.kubernetes.io/control-plane"
+
+// retrieveNodeReadyCondition returns the Ready condition of the node, or nil if the kubelet never reported it
+func retrieveNodeReadyCondition(node corev1.Node) *corev1.NodeCondition {
+	for i, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return &node.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// retrieveNodeCordonTime returns when the node was marked unschedulable, preferring the taint timestamp
+// and falling back to the last managedFields update of spec.unschedulable
+func retrieveNodeCordonTime(node corev1.Node) *time.Time {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == corev1.TaintNodeUnschedulable && taint.TimeAdded != nil {
+			return &taint.TimeAdded.Time
+		}
+	}
+
+	var cordonTime *time.Time
+	for _, entry := range node.ManagedFields {
+		if entry.Time == nil || entry.FieldsV1 == nil || !strings.Contains(string(entry.FieldsV1.Raw), `"f:unschedulable"`) {
+			continue
+		}
+		if cordonTime == nil || entry.Time.After(*cordonTime) {
+			cordonTime = &entry.Time.Time
+		}
+	}
+	return cordonTime
+}
+
+func describeNode(node corev1.Node) string {
+	heartbeat := "never"
+	if ready := retrieveNodeReadyCondition(node); ready != nil && !ready.LastHeartbeatTime.IsZero() {
+		heartbeat = ready.LastHeartbeatTime.UTC().Format(time.RFC3339)
+	}
+
+	taints := make([]string, 0, len(node.Spec.Taints))
+	for _, taint := range node.Spec.Taints {
+		taints = append(taints, taint.ToString())
+	}
+	if len(taints) == 0 {
+		taints = append(taints, "none")
+	}
+
+	return fmt.Sprintf("last heartbeat %s, taints: %s", heartbeat, strings.Join(taints, ", "))
+}
+
+// retrieveNodesWithWorkload returns the nodes running active pods, set to true for those running at least one pod
+// that is neither a DaemonSet pod nor a static pod
+func retrieveNodesWithWorkload(clientset kubernetes.Interface) (map[string]bool, error) {
+	pods, err := clientset.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Pods: %v", err)
+	}
+
+	nodesWithWorkload := make(map[string]bool)
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		isNodeLocal := false
+		for _, owner := range pod.OwnerReferences {
+			if owner.Kind == "DaemonSet" || owner.Kind == "Node" {
+				isNodeLocal = true
+				break
+			}
+		}
+		nodesWithWorkload[pod.Spec.NodeName] = nodesWithWorkload[pod.Spec.NodeName] || !isNodeLocal
+	}
+
+	return nodesWithWorkload, nil
+}
+
+func processNodes(clientset kubernetes.Interface, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+	nodes, err := clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
+	if err != nil {
+		return nil, err
+	}
+
+	nodesWithWorkload, err := retrieveNodesWithWorkload(clientset)
+	if err != nil {
+		return nil, err
+	}
+
+	var unusedNodes []ResourceInfo
+	now := time.Now()
+	cordonedThreshold := time.Duration(opts.NodeCordonedDays) * 24 * time.Hour
+
+	for _, node := range nodes.Items {
+		if pass, _ := filter.SetObject(&node).Run(filterOpts); pass {
+			continue
+		}
+
+		if ready := retrieveNodeReadyCondition(node); ready == nil || ready.Status != corev1.ConditionTrue {
+			notReadyFor := now.Sub(node.CreationTimestamp.Time)
+			if ready != nil && !ready.LastTransitionTime.IsZero() {
+				notReadyFor = now.Sub(ready.LastTransitionTime.Time)
+			}
+			if notReadyFor >= opts.NodeNotReadyThreshold {
+				reason := fmt.Sprintf("NotReady for %s (%s)", duration.HumanDuration(notReadyFor), describeNode(node))
+				unusedNodes = append(unusedNodes, ResourceInfo{Name: node.Name, Reason: reason, ReportOnly: true})
+			}
+			continue
+		}
+
+		if node.Spec.Unschedulable {
+			if cordonTime := retrieveNodeCordonTime(node); cordonTime != nil && now.Sub(*cordonTime) >= cordonedThreshold {
+				reason := fmt.Sprintf("Cordoned for %s (%s)", duration.HumanDuration(now.Sub(*cordonTime)), describeNode(node))
+				unusedNodes = append(unusedNodes, ResourceInfo{Name: node.Name, Reason: reason, ReportOnly: true})
+			}
+			continue
+		}
+
+		// Control plane nodes usually only run static and DaemonSet pods by design
+		if _, isControlPlane := node.Labels[controlPlaneNodeLabel]; isControlPlane {
+			continue
+		}
+
+		hasWorkload, hasPods := nodesWithWorkload[node.Name]
+		if !hasPods {
+			reason := fmt.Sprintf("Runs no pods (%s)", describeNode(node))
+			unusedNodes = append(unusedNodes, ResourceInfo{Name: node.Name, Reason: reason, ReportOnly: true})
+		} else if !hasWorkload {
+			reason := fmt.Sprintf("Runs only DaemonSet pods (%s)", describeNode(node))
+			unusedNodes = append(unusedNodes, ResourceInfo{Name: node.Name, Reason: reason, ReportOnly: true})
+		}
+	}
+
+	return unusedNodes, nil
+}
+
+func GetUnusedNodes(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
+	resources := make(map[string]map[string][]ResourceInfo)
+	diff, err := processNodes(clientset, filterOpts, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to process nodes: %v\n", err)
+	}
+	if opts.DeleteFlag {
+		fmt.Fprintln(os.Stderr, "Nodes are reported only, skipping deletion")
+	}
+	switch opts.GroupBy {
+	case "namespace":
+		resources[""] = make(map[string][]ResourceInfo)
+		resources[""]["Node"] = diff
+	case "resource":
+		appendResources(resources, "Node", "", diff)
+	}
+
+	var outputBuffer bytes.Buffer
+	var jsonResponse []byte
+	switch outputFormat {
+	case "table":
+		outputBuffer = FormatOutput(resources, opts)
+	case "json", "yaml":
+		var err error
+		if jsonResponse, err = json.MarshalIndent(resources, "", "  "); err != nil {
+			return "", err
+		}
+	}
+
+	unusedNodes, err := unusedResourceFormatter(outputFormat, outputBuffer, opts, jsonResponse)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+	}
+
+	return unusedNodes, nil
+}
@@ -0,0 +1,156 @@
+package kor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func createTestNodeWithStatus(name string, ready corev1.ConditionStatus, transitioned time.Time) *corev1.Node {
+	node := CreateTestNode(name)
+	node.Status.Conditions = []corev1.NodeCondition{
+		{
+			Type:               corev1.NodeReady,
+			Status:             ready,
+			LastHeartbeatTime:  v1.NewTime(transitioned),
+			LastTransitionTime: v1.NewTime(transitioned),
+		},
+	}
+	return node
+}
+
+func createTestNodeResources(t *testing.T) *fake.Clientset {
+	clientset := fake.NewClientset()
+	now := time.Now()
+
+	cordoned := createTestNodeWithStatus("cordoned-node", corev1.ConditionTrue, now)
+	cordoned.Spec.Unschedulable = true
+	cordoned.Spec.Taints = []corev1.Taint{
+		{Key: corev1.TaintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule, TimeAdded: &v1.Time{Time: now.Add(-10 * 24 * time.Hour)}},
+	}
+
+	recentlyCordoned := createTestNodeWithStatus("recently-cordoned-node", corev1.ConditionTrue, now)
+	recentlyCordoned.Spec.Unschedulable = true
+	recentlyCordoned.Spec.Taints = []corev1.Taint{
+		{Key: corev1.TaintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule, TimeAdded: &v1.Time{Time: now.Add(-time.Hour)}},
+	}
+
+	controlPlane := createTestNodeWithStatus("control-plane-node", corev1.ConditionTrue, now)
+	controlPlane.Labels = map[string]string{controlPlaneNodeLabel: ""}
+
+	nodes := []*corev1.Node{
+		createTestNodeWithStatus("busy-node", corev1.ConditionTrue, now),
+		createTestNodeWithStatus("daemonset-only-node", corev1.ConditionTrue, now),
+		createTestNodeWithStatus("empty-node", corev1.ConditionTrue, now),
+		createTestNodeWithStatus("not-ready-node", corev1.ConditionUnknown, now.Add(-3*time.Hour)),
+		createTestNodeWithStatus("flapping-node", corev1.ConditionFalse, now.Add(-time.Minute)),
+		cordoned,
+		recentlyCordoned,
+		controlPlane,
+	}
+	for _, node := range nodes {
+		if _, err := clientset.CoreV1().Nodes().Create(context.TODO(), node, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake node: %v", err)
+		}
+	}
+
+	daemonSetPod := func(name, nodeName string) *corev1.Pod {
+		pod := CreateTestPod(testNamespace, name, "", nil, AppLabels)
+		pod.Spec.NodeName = nodeName
+		pod.OwnerReferences = []v1.OwnerReference{{Kind: "DaemonSet", Name: "agent", APIVersion: appsv1.SchemeGroupVersion.String()}}
+		return pod
+	}
+	workloadPod := CreateTestPod(testNamespace, "workload", "", nil, AppLabels)
+	workloadPod.Spec.NodeName = "busy-node"
+	completedPod := CreateTestPod(testNamespace, "completed", "", nil, AppLabels)
+	completedPod.Spec.NodeName = "daemonset-only-node"
+	completedPod.Status.Phase = corev1.PodSucceeded
+
+	pods := []*corev1.Pod{
+		workloadPod,
+		completedPod,
+		daemonSetPod("agent-busy", "busy-node"),
+		daemonSetPod("agent-idle", "daemonset-only-node"),
+	}
+	for _, pod := range pods {
+		if _, err := clientset.CoreV1().Pods(testNamespace).Create(context.TODO(), pod, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake pod: %v", err)
+		}
+	}
+
+	return clientset
+}
+
+func TestProcessNodes(t *testing.T) {
+	clientset := createTestNodeResources(t)
+
+	// The default thresholds are 1h NotReady and 7 days cordoned
+	unusedNodes, err := processNodes(clientset, &filters.Options{}, common.NewOpts())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := map[string]string{
+		"cordoned-node":       "Cordoned for 10d",
+		"daemonset-only-node": "Runs only DaemonSet pods",
+		"empty-node":          "Runs no pods",
+		"not-ready-node":      "NotReady for 3h",
+	}
+
+	if len(unusedNodes) != len(expected) {
+		t.Fatalf("Expected %d unused nodes, got %d: %v", len(expected), len(unusedNodes), unusedNodes)
+	}
+
+	for _, node := range unusedNodes {
+		prefix, ok := expected[node.Name]
+		if !ok {
+			t.Errorf("Unexpected node %s reported: %s", node.Name, node.Reason)
+			continue
+		}
+		if !strings.HasPrefix(node.Reason, prefix) {
+			t.Errorf("Expected reason for %s to start with %q, got %q", node.Name, prefix, node.Reason)
+		}
+		if !strings.Contains(node.Reason, "last heartbeat") || !strings.Contains(node.Reason, "taints:") {
+			t.Errorf("Expected reason for %s to include heartbeat and taints, got %q", node.Name, node.Reason)
+		}
+		if !node.ReportOnly {
+			t.Errorf("Expected node %s to be report-only", node.Name)
+		}
+	}
+}
+
+func TestRetrieveNodeCordonTime(t *testing.T) {
+	cordonTime := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+
+	node := CreateTestNode("cordoned-node")
+	node.Spec.Unschedulable = true
+	node.ManagedFields = []v1.ManagedFieldsEntry{
+		{
+			Manager:  "kubelet",
+			Time:     &v1.Time{Time: time.Now()},
+			FieldsV1: &v1.FieldsV1{Raw: []byte(`{"f:status":{"f:conditions":{}}}`)},
+		},
+		{
+			Manager:  "kubectl-cordon",
+			Time:     &v1.Time{Time: cordonTime},
+			FieldsV1: &v1.FieldsV1{Raw: []byte(`{"f:spec":{"f:unschedulable":{}}}`)},
+		},
+	}
+
+	if result := retrieveNodeCordonTime(*node); result == nil || !result.Equal(cordonTime) {
+		t.Errorf("Expected cordon time %v from managedFields, got %v", cordonTime, result)
+	}
+
+	if result := retrieveNodeCordonTime(*CreateTestNode("uncordoned-node")); result != nil {
+		t.Errorf("Expected no cordon time, got %v", result)
+	}
+}
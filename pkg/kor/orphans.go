@@ -0,0 +1,179 @@
+package kor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func missingOwnerReason(owner metav1.OwnerReference) string {
+	return fmt.Sprintf("Owner %s %s no longer exists", owner.Kind, owner.Name)
+}
+
+// retrieveOrphanedResources lists every listable resource once, collecting all UIDs, and then
+// reports objects whose ownerReferences point at UIDs that were not seen. As the lists are not taken
+// at the same time, every owner that was not seen is looked up again before its objects are reported.
+func retrieveOrphanedResources(resourceTypes []*metav1.APIResourceList, discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface, filterOpts *filters.Options) (map[string]map[schema.GroupVersionResource][]ResourceInfo, error) {
+	orphanedResources := make(map[string]map[schema.GroupVersionResource][]ResourceInfo) //map[namespace]map[gvr][]resourceNames
+	existingUIDs := make(map[types.UID]bool)
+	objects := make(map[schema.GroupVersionResource][]unstructured.Unstructured)
+	apiResources := make(map[string][]metav1.APIResource)
+	// Owners that could not be looked up can't be verified, their objects are left out and the kinds reported
+	unverifiedKinds := make(map[string]bool)
+
+	for _, apiResourceList := range resourceTypes {
+		gv, err := schema.ParseGroupVersion(apiResourceList.GroupVersion)
+		if err != nil {
+			return orphanedResources, err
+		}
+		apiResources[apiResourceList.GroupVersion] = apiResourceList.APIResources
+
+		for _, resourceType := range apiResourceList.APIResources {
+			if strings.Contains(resourceType.Name, "/") {
+				continue
+			}
+
+			if !slices.Contains(resourceType.Verbs, "list") {
+				continue
+			}
+
+			gvr := gv.WithResource(resourceType.Name)
+			resourceList, err := dynamicClient.
+				Resource(gvr).
+				Namespace(metav1.NamespaceAll).
+				List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				fmt.Printf("Error listing resources for GVR %s: %v\n", gvr.String(), err)
+				continue
+			}
+
+			for _, item := range resourceList.Items {
+				existingUIDs[item.GetUID()] = true
+				if len(item.GetOwnerReferences()) > 0 {
+					objects[gvr] = append(objects[gvr], item)
+				}
+			}
+		}
+	}
+
+	for gvr, items := range objects {
+		for _, item := range items {
+			if pass, _ := filter.SetObject(&item).Run(filterOpts); pass {
+				continue
+			}
+
+			// An object is only orphaned once none of its owners exist, the garbage collector keeps it otherwise
+			var reasons []string
+			for _, owner := range item.GetOwnerReferences() {
+				if existingUIDs[owner.UID] {
+					reasons = nil
+					break
+				}
+				exists, err := ownerReferenceExists(discoveryClient, dynamicClient, apiResources, item.GetNamespace(), owner)
+				if err != nil {
+					unverifiedKinds[owner.Kind+"."+owner.APIVersion] = true
+				}
+				if err != nil || exists {
+					reasons = nil
+					break
+				}
+				reasons = append(reasons, missingOwnerReason(owner))
+			}
+			if len(reasons) == 0 {
+				continue
+			}
+
+			namespace := item.GetNamespace()
+			if orphanedResources[namespace] == nil {
+				orphanedResources[namespace] = make(map[schema.GroupVersionResource][]ResourceInfo)
+			}
+			orphanInfo := ResourceInfo{
+				Name:   item.GetName(),
+				Reason: strings.Join(reasons, ", "),
+			}
+			orphanedResources[namespace][gvr] = append(orphanedResources[namespace][gvr], orphanInfo)
+		}
+	}
+
+	if len(unverifiedKinds) > 0 {
+		fmt.Fprintf(os.Stderr, "Could not look up owners of kind %s, the objects they own were not checked\n", strings.Join(slices.Sorted(maps.Keys(unverifiedKinds)), ", "))
+	}
+
+	return orphanedResources, nil
+}
+
+func getOrphanedResources(clientset kubernetes.Interface, dynamicClient dynamic.Interface, filterOpts *filters.Options) (map[string]map[schema.GroupVersionResource][]ResourceInfo, error) {
+	// Use the discovery client to fetch API resources
+	resourceTypes, err := clientset.Discovery().ServerPreferredResources()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching server resources: %v", err)
+	}
+
+	return retrieveOrphanedResources(resourceTypes, clientset.Discovery(), dynamicClient, filterOpts)
+}
+
+func GetOrphanedResources(filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
+	namespaces := filterOpts.Namespaces(clientset)
+	resources := make(map[string]map[string][]ResourceInfo)
+
+	orphanedDiffs, err := getOrphanedResources(clientset, dynamicClient, filterOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to process orphaned resources: %v\n", err)
+	}
+
+	for namespace, resourceTypes := range orphanedDiffs {
+		if !slices.Contains(namespaces, namespace) && namespace != metav1.NamespaceAll {
+			continue
+		}
+		for gvr, diff := range resourceTypes {
+			if opts.DeleteFlag {
+				if diff, err = DeleteDynamicResource(diff, dynamicClient, namespace, gvr, gvr.Resource, opts.NoInteractive); err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to delete orphaned %s %s in namespace %s: %v\n", gvr.Resource, diff, namespace, err)
+				}
+			}
+			switch opts.GroupBy {
+			case "namespace":
+				if resources[namespace] == nil {
+					resources[namespace] = make(map[string][]ResourceInfo)
+				}
+				resources[namespace][gvr.Resource] = diff
+			case "resource":
+				appendResources(resources, gvr.Resource, namespace, diff)
+			}
+		}
+	}
+
+	var outputBuffer bytes.Buffer
+	var jsonResponse []byte
+	switch outputFormat {
+	case "table":
+		outputBuffer = FormatOutput(resources, opts)
+	case "json", "yaml":
+		if jsonResponse, err = json.MarshalIndent(resources, "", "  "); err != nil {
+			return "", err
+		}
+	}
+
+	orphans, err := unusedResourceFormatter(outputFormat, outputBuffer, opts, jsonResponse)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+	}
+
+	return orphans, nil
+}
@@ -0,0 +1,94 @@
+package kor
+
+import (
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func TestRetrieveOrphanedResources(t *testing.T) {
+	deploymentsGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	replicaSetsGVR := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+
+	owner := func(kind, name string, uid types.UID) metav1.OwnerReference {
+		return metav1.OwnerReference{APIVersion: "apps/v1", Kind: kind, Name: name, UID: uid}
+	}
+
+	deployment := CreateTestUnstructered("Deployment", "apps/v1", testNamespace, "test-deploy")
+	deployment.SetUID("deploy-uid")
+
+	ownedReplicaSet := CreateTestUnstructered("ReplicaSet", "apps/v1", testNamespace, "owned-rs")
+	ownedReplicaSet.SetOwnerReferences([]metav1.OwnerReference{owner("Deployment", "test-deploy", "deploy-uid")})
+
+	orphanedReplicaSet := CreateTestUnstructered("ReplicaSet", "apps/v1", testNamespace, "orphaned-rs")
+	orphanedReplicaSet.SetOwnerReferences([]metav1.OwnerReference{owner("Deployment", "gone-deploy", "gone-uid")})
+
+	// Objects keep living as long as one of their owners does
+	partiallyOwnedReplicaSet := CreateTestUnstructered("ReplicaSet", "apps/v1", testNamespace, "partially-owned-rs")
+	partiallyOwnedReplicaSet.SetOwnerReferences([]metav1.OwnerReference{owner("Deployment", "gone-deploy", "gone-uid"), owner("Deployment", "test-deploy", "deploy-uid")})
+
+	unverifiableReplicaSet := CreateTestUnstructered("ReplicaSet", "apps/v1", testNamespace, "unverifiable-rs")
+	unverifiableReplicaSet.SetOwnerReferences([]metav1.OwnerReference{owner("StatefulSet", "hidden-sts", "hidden-uid")})
+
+	// Created after the Deployments were listed
+	lateDeployment := CreateTestUnstructered("Deployment", "apps/v1", testNamespace, "late-deploy")
+	lateDeployment.SetUID("late-uid")
+	lateReplicaSet := CreateTestUnstructered("ReplicaSet", "apps/v1", testNamespace, "late-rs")
+	lateReplicaSet.SetOwnerReferences([]metav1.OwnerReference{owner("Deployment", "late-deploy", "late-uid")})
+
+	gvrToListKind := map[schema.GroupVersionResource]string{
+		deploymentsGVR: "DeploymentList",
+		replicaSetsGVR: "ReplicaSetList",
+	}
+	dynamicClient := fakedynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), gvrToListKind, deployment, lateDeployment, ownedReplicaSet, orphanedReplicaSet, partiallyOwnedReplicaSet, unverifiableReplicaSet, lateReplicaSet)
+	dynamicClient.PrependReactor("list", "deployments", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DeploymentList"})
+		list.Items = []unstructured.Unstructured{*deployment}
+		return true, list, nil
+	})
+	dynamicClient.PrependReactor("get", "statefulsets", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "statefulsets"}, "hidden-sts", nil)
+	})
+
+	apiResourceLists := []*metav1.APIResourceList{
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", Kind: "Deployment", Verbs: []string{"list"}, Namespaced: true},
+				{Name: "deployments/scale", Kind: "Scale", Verbs: []string{"get"}, Namespaced: true},
+				{Name: "replicasets", Kind: "ReplicaSet", Verbs: []string{"list"}, Namespaced: true},
+				{Name: "statefulsets", Kind: "StatefulSet", Verbs: []string{"get"}, Namespaced: true},
+			},
+		},
+	}
+
+	orphans, err := retrieveOrphanedResources(apiResourceLists, fake.NewClientset().Discovery(), dynamicClient, &filters.Options{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []ResourceInfo{{Name: "orphaned-rs", Reason: "Owner Deployment gone-deploy no longer exists"}}
+	replicaSets := orphans[testNamespace][replicaSetsGVR]
+	if len(replicaSets) != len(expected) || replicaSets[0] != expected[0] {
+		t.Errorf("Expected %v, got %v", expected, replicaSets)
+	}
+	if len(orphans[testNamespace][deploymentsGVR]) != 0 {
+		t.Errorf("Expected no orphaned deployments, got %v", orphans[testNamespace][deploymentsGVR])
+	}
+
+	_, err = retrieveOrphanedResources([]*metav1.APIResourceList{{GroupVersion: "bad//api/version"}}, fake.NewClientset().Discovery(), dynamicClient, &filters.Options{})
+	if err == nil {
+		t.Error("Expected an error for an invalid group version")
+	}
+}
@@ -6,22 +6,88 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/yonahd/kor/pkg/common"
 	"github.com/yonahd/kor/pkg/filters"
 )
 
+// podWaitingReasons are container waiting reasons of pods that won't recover without intervention
+var podWaitingReasons = []string{"CrashLoopBackOff", "ImagePullBackOff", "CreateContainerConfigError"}
+
+// retrievePodFinishTime returns when the last container of a Succeeded or Failed pod terminated,
+// falling back to the pod start and creation time
+func retrievePodFinishTime(pod corev1.Pod) time.Time {
+	var finishedAt time.Time
+	for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		if terminated := status.State.Terminated; terminated != nil && terminated.FinishedAt.After(finishedAt) {
+			finishedAt = terminated.FinishedAt.Time
+		}
+	}
+	switch {
+	case !finishedAt.IsZero():
+		return finishedAt
+	case pod.Status.StartTime != nil:
+		return pod.Status.StartTime.Time
+	default:
+		return pod.CreationTimestamp.Time
+	}
+}
+
+// retrievePodFailureReason returns the pod status reason or the reason a container terminated with, e.g. OOMKilled
+func retrievePodFailureReason(pod corev1.Pod) string {
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason
+	}
+	for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 && terminated.Reason != "" {
+			return terminated.Reason
+		}
+	}
+	return ""
+}
+
+// retrievePodNotReadySince returns when the pod last became not ready, falling back to the pod start and creation time
+func retrievePodNotReadySince(pod corev1.Pod) time.Time {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status != corev1.ConditionTrue && !condition.LastTransitionTime.IsZero() {
+			return condition.LastTransitionTime.Time
+		}
+	}
+	if pod.Status.StartTime != nil {
+		return pod.Status.StartTime.Time
+	}
+	return pod.CreationTimestamp.Time
+}
+
+func retrieveContainerWaitingReason(pod corev1.Pod) (string, string) {
+	for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		if waiting := status.State.Waiting; waiting != nil && slices.Contains(podWaitingReasons, waiting.Reason) {
+			return status.Name, waiting.Reason
+		}
+	}
+	return "", ""
+}
+
+func isOwnedByJob(pod corev1.Pod) bool {
+	controller := metav1.GetControllerOf(&pod)
+	return controller != nil && controller.Kind == "Job"
+}
+
 func processNamespacePods(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
 	podsList, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
 	if err != nil {
 		return nil, err
 	}
 
-	var evictedPods []ResourceInfo
+	var unusedPods []ResourceInfo
+	now := time.Now()
 
 	for _, pod := range podsList.Items {
 		// Skip resources with ownerReferences if the general flag is set
@@ -35,28 +101,66 @@ func processNamespacePods(clientset kubernetes.Interface, namespace string, filt
 
 		if pod.Labels["kor/used"] == "false" {
 			reason := "Marked with unused label"
-			evictedPods = append(evictedPods, ResourceInfo{Name: pod.Name, Reason: reason})
+			unusedPods = append(unusedPods, ResourceInfo{Name: pod.Name, Reason: reason})
 			continue
 		}
 
 		if pod.Status.Phase == corev1.PodFailed && pod.Status.Reason == "Evicted" {
 			reason := "Pod is evicted"
-			evictedPods = append(evictedPods, ResourceInfo{Name: pod.Name, Reason: reason})
+			unusedPods = append(unusedPods, ResourceInfo{Name: pod.Name, Reason: reason})
+			continue
+		}
+
+		switch pod.Status.Phase {
+		case corev1.PodSucceeded, corev1.PodFailed:
+			// Pods of Jobs are kept for their logs and are cleaned up together with the Job
+			if isOwnedByJob(pod) {
+				continue
+			}
+			age := now.Sub(retrievePodFinishTime(pod))
+			if age < opts.PodFinishedThreshold {
+				continue
+			}
+			reason := fmt.Sprintf("Pod completed %s ago", duration.HumanDuration(age))
+			if pod.Status.Phase == corev1.PodFailed {
+				reason = fmt.Sprintf("Pod failed %s ago", duration.HumanDuration(age))
+				if failureReason := retrievePodFailureReason(pod); failureReason != "" {
+					reason = fmt.Sprintf("Pod failed (%s) %s ago", failureReason, duration.HumanDuration(age))
+				}
+			}
+			unusedPods = append(unusedPods, ResourceInfo{Name: pod.Name, Reason: reason})
+			continue
+		}
+
+		if container, waitingReason := retrieveContainerWaitingReason(pod); waitingReason != "" {
+			age := now.Sub(retrievePodNotReadySince(pod))
+			if age >= opts.PodWaitingThreshold {
+				reason := fmt.Sprintf("Container %s is in %s for %s", container, waitingReason, duration.HumanDuration(age))
+				// Nothing recreates a deleted pod without a controller, so it's left to be fixed in place
+				unusedPods = append(unusedPods, ResourceInfo{Name: pod.Name, Reason: reason, ReportOnly: metav1.GetControllerOf(&pod) == nil})
+			}
+			continue
 		}
 
-		if pod.Status.Phase == corev1.PodFailed && pod.Status.Reason == "CrashLoopBackOff" {
-			reason := "Pod is in CrashLoopBackOff"
-			evictedPods = append(evictedPods, ResourceInfo{Name: pod.Name, Reason: reason})
+		if pod.Status.Phase == corev1.PodPending {
+			age := now.Sub(pod.CreationTimestamp.Time)
+			if age >= opts.PodPendingThreshold {
+				reason := fmt.Sprintf("Pending for %s", duration.HumanDuration(age))
+				unusedPods = append(unusedPods, ResourceInfo{Name: pod.Name, Reason: reason, ReportOnly: metav1.GetControllerOf(&pod) == nil})
+			}
+			continue
 		}
 
+		if metav1.GetControllerOf(&pod) == nil {
+			unusedPods = append(unusedPods, ResourceInfo{Name: pod.Name, Reason: "Pod has no controller owner", ReportOnly: true})
+		}
 	}
 	if opts.DeleteFlag {
-		if evictedPods, err = DeleteResource(evictedPods, clientset, namespace, "Pod", opts.NoInteractive); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to delete Pod %s in namespace %s: %v\n", evictedPods, namespace, err)
+		if unusedPods, err = DeleteResource(unusedPods, clientset, namespace, "Pod", opts.NoInteractive); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to delete Pod %s in namespace %s: %v\n", unusedPods, namespace, err)
 		}
 	}
-
-	return evictedPods, nil
+	return unusedPods, nil
 }
 
 func GetUnusedPods(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"reflect"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -18,6 +19,7 @@ import (
 
 func createTestPods(t *testing.T) *fake.Clientset {
 	clientset := fake.NewClientset()
+	isController := true
 
 	_, err := clientset.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{
 		ObjectMeta: v1.ObjectMeta{Name: testNamespace},
@@ -28,6 +30,7 @@ func createTestPods(t *testing.T) *fake.Clientset {
 	}
 
 	pod1 := CreateTestPod(testNamespace, "pod-1", "", nil, AppLabels)
+	pod1.OwnerReferences = []v1.OwnerReference{{Kind: "ReplicaSet", Name: "test-replicaset", Controller: &isController}}
 	pod1.Status = corev1.PodStatus{
 		Phase:   corev1.PodRunning,
 		Reason:  "",
@@ -52,6 +55,9 @@ func createTestPods(t *testing.T) *fake.Clientset {
 		Phase:   corev1.PodSucceeded,
 		Reason:  "",
 		Message: "",
+		ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "main", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{FinishedAt: v1.Now()}}},
+		},
 	}
 
 	pod5 := CreateTestPod(testNamespace, "pod-5", "", nil, AppLabels)
@@ -92,7 +98,7 @@ func createTestPods(t *testing.T) *fake.Clientset {
 
 func TestProcessNamespacePods(t *testing.T) {
 	clientset := createTestPods(t)
-	evictedPods, err := processNamespacePods(clientset, testNamespace, &filters.Options{}, common.Opts{})
+	evictedPods, err := processNamespacePods(clientset, testNamespace, &filters.Options{}, common.Opts{PodFinishedThreshold: time.Hour})
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
 	}
@@ -114,6 +120,112 @@ func TestProcessNamespacePods(t *testing.T) {
 	}
 }
 
+func TestProcessNamespacePodStates(t *testing.T) {
+	clientset := fake.NewClientset()
+	isController := true
+	replicaSetOwner := []v1.OwnerReference{{Kind: "ReplicaSet", Name: "test-replicaset", Controller: &isController}}
+	jobOwner := []v1.OwnerReference{{Kind: "Job", Name: "test-job", Controller: &isController}}
+	twoDaysAgo := v1.NewTime(time.Now().Add(-48 * time.Hour))
+
+	oomKilled := CreateTestPod(testNamespace, "oom-killed", "", nil, AppLabels)
+	oomKilled.OwnerReferences = replicaSetOwner
+	oomKilled.Status = corev1.PodStatus{
+		Phase: corev1.PodFailed,
+		ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "main", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled", FinishedAt: twoDaysAgo}}},
+		},
+	}
+
+	jobPod := CreateTestPod(testNamespace, "job-pod", "", nil, AppLabels)
+	jobPod.OwnerReferences = jobOwner
+	jobPod.Status = corev1.PodStatus{Phase: corev1.PodSucceeded, StartTime: &twoDaysAgo}
+
+	imagePull := CreateTestPod(testNamespace, "image-pull", "", nil, AppLabels)
+	imagePull.OwnerReferences = replicaSetOwner
+	imagePull.Status = corev1.PodStatus{
+		Phase:      corev1.PodPending,
+		Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse, LastTransitionTime: twoDaysAgo}},
+		ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+		},
+	}
+
+	crashLoop := CreateTestPod(testNamespace, "crash-loop", "", nil, AppLabels)
+	crashLoop.OwnerReferences = replicaSetOwner
+	crashLoop.CreationTimestamp = twoDaysAgo
+	crashLoop.Status = corev1.PodStatus{
+		Phase: corev1.PodRunning,
+		ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+		},
+	}
+
+	recentCrashLoop := CreateTestPod(testNamespace, "recent-crash-loop", "", nil, AppLabels)
+	recentCrashLoop.OwnerReferences = replicaSetOwner
+	recentCrashLoop.CreationTimestamp = v1.Now()
+	recentCrashLoop.Status = crashLoop.Status
+
+	pending := CreateTestPod(testNamespace, "stuck-pending", "", nil, AppLabels)
+	pending.OwnerReferences = replicaSetOwner
+	pending.CreationTimestamp = twoDaysAgo
+	pending.Status = corev1.PodStatus{Phase: corev1.PodPending}
+
+	recentPending := CreateTestPod(testNamespace, "recent-pending", "", nil, AppLabels)
+	recentPending.OwnerReferences = replicaSetOwner
+	recentPending.CreationTimestamp = v1.Now()
+	recentPending.Status = corev1.PodStatus{Phase: corev1.PodPending}
+
+	bare := CreateTestPod(testNamespace, "bare", "", nil, AppLabels)
+	bare.Status = corev1.PodStatus{Phase: corev1.PodRunning}
+
+	barePending := CreateTestPod(testNamespace, "bare-pending", "", nil, AppLabels)
+	barePending.CreationTimestamp = twoDaysAgo
+	barePending.Status = corev1.PodStatus{Phase: corev1.PodPending}
+
+	bareCrashLoop := CreateTestPod(testNamespace, "bare-crash-loop", "", nil, AppLabels)
+	bareCrashLoop.CreationTimestamp = twoDaysAgo
+	bareCrashLoop.Status = crashLoop.Status
+
+	for _, pod := range []*corev1.Pod{oomKilled, jobPod, imagePull, crashLoop, recentCrashLoop, pending, recentPending, bare, barePending, bareCrashLoop} {
+		if _, err := clientset.CoreV1().Pods(testNamespace).Create(context.TODO(), pod, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake pod: %v", err)
+		}
+	}
+
+	opts := common.Opts{PodFinishedThreshold: 24 * time.Hour, PodPendingThreshold: time.Hour, PodWaitingThreshold: time.Hour}
+	unusedPods, err := processNamespacePods(clientset, testNamespace, &filters.Options{}, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "bare", Reason: "Pod has no controller owner", ReportOnly: true},
+		{Name: "bare-crash-loop", Reason: "Container app is in CrashLoopBackOff for 2d", ReportOnly: true},
+		{Name: "bare-pending", Reason: "Pending for 2d", ReportOnly: true},
+		{Name: "crash-loop", Reason: "Container app is in CrashLoopBackOff for 2d"},
+		{Name: "image-pull", Reason: "Container app is in ImagePullBackOff for 2d"},
+		{Name: "oom-killed", Reason: "Pod failed (OOMKilled) 2d ago"},
+		{Name: "stuck-pending", Reason: "Pending for 2d"},
+	}
+	if !reflect.DeepEqual(unusedPods, expected) {
+		t.Errorf("Expected %v, got %v", expected, unusedPods)
+	}
+
+	// A zero threshold reports pods as soon as they are pending or waiting
+	opts.PodPendingThreshold, opts.PodWaitingThreshold = 0, 0
+	unusedPods, err = processNamespacePods(clientset, testNamespace, &filters.Options{}, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	reported := make(map[string]bool)
+	for _, pod := range unusedPods {
+		reported[pod.Name] = true
+	}
+	if !reported["recent-pending"] || !reported["recent-crash-loop"] {
+		t.Errorf("Expected recent pending and crash-looping pods with a zero threshold, got %v", unusedPods)
+	}
+}
+
 func TestGetUnusedPodsStructured(t *testing.T) {
 	clientset := createTestPods(t)
 
@@ -124,6 +236,8 @@ func TestGetUnusedPodsStructured(t *testing.T) {
 		DeleteFlag:    false,
 		NoInteractive: true,
 		GroupBy:       "namespace",
+
+		PodFinishedThreshold: time.Hour,
 	}
 
 	output, err := GetUnusedPods(&filters.Options{}, clientset, "json", opts)
@@ -0,0 +1,95 @@
+package kor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func processNamespacePodTemplates(clientset kubernetes.Interface, dynamicClient dynamic.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+	podTemplateList, err := clientset.CoreV1().PodTemplates(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing in core Kubernetes consumes PodTemplates by name, only controllers described by reference rules do
+	referencedPodTemplates, err := retrieveRuleReferencedNames(dynamicClient, namespace, schema.GroupResource{Resource: "podtemplates"}, opts.ReferenceRules)
+	if err != nil {
+		return nil, err
+	}
+
+	var unusedPodTemplates []ResourceInfo
+
+	for _, podTemplate := range podTemplateList.Items {
+		if pass, _ := filter.SetObject(&podTemplate).Run(filterOpts); pass {
+			continue
+		}
+
+		if podTemplate.Labels["kor/used"] == "false" {
+			unusedPodTemplates = append(unusedPodTemplates, ResourceInfo{Name: podTemplate.Name, Reason: unusedLabelReason})
+			continue
+		}
+
+		if len(podTemplate.OwnerReferences) > 0 || slices.Contains(referencedPodTemplates, podTemplate.Name) {
+			continue
+		}
+
+		unusedPodTemplates = append(unusedPodTemplates, ResourceInfo{Name: podTemplate.Name, Reason: "PodTemplate has no owner and is not referenced"})
+	}
+
+	if opts.DeleteFlag {
+		if unusedPodTemplates, err = DeleteResource(unusedPodTemplates, clientset, namespace, "PodTemplate", opts.NoInteractive); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to delete PodTemplate %s in namespace %s: %v\n", unusedPodTemplates, namespace, err)
+		}
+	}
+
+	return unusedPodTemplates, nil
+}
+
+func GetUnusedPodTemplates(filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
+	resources := make(map[string]map[string][]ResourceInfo)
+	for _, namespace := range filterOpts.Namespaces(clientset) {
+		diff, err := processNamespacePodTemplates(clientset, dynamicClient, namespace, filterOpts, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to process namespace %s: %v\n", namespace, err)
+			continue
+		}
+		switch opts.GroupBy {
+		case "namespace":
+			resources[namespace] = make(map[string][]ResourceInfo)
+			resources[namespace]["PodTemplate"] = diff
+		case "resource":
+			appendResources(resources, "PodTemplate", namespace, diff)
+		}
+	}
+
+	var outputBuffer bytes.Buffer
+	var jsonResponse []byte
+	switch outputFormat {
+	case "table":
+		outputBuffer = FormatOutput(resources, opts)
+	case "json", "yaml":
+		var err error
+		if jsonResponse, err = json.MarshalIndent(resources, "", "  "); err != nil {
+			return "", err
+		}
+	}
+
+	unusedPodTemplates, err := unusedResourceFormatter(outputFormat, outputBuffer, opts, jsonResponse)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+	}
+
+	return unusedPodTemplates, nil
+}
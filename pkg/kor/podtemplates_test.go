@@ -0,0 +1,57 @@
+package kor
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func TestProcessNamespacePodTemplates(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	owned := &corev1.PodTemplate{ObjectMeta: v1.ObjectMeta{Name: "owned-template", Namespace: testNamespace}}
+	owned.OwnerReferences = []v1.OwnerReference{{Kind: "Widget", Name: "widget", APIVersion: "example.com/v1"}}
+
+	podTemplates := []*corev1.PodTemplate{
+		owned,
+		{ObjectMeta: v1.ObjectMeta{Name: "referenced-template", Namespace: testNamespace}},
+		{ObjectMeta: v1.ObjectMeta{Name: "standalone-template", Namespace: testNamespace}},
+	}
+	for _, podTemplate := range podTemplates {
+		if _, err := clientset.CoreV1().PodTemplates(testNamespace).Create(context.TODO(), podTemplate, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake podTemplate: %v", err)
+		}
+	}
+
+	widget := createTestWidget("widget", map[string]interface{}{"templateRef": "referenced-template"})
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		testWidgetRule.GVR(): "WidgetList",
+	}, widget)
+	rules := &common.ReferenceRules{
+		References: []common.ReferenceRule{
+			{
+				Source: testWidgetRule,
+				Target: common.RuleResource{Version: "v1", Resource: "podtemplates", Kind: "PodTemplate"},
+				Path:   ".spec.templateRef",
+			},
+		},
+	}
+
+	unusedPodTemplates, err := processNamespacePodTemplates(clientset, dynamicClient, testNamespace, &filters.Options{}, common.Opts{ReferenceRules: rules})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(unusedPodTemplates) != 1 || unusedPodTemplates[0].Name != "standalone-template" {
+		t.Errorf("Expected only standalone-template to be unused, got %v", unusedPodTemplates)
+	}
+}
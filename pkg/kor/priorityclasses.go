@@ -19,18 +19,18 @@ import (
 //go:embed exceptions/priorityclasses/priorityclasses.json
 var priorityClassesConfig []byte
 
+// retrieveUsedPriorityClasses returns the PriorityClasses of Pods and of workload pod templates,
+// so a PriorityClass of a scaled-down workload is still counted
 func retrieveUsedPriorityClasses(clientset kubernetes.Interface) ([]string, error) {
-	pods, err := clientset.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{})
+	podSpecs, err := retrieveWorkloadPodSpecs(clientset, metav1.NamespaceAll)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list Pods: %v", err)
+		return nil, err
 	}
 
 	var usedPriorityClasses []string
-
-	// Iterate through each Pod and check for PriorityClass usage
-	for _, pod := range pods.Items {
-		if pod.Spec.PriorityClassName != "" {
-			usedPriorityClasses = append(usedPriorityClasses, pod.Spec.PriorityClassName)
+	for _, podSpec := range podSpecs {
+		if podSpec.Spec.PriorityClassName != "" {
+			usedPriorityClasses = append(usedPriorityClasses, podSpec.Spec.PriorityClassName)
 		}
 	}
 
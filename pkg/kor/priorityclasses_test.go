@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"testing"
 
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 
@@ -52,6 +53,35 @@ func TestRetrieveUsedPriorityClasses(t *testing.T) {
 	}
 }
 
+func TestRetrieveUsedPriorityClassesFromTemplates(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	deployment := CreateTestDeployment(testNamespace, "scaled-down", 0, AppLabels)
+	deployment.Spec.Template.Spec.PriorityClassName = "deployment-pc"
+	_, err := clientset.AppsV1().Deployments(testNamespace).Create(context.TODO(), deployment, v1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Error creating fake Deployment: %v", err)
+	}
+
+	cronJob := &batchv1.CronJob{ObjectMeta: v1.ObjectMeta{Namespace: testNamespace, Name: "nightly"}}
+	cronJob.Spec.JobTemplate.Spec.Template.Spec.PriorityClassName = "cronjob-pc"
+	_, err = clientset.BatchV1().CronJobs(testNamespace).Create(context.TODO(), cronJob, v1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Error creating fake CronJob: %v", err)
+	}
+
+	usedPriorityClasses, err := retrieveUsedPriorityClasses(clientset)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	for _, name := range []string{"deployment-pc", "cronjob-pc"} {
+		if !contains(usedPriorityClasses, name) {
+			t.Errorf("Expected '%s', got %v", name, usedPriorityClasses)
+		}
+	}
+}
+
 func TestProcessPriorityClasses(t *testing.T) {
 	clientset := createTestPriorityClass(t)
 	unusedPriorityClasses, err := processPriorityClasses(clientset, &filters.Options{})
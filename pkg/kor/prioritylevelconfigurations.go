@@ -0,0 +1,111 @@
+package kor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func retrieveUsedPriorityLevelConfigurations(clientset kubernetes.Interface) ([]string, error) {
+	flowSchemas, err := clientset.FlowcontrolV1().FlowSchemas().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list FlowSchemas: %v", err)
+	}
+
+	var usedPriorityLevels []string
+	for _, fs := range flowSchemas.Items {
+		usedPriorityLevels = append(usedPriorityLevels, fs.Spec.PriorityLevelConfiguration.Name)
+	}
+
+	return usedPriorityLevels, nil
+}
+
+func processPriorityLevelConfigurations(clientset kubernetes.Interface, filterOpts *filters.Options) ([]ResourceInfo, error) {
+	plcs, err := clientset.FlowcontrolV1().PriorityLevelConfigurations().List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
+	if err != nil {
+		return nil, err
+	}
+
+	var unusedPriorityLevels []ResourceInfo
+	priorityLevelNames := make([]string, 0, len(plcs.Items))
+
+	for _, plc := range plcs.Items {
+		// Skip resources with ownerReferences if the general flag is set
+		if filterOpts.IgnoreOwnerReferences && len(plc.OwnerReferences) > 0 {
+			continue
+		}
+
+		if pass, _ := filter.SetObject(&plc).Run(filterOpts); pass {
+			continue
+		}
+
+		if plc.Labels["kor/used"] == "false" {
+			unusedPriorityLevels = append(unusedPriorityLevels, ResourceInfo{Name: plc.Name, Reason: unusedLabelReason})
+			continue
+		}
+
+		if isAPFAutoUpdated(&plc) {
+			continue
+		}
+
+		priorityLevelNames = append(priorityLevelNames, plc.Name)
+	}
+
+	usedPriorityLevels, err := retrieveUsedPriorityLevelConfigurations(clientset)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := CalculateResourceDifference(usedPriorityLevels, priorityLevelNames)
+	for _, name := range diff {
+		unusedPriorityLevels = append(unusedPriorityLevels, ResourceInfo{Name: name, Reason: "PriorityLevelConfiguration is not referenced by any FlowSchema"})
+	}
+	return unusedPriorityLevels, nil
+}
+
+func GetUnusedPriorityLevelConfigurations(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
+	resources := make(map[string]map[string][]ResourceInfo)
+	diff, err := processPriorityLevelConfigurations(clientset, filterOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to process priorityLevelConfigurations: %v\n", err)
+	}
+	if opts.DeleteFlag {
+		if diff, err = DeleteResource(diff, clientset, "", "PriorityLevelConfiguration", opts.NoInteractive); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to delete PriorityLevelConfiguration %s: %v\n", diff, err)
+		}
+	}
+	switch opts.GroupBy {
+	case "namespace":
+		resources[""] = make(map[string][]ResourceInfo)
+		resources[""]["PriorityLevelConfiguration"] = diff
+	case "resource":
+		appendResources(resources, "PriorityLevelConfiguration", "", diff)
+	}
+
+	var outputBuffer bytes.Buffer
+	var jsonResponse []byte
+	switch outputFormat {
+	case "table":
+		outputBuffer = FormatOutput(resources, opts)
+	case "json", "yaml":
+		var err error
+		if jsonResponse, err = json.MarshalIndent(resources, "", "  "); err != nil {
+			return "", err
+		}
+	}
+
+	unusedPriorityLevels, err := unusedResourceFormatter(outputFormat, outputBuffer, opts, jsonResponse)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+	}
+
+	return unusedPriorityLevels, nil
+}
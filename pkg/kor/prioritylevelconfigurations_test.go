@@ -0,0 +1,21 @@
+package kor
+
+import (
+	"testing"
+
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func TestProcessPriorityLevelConfigurations(t *testing.T) {
+	clientset := createTestFlowControlResources(t)
+
+	unusedPriorityLevels, err := processPriorityLevelConfigurations(clientset, &filters.Options{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := ResourceInfo{Name: "unreferenced", Reason: "PriorityLevelConfiguration is not referenced by any FlowSchema"}
+	if len(unusedPriorityLevels) != 1 || unusedPriorityLevels[0] != expected {
+		t.Errorf("Expected %v, got %v", expected, unusedPriorityLevels)
+	}
+}
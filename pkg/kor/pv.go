@@ -6,19 +6,70 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
+	"sigs.k8s.io/yaml"
 
 	"github.com/yonahd/kor/pkg/common"
 	"github.com/yonahd/kor/pkg/filters"
 )
 
-func processPvs(clientset kubernetes.Interface, filterOpts *filters.Options) ([]ResourceInfo, error) {
+// describePv returns the capacity, StorageClass and CSI volume handle of a PV
+func describePv(pv corev1.PersistentVolume) string {
+	var details []string
+	if capacity, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+		details = append(details, capacity.String())
+	}
+	if pv.Spec.StorageClassName != "" {
+		details = append(details, "StorageClass "+pv.Spec.StorageClassName)
+	}
+	if pv.Spec.CSI != nil {
+		details = append(details, "volume handle "+pv.Spec.CSI.VolumeHandle)
+	}
+	if len(details) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(details, ", ") + ")"
+}
+
+func retrieveFormerClaim(pv corev1.PersistentVolume) string {
+	if pv.Spec.ClaimRef == nil {
+		return "an unknown claim"
+	}
+	return pv.Spec.ClaimRef.Namespace + "/" + pv.Spec.ClaimRef.Name
+}
+
+func retrievePvReason(pv corev1.PersistentVolume) string {
+	switch pv.Status.Phase {
+	case corev1.VolumeAvailable:
+		return "PV is Available and not bound to any PVC"
+	case corev1.VolumeReleased:
+		if pv.Spec.PersistentVolumeReclaimPolicy == corev1.PersistentVolumeReclaimRetain {
+			return fmt.Sprintf("PV is Released and retained, formerly bound to %s", retrieveFormerClaim(pv))
+		}
+		return fmt.Sprintf("PV is Released with %s policy, formerly bound to %s", pv.Spec.PersistentVolumeReclaimPolicy, retrieveFormerClaim(pv))
+	case corev1.VolumeFailed:
+		if pv.Status.Message != "" {
+			return fmt.Sprintf("PV reclamation failed: %s", pv.Status.Message)
+		}
+		return "PV reclamation failed"
+	default:
+		return "Persistent Volume is not in use"
+	}
+}
+
+// processPvs returns the unused PVs together with the summed capacity of those that are not Bound
+func processPvs(clientset kubernetes.Interface, filterOpts *filters.Options) ([]ResourceInfo, resource.Quantity, error) {
+	var reclaimable resource.Quantity
+
 	pvs, err := clientset.CoreV1().PersistentVolumes().List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
 	if err != nil {
-		return nil, err
+		return nil, reclaimable, err
 	}
 
 	var unusedPvs []ResourceInfo
@@ -33,26 +84,77 @@ func processPvs(clientset kubernetes.Interface, filterOpts *filters.Options) ([]
 			continue
 		}
 
-		if pv.Labels["kor/used"] == "false" {
-			reason := "Marked with unused label"
-			unusedPvs = append(unusedPvs, ResourceInfo{Name: pv.Name, Reason: reason})
+		var reason string
+		switch {
+		case pv.Labels["kor/used"] == "false":
+			reason = "Marked with unused label"
+		case pv.Status.Phase != corev1.VolumeBound:
+			reason = retrievePvReason(pv) + describePv(pv)
+		default:
 			continue
 		}
 
-		if pv.Status.Phase != "Bound" {
-			reason := "Persistent Volume is not in use"
-			unusedPvs = append(unusedPvs, ResourceInfo{Name: pv.Name, Reason: reason})
+		unusedPvs = append(unusedPvs, ResourceInfo{Name: pv.Name, Reason: reason})
+		// Bound PVs marked with the unused label still hold data of their claim
+		if capacity, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok && pv.Status.Phase != corev1.VolumeBound {
+			reclaimable.Add(capacity)
 		}
+	}
 
+	return unusedPvs, reclaimable, nil
+
+}
+
+// reclaimableStorageKey holds the reclaimable storage in structured output, next to the namespaces.
+// Namespace names are lowercase DNS labels, so none of them can take the key.
+const reclaimableStorageKey = "reclaimableStorage"
+
+// writeReclaimableStorage prints the reclaimable storage below the table
+func writeReclaimableStorage(outputBuffer *bytes.Buffer, outputFormat string, reclaimable resource.Quantity) {
+	if reclaimable.IsZero() || outputFormat != "table" {
+		return
+	}
+	fmt.Fprintf(outputBuffer, "Reclaimable storage: %s\n", reclaimable.String())
+}
+
+// addReclaimableStorage adds the reclaimable storage to the formatted json or yaml output
+func addReclaimableStorage(output, outputFormat string, reclaimable resource.Quantity) (string, error) {
+	if reclaimable.IsZero() || output == "" || (outputFormat != "json" && outputFormat != "yaml") {
+		return output, nil
 	}
 
-	return unusedPvs, nil
+	jsonOutput := []byte(output)
+	if outputFormat == "yaml" {
+		var err error
+		if jsonOutput, err = yaml.YAMLToJSON(jsonOutput); err != nil {
+			return "", err
+		}
+	}
 
+	var structured map[string]interface{}
+	if err := json.Unmarshal(jsonOutput, &structured); err != nil {
+		return "", err
+	}
+	if structured == nil {
+		structured = make(map[string]interface{})
+	}
+	structured[reclaimableStorageKey] = reclaimable.String()
+
+	jsonOutput, err := json.MarshalIndent(structured, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if outputFormat == "yaml" {
+		if jsonOutput, err = yaml.JSONToYAML(jsonOutput); err != nil {
+			return "", err
+		}
+	}
+	return string(jsonOutput), nil
 }
 
 func GetUnusedPvs(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
 	resources := make(map[string]map[string][]ResourceInfo)
-	diff, err := processPvs(clientset, filterOpts)
+	diff, reclaimable, err := processPvs(clientset, filterOpts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to process pvs: %v\n", err)
 	}
@@ -80,11 +182,12 @@ func GetUnusedPvs(filterOpts *filters.Options, clientset kubernetes.Interface, o
 			return "", err
 		}
 	}
+	writeReclaimableStorage(&outputBuffer, outputFormat, reclaimable)
 
 	unusedPvs, err := unusedResourceFormatter(outputFormat, outputBuffer, opts, jsonResponse)
 	if err != nil {
 		fmt.Printf("err: %v\n", err)
 	}
 
-	return unusedPvs, nil
+	return addReclaimableStorage(unusedPvs, outputFormat, reclaimable)
 }
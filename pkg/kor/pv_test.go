@@ -6,6 +6,8 @@ import (
 	"reflect"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 
@@ -45,7 +47,7 @@ func createTestPvs(t *testing.T) *fake.Clientset {
 
 func TestProcessPvs(t *testing.T) {
 	clientset := createTestPvs(t)
-	usedPvs, err := processPvs(clientset, &filters.Options{})
+	usedPvs, _, err := processPvs(clientset, &filters.Options{})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -94,3 +96,49 @@ func TestGetUnusedPvs(t *testing.T) {
 		t.Errorf("Expected output does not match actual output")
 	}
 }
+
+func TestProcessPvsPhases(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	newPv := func(name string, phase corev1.PersistentVolumePhase, capacity string) *corev1.PersistentVolume {
+		pv := CreateTestPv(name, string(phase), AppLabels, "fast")
+		pv.Spec.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)}
+		return pv
+	}
+
+	available := newPv("available", corev1.VolumeAvailable, "10Gi")
+	available.Spec.CSI = &corev1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: "vol-0123"}
+	retained := newPv("retained", corev1.VolumeReleased, "20Gi")
+	retained.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+	retained.Spec.ClaimRef = &corev1.ObjectReference{Namespace: testNamespace, Name: "data"}
+	failed := newPv("failed", corev1.VolumeFailed, "2Gi")
+	failed.Status.Message = "recycler failed"
+	// Bound PVs marked as unused are reported but their storage isn't reclaimable yet
+	marked := newPv("marked", corev1.VolumeBound, "50Gi")
+	marked.Labels = map[string]string{"kor/used": "false"}
+
+	for _, pv := range []*corev1.PersistentVolume{available, retained, failed, marked, newPv("bound", corev1.VolumeBound, "100Gi")} {
+		if _, err := clientset.CoreV1().PersistentVolumes().Create(context.TODO(), pv, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake PV: %v", err)
+		}
+	}
+
+	unusedPvs, reclaimable, err := processPvs(clientset, &filters.Options{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "available", Reason: "PV is Available and not bound to any PVC (10Gi, StorageClass fast, volume handle vol-0123)"},
+		{Name: "failed", Reason: "PV reclamation failed: recycler failed (2Gi, StorageClass fast)"},
+		{Name: "marked", Reason: "Marked with unused label"},
+		{Name: "retained", Reason: "PV is Released and retained, formerly bound to " + testNamespace + "/data (20Gi, StorageClass fast)"},
+	}
+	if !reflect.DeepEqual(expected, unusedPvs) {
+		t.Errorf("Expected %v, got %v", expected, unusedPvs)
+	}
+
+	if reclaimable.String() != "32Gi" {
+		t.Errorf("Expected 32Gi of reclaimable storage, got %s", reclaimable.String())
+	}
+}
@@ -6,7 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
@@ -38,14 +44,66 @@ func retrieveUsedPvcs(clientset kubernetes.Interface, namespace string) ([]strin
 	return usedPvcs, err
 }
 
+// retrievePvcCapacity returns the capacity of a bound PVC, or the requested storage of an unbound one
+func retrievePvcCapacity(pvc corev1.PersistentVolumeClaim) string {
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		return capacity.String()
+	}
+	if request, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		return request.String()
+	}
+	return ""
+}
+
+func withPvcCapacity(reason string, pvc corev1.PersistentVolumeClaim) string {
+	if capacity := retrievePvcCapacity(pvc); capacity != "" {
+		return fmt.Sprintf("%s (%s)", reason, capacity)
+	}
+	return reason
+}
+
+// retrieveStatefulSetClaimOrdinal matches a PVC against the volumeClaimTemplates of a StatefulSet,
+// whose claims are named <template>-<statefulset>-<ordinal>
+func retrieveStatefulSetClaimOrdinal(pvcName string, sts appsv1.StatefulSet) (int, bool) {
+	for _, template := range sts.Spec.VolumeClaimTemplates {
+		suffix, found := strings.CutPrefix(pvcName, template.Name+"-"+sts.Name+"-")
+		if !found {
+			continue
+		}
+		if ordinal, err := strconv.Atoi(suffix); err == nil && ordinal >= 0 {
+			return ordinal, true
+		}
+	}
+	return 0, false
+}
+
+// statefulSetClaimSuffix matches the ordinal suffix of claims created from a volumeClaimTemplate
+var statefulSetClaimSuffix = regexp.MustCompile(`-[0-9]+$`)
+
 func processNamespacePvcs(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
 	pvcs, err := clientset.CoreV1().PersistentVolumeClaims(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
 	if err != nil {
 		return nil, err
 	}
 
+	statefulSets, err := clientset.AppsV1().StatefulSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	liveStatefulSets := make(map[string]bool, len(statefulSets.Items))
+	for _, sts := range statefulSets.Items {
+		liveStatefulSets[sts.Name] = true
+	}
+
+	usedPvcs, err := retrieveUsedPvcs(clientset, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var diff []ResourceInfo
 	var unusedPvcNames []string
-	pvcNames := make([]string, 0, len(pvcs.Items))
+	var retainedPvcs []ResourceInfo
+
 	for _, pvc := range pvcs.Items {
 		// Skip resources with ownerReferences if the general flag is set
 		if filterOpts.IgnoreOwnerReferences && len(pvc.OwnerReferences) > 0 {
@@ -61,18 +119,67 @@ func processNamespacePvcs(clientset kubernetes.Interface, namespace string, filt
 			continue
 		}
 
-		pvcNames = append(pvcNames, pvc.Name)
-	}
+		used := slices.Contains(usedPvcs, pvc.Name)
 
-	usedPvcs, err := retrieveUsedPvcs(clientset, namespace)
-	if err != nil {
-		return nil, err
-	}
+		if pvc.Status.Phase == corev1.ClaimLost {
+			reason := withPvcCapacity(fmt.Sprintf("PVC is Lost, PersistentVolume %s no longer exists", pvc.Spec.VolumeName), pvc)
+			if used {
+				retainedPvcs = append(retainedPvcs, ResourceInfo{Name: pvc.Name, Reason: reason, ReportOnly: true})
+			} else {
+				diff = append(diff, ResourceInfo{Name: pvc.Name, Reason: reason})
+			}
+			continue
+		}
+
+		if used {
+			continue
+		}
+
+		// Claims of a StatefulSet scaled down are kept on purpose so scaling up reuses them,
+		// and those of its replicas may be Pending while their volumes are provisioned
+		statefulSetClaim := false
+		for _, sts := range statefulSets.Items {
+			ordinal, ok := retrieveStatefulSetClaimOrdinal(pvc.Name, sts)
+			if !ok {
+				continue
+			}
+			statefulSetClaim = true
+			var start, replicas int
+			if sts.Spec.Ordinals != nil {
+				start = int(sts.Spec.Ordinals.Start)
+			}
+			if sts.Spec.Replicas != nil {
+				replicas = int(*sts.Spec.Replicas)
+			}
+			if ordinal < start || ordinal >= start+replicas {
+				reason := withPvcCapacity(fmt.Sprintf("PVC of StatefulSet %s ordinal %d is beyond its %d replicas", sts.Name, ordinal, replicas), pvc)
+				retainedPvcs = append(retainedPvcs, ResourceInfo{Name: pvc.Name, Reason: reason, ReportOnly: true})
+			}
+			break
+		}
+		if statefulSetClaim {
+			continue
+		}
+
+		if pvc.Status.Phase == corev1.ClaimPending {
+			diff = append(diff, ResourceInfo{Name: pvc.Name, Reason: withPvcCapacity("PVC is Pending and not used in Pods", pvc)})
+			continue
+		}
+
+		if owner := slices.IndexFunc(pvc.OwnerReferences, func(ref metav1.OwnerReference) bool {
+			return ref.Kind == "StatefulSet" && !liveStatefulSets[ref.Name]
+		}); owner >= 0 {
+			reason := withPvcCapacity(fmt.Sprintf("PVC of deleted StatefulSet %s", pvc.OwnerReferences[owner].Name), pvc)
+			diff = append(diff, ResourceInfo{Name: pvc.Name, Reason: reason})
+			continue
+		}
 
-	var diff []ResourceInfo
-	for _, name := range CalculateResourceDifference(usedPvcs, pvcNames) {
 		reason := "PVC is not in use"
-		diff = append(diff, ResourceInfo{Name: name, Reason: reason})
+		// StatefulSets retain their claims without ownerReferences by default
+		if statefulSetClaimSuffix.MatchString(pvc.Name) {
+			reason = "PVC is not in use, possibly left behind by a deleted StatefulSet"
+		}
+		diff = append(diff, ResourceInfo{Name: pvc.Name, Reason: withPvcCapacity(reason, pvc)})
 	}
 
 	for _, name := range unusedPvcNames {
@@ -85,7 +192,8 @@ func processNamespacePvcs(clientset kubernetes.Interface, namespace string, filt
 			fmt.Fprintf(os.Stderr, "Failed to delete PVC %s in namespace %s: %v\n", diff, namespace, err)
 		}
 	}
-	return diff, nil
+
+	return append(diff, retainedPvcs...), nil
 }
 
 func GetUnusedPvcs(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
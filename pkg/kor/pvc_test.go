@@ -8,6 +8,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
@@ -206,6 +207,64 @@ func TestFilterOwnerReferencedPvcs(t *testing.T) {
 	}
 }
 
+func TestProcessNamespacePvcsStates(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	sts := CreateTestStatefulSet(testNamespace, "db", 2, AppLabels)
+	sts.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{{ObjectMeta: v1.ObjectMeta{Name: "data"}}}
+	if _, err := clientset.AppsV1().StatefulSets(testNamespace).Create(context.TODO(), sts, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake StatefulSet: %v", err)
+	}
+
+	newPvc := func(name string, phase corev1.PersistentVolumeClaimPhase, capacity string) *corev1.PersistentVolumeClaim {
+		pvc := CreateTestPvc(testNamespace, name, AppLabels, "test-sc1")
+		pvc.Status.Phase = phase
+		pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(capacity)}
+		return pvc
+	}
+
+	lost := newPvc("lost", corev1.ClaimLost, "5Gi")
+	lost.Spec.VolumeName = "pv-lost"
+	pending := newPvc("pending", corev1.ClaimPending, "1Gi")
+	pending.Status.Capacity = nil
+	pending.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("2Gi")}
+	ownedByDeleted := newPvc("data-cache-0", corev1.ClaimBound, "1Gi")
+	ownedByDeleted.OwnerReferences = []v1.OwnerReference{{Kind: "StatefulSet", Name: "cache"}}
+
+	pvcs := []*corev1.PersistentVolumeClaim{
+		newPvc("data-db-0", corev1.ClaimPending, "10Gi"),
+		newPvc("data-db-1", corev1.ClaimBound, "10Gi"),
+		newPvc("data-db-2", corev1.ClaimBound, "10Gi"),
+		newPvc("data-db-3", corev1.ClaimPending, "10Gi"),
+		lost,
+		pending,
+		ownedByDeleted,
+		newPvc("data-queue-0", corev1.ClaimBound, "3Gi"),
+	}
+	for _, pvc := range pvcs {
+		if _, err := clientset.CoreV1().PersistentVolumeClaims(testNamespace).Create(context.TODO(), pvc, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake PVC: %v", err)
+		}
+	}
+
+	unusedPvcs, err := processNamespacePvcs(clientset, testNamespace, &filters.Options{}, common.Opts{})
+	if err != nil {
+		t.Fatalf("Error retrieving unused PVCs: %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "data-cache-0", Reason: "PVC of deleted StatefulSet cache (1Gi)"},
+		{Name: "data-queue-0", Reason: "PVC is not in use, possibly left behind by a deleted StatefulSet (3Gi)"},
+		{Name: "lost", Reason: "PVC is Lost, PersistentVolume pv-lost no longer exists (5Gi)"},
+		{Name: "pending", Reason: "PVC is Pending and not used in Pods (2Gi)"},
+		{Name: "data-db-2", Reason: "PVC of StatefulSet db ordinal 2 is beyond its 2 replicas (10Gi)", ReportOnly: true},
+		{Name: "data-db-3", Reason: "PVC of StatefulSet db ordinal 3 is beyond its 2 replicas (10Gi)", ReportOnly: true},
+	}
+	if !reflect.DeepEqual(expected, unusedPvcs) {
+		t.Errorf("Expected %v, got %v", expected, unusedPvcs)
+	}
+}
+
 func init() {
 	scheme.Scheme = runtime.NewScheme()
 	_ = appsv1.AddToScheme(scheme.Scheme)
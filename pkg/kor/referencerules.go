@@ -0,0 +1,431 @@
+package kor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func validateRuleResource(r common.RuleResource) error {
+	if r.Version == "" || r.Resource == "" {
+		return errors.New("version and resource are required")
+	}
+	return nil
+}
+
+// LoadReferenceRules reads and validates the rules at path, leaving out with a warning
+// the rules whose source or target resource is not served by the cluster, e.g. because its CRD is not installed
+func LoadReferenceRules(path string, discoveryClient discovery.DiscoveryInterface) (*common.ReferenceRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules common.ReferenceRules
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse reference rules %s: %v", path, err)
+	}
+
+	for i, rule := range rules.References {
+		if err := validateRuleResource(rule.Source); err != nil {
+			return nil, fmt.Errorf("reference rule %d source: %v", i, err)
+		}
+		if err := validateRuleResource(rule.Target); err != nil {
+			return nil, fmt.Errorf("reference rule %d target: %v", i, err)
+		}
+		parsedPath, err := parseRuleJSONPath(rule.Path)
+		if err != nil {
+			return nil, fmt.Errorf("reference rule %d path: %v", i, err)
+		}
+		rules.References[i].ParsedPath = parsedPath
+	}
+	for i, rule := range rules.Selectors {
+		if err := validateRuleResource(rule.Source); err != nil {
+			return nil, fmt.Errorf("selector rule %d source: %v", i, err)
+		}
+		parsedPath, err := parseRuleJSONPath(rule.Path)
+		if err != nil {
+			return nil, fmt.Errorf("selector rule %d path: %v", i, err)
+		}
+		rules.Selectors[i].ParsedPath = parsedPath
+	}
+
+	served := make(map[schema.GroupVersionResource]bool)
+	isServed := func(resource common.RuleResource) (bool, error) {
+		gvr := resource.GVR()
+		if ok, checked := served[gvr]; checked {
+			return ok, nil
+		}
+		resources, err := discoveryClient.ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+		if err != nil && !apierrors.IsNotFound(err) {
+			return false, err
+		}
+		served[gvr] = resources != nil && slices.ContainsFunc(resources.APIResources, func(resource metav1.APIResource) bool {
+			return resource.Name == gvr.Resource
+		})
+		if !served[gvr] {
+			fmt.Fprintf(os.Stderr, "Skipping reference rules for %s, the resource is not installed\n", gvr.GroupResource())
+		}
+		return served[gvr], nil
+	}
+
+	var references []common.ReferenceRule
+	for _, rule := range rules.References {
+		if ok, err := isServed(rule.Source); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		// Without its target served, every reference of the rule would look missing
+		if ok, err := isServed(rule.Target); err != nil {
+			return nil, err
+		} else if ok {
+			references = append(references, rule)
+		}
+	}
+	var selectors []common.SelectorRule
+	for _, rule := range rules.Selectors {
+		if ok, err := isServed(rule.Source); err != nil {
+			return nil, err
+		} else if ok {
+			selectors = append(selectors, rule)
+		}
+	}
+
+	return &common.ReferenceRules{References: references, Selectors: selectors}, nil
+}
+
+func parseRuleJSONPath(path string) (*jsonpath.JSONPath, error) {
+	if path == "" {
+		return nil, errors.New("path is required")
+	}
+	if !strings.HasPrefix(path, "{") {
+		path = "{" + path + "}"
+	}
+	parser := jsonpath.New("rule").AllowMissingKeys(true)
+	if err := parser.Parse(path); err != nil {
+		return nil, err
+	}
+	return parser, nil
+}
+
+// retrieveRuleJSONPath returns the path parsed when the rules were loaded, parsing it for rules built otherwise
+func retrieveRuleJSONPath(parsedPath *jsonpath.JSONPath, path string) (*jsonpath.JSONPath, error) {
+	if parsedPath != nil {
+		return parsedPath, nil
+	}
+	return parseRuleJSONPath(path)
+}
+
+func evaluateRuleJSONPath(object map[string]interface{}, parser *jsonpath.JSONPath) ([]interface{}, error) {
+	results, err := parser.FindResults(object)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []interface{}
+	for _, result := range results {
+		for _, value := range result {
+			if value.IsValid() && value.CanInterface() && value.Interface() != nil {
+				values = append(values, value.Interface())
+			}
+		}
+	}
+	return values, nil
+}
+
+func retrieveRuleReferencedNamesFromObject(object map[string]interface{}, parser *jsonpath.JSONPath) ([]string, error) {
+	values, err := evaluateRuleJSONPath(object, parser)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			if v != "" {
+				names = append(names, v)
+			}
+		case []interface{}:
+			for _, item := range v {
+				if name, ok := item.(string); ok && name != "" {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	return names, nil
+}
+
+// retrieveRuleSelector converts the value found at a selector path into a label selector.
+// Both a full LabelSelector and a plain label map are accepted.
+func retrieveRuleSelector(object map[string]interface{}, parser *jsonpath.JSONPath, path string) (*metav1.LabelSelector, bool, error) {
+	values, err := evaluateRuleJSONPath(object, parser)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(values) == 0 {
+		return nil, false, nil
+	}
+
+	selectorMap, ok := values[0].(map[string]interface{})
+	if !ok {
+		return nil, false, fmt.Errorf("value at %s is not a selector", path)
+	}
+
+	selector := &metav1.LabelSelector{}
+	_, hasMatchLabels := selectorMap["matchLabels"]
+	_, hasMatchExpressions := selectorMap["matchExpressions"]
+	if hasMatchLabels || hasMatchExpressions {
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(selectorMap, selector)
+	} else {
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(map[string]interface{}{"matchLabels": selectorMap}, selector)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return selector, true, nil
+}
+
+// retrieveRuleReferencedNames returns the names of target objects in the namespace that
+// are referenced by custom resources according to the loaded reference rules
+func retrieveRuleReferencedNames(dynamicClient dynamic.Interface, namespace string, target schema.GroupResource, rules *common.ReferenceRules) ([]string, error) {
+	if rules == nil || dynamicClient == nil {
+		return nil, nil
+	}
+
+	var names []string
+	for _, rule := range rules.References {
+		if rule.Target.Group != target.Group || rule.Target.Resource != target.Resource {
+			continue
+		}
+
+		parser, err := retrieveRuleJSONPath(rule.ParsedPath, rule.Path)
+		if err != nil {
+			return nil, err
+		}
+		sources, err := dynamicClient.Resource(rule.Source.GVR()).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, source := range sources.Items {
+			referenced, err := retrieveRuleReferencedNamesFromObject(source.Object, parser)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, referenced...)
+		}
+	}
+	return names, nil
+}
+
+func retrieveRuleTargetNames(dynamicClient dynamic.Interface, namespace string, target common.RuleResource, cache map[schema.GroupVersionResource]map[string]bool) (map[string]bool, error) {
+	gvr := target.GVR()
+	if names, ok := cache[gvr]; ok {
+		return names, nil
+	}
+
+	targets, err := dynamicClient.Resource(gvr).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(targets.Items))
+	for _, item := range targets.Items {
+		names[item.GetName()] = true
+	}
+	cache[gvr] = names
+	return names, nil
+}
+
+func listRuleSources(dynamicClient dynamic.Interface, namespace string, source common.RuleResource, filterOpts *filters.Options) ([]unstructured.Unstructured, error) {
+	sources, err := dynamicClient.Resource(source.GVR()).Namespace(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []unstructured.Unstructured
+	for _, item := range sources.Items {
+		// Skip resources with ownerReferences if the general flag is set
+		if filterOpts.IgnoreOwnerReferences && len(item.GetOwnerReferences()) > 0 {
+			continue
+		}
+
+		if pass, _ := filter.SetObject(&item).Run(filterOpts); pass {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+	return filtered, nil
+}
+
+func processNamespaceCustomResources(clientset kubernetes.Interface, dynamicClient dynamic.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) (map[common.RuleResource][]ResourceInfo, error) {
+	unused := make(map[common.RuleResource]map[string]ResourceInfo)
+	targetCache := make(map[schema.GroupVersionResource]map[string]bool)
+
+	// Rule findings are only reported, a rule may be incomplete and a missing target or pod may be transient
+	markUnused := func(source common.RuleResource, name, reason string, reportOnly bool) {
+		if unused[source] == nil {
+			unused[source] = make(map[string]ResourceInfo)
+		}
+		if _, found := unused[source][name]; !found {
+			unused[source][name] = ResourceInfo{Name: name, Reason: reason, ReportOnly: reportOnly}
+		}
+	}
+
+	for _, rule := range opts.ReferenceRules.References {
+		sources, err := listRuleSources(dynamicClient, namespace, rule.Source, filterOpts)
+		if err != nil {
+			return nil, err
+		}
+		if len(sources) == 0 {
+			continue
+		}
+
+		targetNames, err := retrieveRuleTargetNames(dynamicClient, namespace, rule.Target, targetCache)
+		if err != nil {
+			return nil, err
+		}
+		parser, err := retrieveRuleJSONPath(rule.ParsedPath, rule.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, source := range sources {
+			if source.GetLabels()["kor/used"] == "false" {
+				markUnused(rule.Source, source.GetName(), unusedLabelReason, false)
+				continue
+			}
+
+			referenced, err := retrieveRuleReferencedNamesFromObject(source.Object, parser)
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range referenced {
+				if !targetNames[name] {
+					markUnused(rule.Source, source.GetName(), fmt.Sprintf("References missing %s %s", rule.Target.DisplayKind(), name), true)
+					break
+				}
+			}
+		}
+	}
+
+	for _, rule := range opts.ReferenceRules.Selectors {
+		sources, err := listRuleSources(dynamicClient, namespace, rule.Source, filterOpts)
+		if err != nil {
+			return nil, err
+		}
+		parser, err := retrieveRuleJSONPath(rule.ParsedPath, rule.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, source := range sources {
+			if source.GetLabels()["kor/used"] == "false" {
+				markUnused(rule.Source, source.GetName(), unusedLabelReason, false)
+				continue
+			}
+
+			selector, found, err := retrieveRuleSelector(source.Object, parser, rule.Path)
+			if err != nil {
+				return nil, err
+			}
+			if !found {
+				continue
+			}
+
+			pods, err := retrievePodsForSelector(clientset, namespace, selector)
+			if err != nil {
+				return nil, err
+			}
+			if len(pods) == 0 {
+				markUnused(rule.Source, source.GetName(), "Selector matches no pods", true)
+			}
+		}
+	}
+
+	diffs := make(map[common.RuleResource][]ResourceInfo)
+	for source, infos := range unused {
+		var diff []ResourceInfo
+		for _, name := range slices.Sorted(maps.Keys(infos)) {
+			diff = append(diff, infos[name])
+		}
+
+		if opts.DeleteFlag {
+			var err error
+			if diff, err = DeleteDynamicResource(diff, dynamicClient, namespace, source.GVR(), source.DisplayKind(), opts.NoInteractive); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to delete %s %s in namespace %s: %v\n", source.DisplayKind(), diff, namespace, err)
+			}
+		}
+		diffs[source] = diff
+	}
+
+	return diffs, nil
+}
+
+func GetUnusedCustomResources(filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
+	if opts.ReferenceRules == nil {
+		return "", errors.New("no reference rules loaded, use --reference-rules to provide them")
+	}
+
+	resources := make(map[string]map[string][]ResourceInfo)
+	for _, namespace := range filterOpts.Namespaces(clientset) {
+		diffs, err := processNamespaceCustomResources(clientset, dynamicClient, namespace, filterOpts, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to process namespace %s: %v\n", namespace, err)
+			continue
+		}
+		switch opts.GroupBy {
+		case "namespace":
+			resources[namespace] = make(map[string][]ResourceInfo)
+			for source, diff := range diffs {
+				resources[namespace][source.DisplayKind()] = diff
+			}
+		case "resource":
+			for source, diff := range diffs {
+				appendResources(resources, source.DisplayKind(), namespace, diff)
+			}
+		}
+	}
+
+	var outputBuffer bytes.Buffer
+	var jsonResponse []byte
+	switch outputFormat {
+	case "table":
+		outputBuffer = FormatOutput(resources, opts)
+	case "json", "yaml":
+		var err error
+		if jsonResponse, err = json.MarshalIndent(resources, "", "  "); err != nil {
+			return "", err
+		}
+	}
+
+	unusedCustomResources, err := unusedResourceFormatter(outputFormat, outputBuffer, opts, jsonResponse)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+	}
+
+	return unusedCustomResources, nil
+}
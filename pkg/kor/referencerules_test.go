@@ -0,0 +1,221 @@
+package kor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+var (
+	testWidgetRule    = common.RuleResource{Group: "example.com", Version: "v1", Resource: "widgets", Kind: "Widget"}
+	testConfigMapRule = common.RuleResource{Version: "v1", Resource: "configmaps", Kind: "ConfigMap"}
+)
+
+func createTestWidget(name string, spec map[string]interface{}) *unstructured.Unstructured {
+	widget := CreateTestUnstructered("Widget", "example.com/v1", testNamespace, name)
+	widget.Object["spec"] = spec
+	return widget
+}
+
+func createTestReferenceRuleResources(t *testing.T) (*fake.Clientset, *dynamicfake.FakeDynamicClient, *common.ReferenceRules) {
+	clientset := fake.NewClientset()
+
+	_, err := clientset.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{
+		ObjectMeta: v1.ObjectMeta{Name: testNamespace},
+	}, v1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Error creating namespace %s: %v", testNamespace, err)
+	}
+
+	configmaps := []*corev1.ConfigMap{
+		CreateTestConfigmap(testNamespace, "widget-config", AppLabels),
+		CreateTestConfigmap(testNamespace, "orphan-config", AppLabels),
+	}
+	for _, cm := range configmaps {
+		if _, err := clientset.CoreV1().ConfigMaps(testNamespace).Create(context.TODO(), cm, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake configmap: %v", err)
+		}
+	}
+
+	_, err = clientset.CoreV1().Pods(testNamespace).Create(context.TODO(), CreateTestPod(testNamespace, "widget-pod", "", nil, map[string]string{"app": "widget"}), v1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Error creating fake pod: %v", err)
+	}
+
+	objects := []runtime.Object{
+		CreateTestUnstructered("ConfigMap", "v1", testNamespace, "widget-config"),
+		CreateTestUnstructered("ConfigMap", "v1", testNamespace, "orphan-config"),
+		createTestWidget("widget-ok", map[string]interface{}{
+			"configRef": map[string]interface{}{"name": "widget-config"},
+			"selector":  map[string]interface{}{"matchLabels": map[string]interface{}{"app": "widget"}},
+		}),
+		createTestWidget("widget-dangling", map[string]interface{}{
+			"configRef": map[string]interface{}{"name": "missing-config"},
+		}),
+		createTestWidget("widget-no-pods", map[string]interface{}{
+			"selector": map[string]interface{}{"app": "gone"},
+		}),
+	}
+
+	gvrToListKind := map[schema.GroupVersionResource]string{
+		testWidgetRule.GVR():    "WidgetList",
+		testConfigMapRule.GVR(): "ConfigMapList",
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), gvrToListKind, objects...)
+
+	rules := &common.ReferenceRules{
+		References: []common.ReferenceRule{
+			{Source: testWidgetRule, Target: testConfigMapRule, Path: ".spec.configRef.name"},
+		},
+		Selectors: []common.SelectorRule{
+			{Source: testWidgetRule, Path: "{.spec.selector}"},
+		},
+	}
+
+	return clientset, dynamicClient, rules
+}
+
+func TestLoadReferenceRules(t *testing.T) {
+	dir := t.TempDir()
+	clientset := fake.NewClientset()
+	clientset.Resources = []*v1.APIResourceList{
+		{GroupVersion: "example.com/v1", APIResources: []v1.APIResource{{Name: "widgets", Namespaced: true, Kind: "Widget"}}},
+		{GroupVersion: "v1", APIResources: []v1.APIResource{{Name: "configmaps", Namespaced: true, Kind: "ConfigMap"}}},
+	}
+
+	valid := filepath.Join(dir, "valid.yaml")
+	err := os.WriteFile(valid, []byte(`references:
+- source: {group: example.com, version: v1, resource: widgets}
+  target: {version: v1, resource: configmaps}
+  path: "{.spec.configRef.name}"
+- source: {group: example.com, version: v1, resource: gadgets}
+  target: {version: v1, resource: configmaps}
+  path: "{.spec.configRef.name}"
+- source: {group: example.com, version: v1, resource: widgets}
+  target: {group: missing.example.com, version: v1, resource: sprockets}
+  path: "{.spec.sprocketRef.name}"
+selectors:
+- source: {group: example.com, version: v1, resource: widgets}
+  path: .spec.selector
+- source: {group: missing.example.com, version: v1, resource: sprockets}
+  path: .spec.selector
+`), 0o600)
+	if err != nil {
+		t.Fatalf("Error writing rules file: %v", err)
+	}
+
+	// Rules whose source or target is not installed are left out
+	rules, err := LoadReferenceRules(valid, clientset.Discovery())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(rules.References) != 1 || len(rules.Selectors) != 1 {
+		t.Errorf("Expected 1 reference and 1 selector rule, got %d and %d", len(rules.References), len(rules.Selectors))
+	}
+	if rules.References[0].Target.GVR() != testConfigMapRule.GVR() {
+		t.Errorf("Expected target %v, got %v", testConfigMapRule.GVR(), rules.References[0].Target.GVR())
+	}
+	if rules.References[0].ParsedPath == nil || rules.Selectors[0].ParsedPath == nil {
+		t.Error("Expected the rule paths to be parsed when loading")
+	}
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	err = os.WriteFile(invalid, []byte(`references:
+- source: {group: example.com, resource: widgets}
+  target: {version: v1, resource: configmaps}
+  path: "{.spec.configRef.name}"
+`), 0o600)
+	if err != nil {
+		t.Fatalf("Error writing rules file: %v", err)
+	}
+
+	if _, err := LoadReferenceRules(invalid, clientset.Discovery()); err == nil {
+		t.Error("Expected an error for a rule without a source version")
+	}
+}
+
+func TestProcessNamespaceCustomResources(t *testing.T) {
+	clientset, dynamicClient, rules := createTestReferenceRuleResources(t)
+
+	diffs, err := processNamespaceCustomResources(clientset, dynamicClient, testNamespace, &filters.Options{}, common.Opts{ReferenceRules: rules})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "widget-dangling", Reason: "References missing ConfigMap missing-config", ReportOnly: true},
+		{Name: "widget-no-pods", Reason: "Selector matches no pods", ReportOnly: true},
+	}
+
+	widgets := diffs[testWidgetRule]
+	if len(widgets) != len(expected) {
+		t.Fatalf("Expected %d unused widgets, got %d: %v", len(expected), len(widgets), widgets)
+	}
+	for i, widget := range widgets {
+		if widget != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], widget)
+		}
+	}
+}
+
+func TestRuleReferencesCountAsConfigMapUsage(t *testing.T) {
+	clientset, dynamicClient, rules := createTestReferenceRuleResources(t)
+
+	unusedCMs, err := processNamespaceCM(clientset, dynamicClient, testNamespace, &filters.Options{}, common.Opts{ReferenceRules: rules})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(unusedCMs) != 1 || unusedCMs[0].Name != "orphan-config" {
+		t.Errorf("Expected only orphan-config to be unused, got %v", unusedCMs)
+	}
+}
+
+func TestRuleReferencesCountAsServiceUsage(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	endpointSlices := []*discoveryv1.EndpointSlice{
+		CreateTestEndpoint(testNamespace, "widget-svc", 0, map[string]string{}),
+		CreateTestEndpoint(testNamespace, "retired-svc", 0, map[string]string{"kor/used": "false"}),
+	}
+	for _, endpointSlice := range endpointSlices {
+		if _, err := clientset.DiscoveryV1().EndpointSlices(testNamespace).Create(context.TODO(), endpointSlice, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake endpointslice: %v", err)
+		}
+	}
+
+	widget := createTestWidget("widget", map[string]interface{}{"serviceRefs": []interface{}{"widget-svc", "retired-svc"}})
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		testWidgetRule.GVR(): "WidgetList",
+	}, widget)
+	rules := &common.ReferenceRules{
+		References: []common.ReferenceRule{
+			{Source: testWidgetRule, Target: common.RuleResource{Version: "v1", Resource: "services", Kind: "Service"}, Path: ".spec.serviceRefs"},
+		},
+	}
+
+	// The kor/used=false label wins over rule references
+	unusedServices, err := processNamespaceServices(clientset, dynamicClient, testNamespace, &filters.Options{}, common.Opts{ReferenceRules: rules})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []ResourceInfo{{Name: "retired-svc", Reason: "Marked with unused label"}}
+	if !reflect.DeepEqual(expected, unusedServices) {
+		t.Errorf("Expected %v, got %v", expected, unusedServices)
+	}
+}
@@ -2,25 +2,91 @@ package kor
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
 
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/yonahd/kor/pkg/common"
 	"github.com/yonahd/kor/pkg/filters"
 )
 
+// deploymentRevisionAnnotation is set by the Deployment controller on every ReplicaSet it rolls out
+const deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+
+func retrieveReplicaSetRevision(replicaSet appsv1.ReplicaSet) int64 {
+	revision, _ := strconv.ParseInt(replicaSet.Annotations[deploymentRevisionAnnotation], 10, 64)
+	return revision
+}
+
+// isReplicaSetIdle reports whether a ReplicaSet is scaled to 0 and has no pods left
+func isReplicaSetIdle(replicaSet appsv1.ReplicaSet) bool {
+	return *replicaSet.Spec.Replicas == 0 && replicaSet.Status.AvailableReplicas == 0 && replicaSet.Status.ReadyReplicas == 0 && replicaSet.Status.FullyLabeledReplicas == 0
+}
+
+// retrieveRollbackHistoryLimit returns how many old ReplicaSets of a Deployment are kept for rollbacks,
+// which is its revisionHistoryLimit unless --rollback-history overrides it
+func retrieveRollbackHistoryLimit(deployment appsv1.Deployment, opts common.Opts) int {
+	switch {
+	case opts.RollbackHistory >= 0:
+		return opts.RollbackHistory
+	case deployment.Spec.RevisionHistoryLimit != nil:
+		return int(*deployment.Spec.RevisionHistoryLimit)
+	default:
+		return defaultRevisionHistoryLimit
+	}
+}
+
+// retrieveReplicaSetsBeyondHistoryLimit returns the oldest idle ReplicaSets of a Deployment that exceed its rollback history.
+// The ReplicaSet with the newest revision is the current one and never part of the history, even when scaled to 0.
+func retrieveReplicaSetsBeyondHistoryLimit(replicaSets []appsv1.ReplicaSet, limit int) []appsv1.ReplicaSet {
+	slices.SortFunc(replicaSets, func(a, b appsv1.ReplicaSet) int {
+		return cmp.Or(
+			cmp.Compare(retrieveReplicaSetRevision(b), retrieveReplicaSetRevision(a)),
+			b.CreationTimestamp.Compare(a.CreationTimestamp.Time),
+		)
+	})
+
+	var history []appsv1.ReplicaSet
+	for i, replicaSet := range replicaSets {
+		if i == 0 || !isReplicaSetIdle(replicaSet) {
+			continue
+		}
+		history = append(history, replicaSet)
+	}
+
+	if len(history) <= limit {
+		return nil
+	}
+	return history[limit:]
+}
+
 func processNamespaceReplicaSets(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
 	replicaSetList, err := clientset.AppsV1().ReplicaSets(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
 	if err != nil {
 		return nil, err
 	}
 
+	deploymentList, err := clientset.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	deployments := make(map[types.UID]appsv1.Deployment, len(deploymentList.Items))
+	for _, deployment := range deploymentList.Items {
+		deployments[deployment.UID] = deployment
+	}
+
 	var unusedReplicaSetNames []ResourceInfo
+	replicaSetsByDeployment := make(map[types.UID][]appsv1.ReplicaSet)
 
 	for _, replicaSet := range replicaSetList.Items {
 		if pass, _ := filter.SetObject(&replicaSet).Run(filterOpts); pass {
@@ -38,12 +104,37 @@ func processNamespaceReplicaSets(clientset kubernetes.Interface, namespace strin
 			continue
 		}
 
+		// ReplicaSets of a Deployment are kept for rollbacks up to its revisionHistoryLimit
+		if controller := metav1.GetControllerOf(&replicaSet); controller != nil && controller.Kind == "Deployment" {
+			if _, exists := deployments[controller.UID]; exists {
+				replicaSetsByDeployment[controller.UID] = append(replicaSetsByDeployment[controller.UID], replicaSet)
+				continue
+			}
+			if isReplicaSetIdle(replicaSet) {
+				reason := fmt.Sprintf("Owner Deployment %s no longer exists", controller.Name)
+				unusedReplicaSetNames = append(unusedReplicaSetNames, ResourceInfo{Name: replicaSet.Name, Reason: reason})
+			}
+			continue
+		}
+
 		// if the replicaSet is specified 0 replica and current available & ready & fullyLabeled replica count is all 0, think the replicaSet is completed
-		if *replicaSet.Spec.Replicas == 0 && replicaSet.Status.AvailableReplicas == 0 && replicaSet.Status.ReadyReplicas == 0 && replicaSet.Status.FullyLabeledReplicas == 0 {
+		if isReplicaSetIdle(replicaSet) {
 			reason := "ReplicaSet is not in use"
 			unusedReplicaSetNames = append(unusedReplicaSetNames, ResourceInfo{Name: replicaSet.Name, Reason: reason})
 		}
 	}
+
+	deploymentUIDs := slices.SortedFunc(maps.Keys(replicaSetsByDeployment), func(a, b types.UID) int {
+		return cmp.Compare(deployments[a].Name, deployments[b].Name)
+	})
+	for _, uid := range deploymentUIDs {
+		deployment := deployments[uid]
+		limit := retrieveRollbackHistoryLimit(deployment, opts)
+		for _, replicaSet := range retrieveReplicaSetsBeyondHistoryLimit(replicaSetsByDeployment[uid], limit) {
+			reason := fmt.Sprintf("Beyond rollback history of %d of Deployment %s", limit, deployment.Name)
+			unusedReplicaSetNames = append(unusedReplicaSetNames, ResourceInfo{Name: replicaSet.Name, Reason: reason})
+		}
+	}
 	if opts.DeleteFlag {
 		if unusedReplicaSetNames, err = DeleteResource(unusedReplicaSetNames, clientset, namespace, "ReplicaSet", opts.NoInteractive); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to delete ReplicaSet %s in namespace %s: %v\n", unusedReplicaSetNames, namespace, err)
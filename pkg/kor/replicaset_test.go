@@ -10,6 +10,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 
@@ -169,6 +170,78 @@ func TestFilterDeploymentOwnedReplicaSets(t *testing.T) {
 	}
 }
 
+func TestProcessNamespaceReplicaSetsRollbackHistory(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	historyLimits := map[string]int32{"web": 2, "admin": 0}
+	for name, historyLimit := range historyLimits {
+		deployment := CreateTestDeployment(testNamespace, name, 1, AppLabels)
+		deployment.UID = types.UID(name + "-uid")
+		deployment.Spec.RevisionHistoryLimit = &historyLimit
+		if _, err := clientset.AppsV1().Deployments(testNamespace).Create(context.TODO(), deployment, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake deployment: %v", err)
+		}
+	}
+
+	controller := true
+	var zero, one int32 = 0, 1
+	newReplicaSet := func(name, revision string, replicas *int32, ownerName string, ownerUID types.UID) *appsv1.ReplicaSet {
+		replicaSet := CreateTestReplicaSet(testNamespace, name, replicas, &appsv1.ReplicaSetStatus{Replicas: *replicas})
+		replicaSet.Annotations = map[string]string{deploymentRevisionAnnotation: revision}
+		replicaSet.OwnerReferences = []v1.OwnerReference{{Kind: "Deployment", Name: ownerName, UID: ownerUID, Controller: &controller}}
+		return replicaSet
+	}
+
+	replicaSets := []*appsv1.ReplicaSet{
+		newReplicaSet("web-5", "5", &one, "web", "web-uid"),
+		newReplicaSet("web-4", "4", &zero, "web", "web-uid"),
+		newReplicaSet("web-3", "3", &zero, "web", "web-uid"),
+		newReplicaSet("web-2", "2", &zero, "web", "web-uid"),
+		newReplicaSet("web-1", "1", &zero, "web", "web-uid"),
+		newReplicaSet("api-1", "1", &zero, "api", "api-uid"),
+		newReplicaSet("admin-2", "2", &one, "admin", "admin-uid"),
+		newReplicaSet("admin-1", "1", &zero, "admin", "admin-uid"),
+	}
+	for _, replicaSet := range replicaSets {
+		if _, err := clientset.AppsV1().ReplicaSets(testNamespace).Create(context.TODO(), replicaSet, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake replicaSet: %v", err)
+		}
+	}
+
+	unusedReplicaSets, err := processNamespaceReplicaSets(clientset, testNamespace, &filters.Options{}, common.NewOpts())
+	if err != nil {
+		t.Fatalf("Error retrieving unused replica sets: %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "api-1", Reason: "Owner Deployment api no longer exists"},
+		{Name: "admin-1", Reason: "Beyond rollback history of 0 of Deployment admin"},
+		{Name: "web-2", Reason: "Beyond rollback history of 2 of Deployment web"},
+		{Name: "web-1", Reason: "Beyond rollback history of 2 of Deployment web"},
+	}
+	if !reflect.DeepEqual(expected, unusedReplicaSets) {
+		t.Errorf("Expected %v, got %v", expected, unusedReplicaSets)
+	}
+
+	// --rollback-history overrides the revisionHistoryLimit of the Deployment
+	unusedReplicaSets, err = processNamespaceReplicaSets(clientset, testNamespace, &filters.Options{}, common.Opts{RollbackHistory: 3})
+	if err != nil {
+		t.Fatalf("Error retrieving unused replica sets: %v", err)
+	}
+	if len(unusedReplicaSets) != 2 || unusedReplicaSets[1].Name != "web-1" {
+		t.Errorf("Expected api-1 and web-1 with a rollback history of 3, got %v", unusedReplicaSets)
+	}
+
+	// An explicit 0 keeps no rollback history at all
+	unusedReplicaSets, err = processNamespaceReplicaSets(clientset, testNamespace, &filters.Options{}, common.Opts{RollbackHistory: 0})
+	if err != nil {
+		t.Fatalf("Error retrieving unused replica sets: %v", err)
+	}
+	if len(unusedReplicaSets) != 6 {
+		t.Errorf("Expected api-1 and every old ReplicaSet with a rollback history of 0, got %v", unusedReplicaSets)
+	}
+}
+
 func init() {
 	scheme.Scheme = runtime.NewScheme()
 	_ = appsv1.AddToScheme(scheme.Scheme)
@@ -2,11 +2,13 @@ package kor
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	_ "embed"
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	v1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -40,6 +42,27 @@ func isUsingValidServiceAccount(serviceAccounts []v1.Subject, serviceAccountName
 	return false
 }
 
+// retrieveStaleSubjects describes the subjects that can no longer use a binding: ServiceAccounts whose
+// namespace no longer exists and, when audit logs are loaded, Users and Groups that stopped making requests.
+// fromAuditLog is set when any of them is judged from the audit logs, which may well be partial.
+func retrieveStaleSubjects(subjects []v1.Subject, defaultNamespace string, namespaceNames map[string]bool, opts common.Opts) (staleSubjects []string, fromAuditLog bool) {
+	for _, subject := range subjects {
+		switch subject.Kind {
+		case v1.ServiceAccountKind:
+			namespace := cmp.Or(subject.Namespace, defaultNamespace)
+			if namespace != "" && !namespaceNames[namespace] {
+				staleSubjects = append(staleSubjects, fmt.Sprintf("ServiceAccount %s/%s namespace no longer exists", namespace, subject.Name))
+			}
+		case v1.UserKind, v1.GroupKind:
+			if reason := retrieveInactiveSubjectReason(subject, opts); reason != "" {
+				staleSubjects = append(staleSubjects, reason)
+				fromAuditLog = true
+			}
+		}
+	}
+	return staleSubjects, fromAuditLog
+}
+
 func validateRoleReference(rb v1.RoleBinding, roleNames, clusterRoleNames map[string]bool) *ResourceInfo {
 	if rb.RoleRef.Kind == "Role" && !roleNames[rb.RoleRef.Name] {
 		return &ResourceInfo{Name: rb.Name, Reason: "RoleBinding references a non-existing Role"}
@@ -52,7 +75,7 @@ func validateRoleReference(rb v1.RoleBinding, roleNames, clusterRoleNames map[st
 	return nil
 }
 
-func processNamespaceRoleBindings(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+func processNamespaceRoleBindings(clientset kubernetes.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
 	roleBindingsList, err := clientset.RbacV1().RoleBindings(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
 	if err != nil {
 		return nil, err
@@ -78,7 +101,13 @@ func processNamespaceRoleBindings(clientset kubernetes.Interface, namespace stri
 		return nil, err
 	}
 
+	namespaceNames, err := cache.retrieveNamespaceNames(clientset)
+	if err != nil {
+		return nil, err
+	}
+
 	var unusedRoleBindingNames []ResourceInfo
+	var staleRoleBindings []ResourceInfo
 
 	for _, rb := range roleBindingsList.Items {
 		// Skip resources with ownerReferences if the general flag is set
@@ -110,14 +139,22 @@ func processNamespaceRoleBindings(clientset kubernetes.Interface, namespace stri
 
 		serviceAccountSubjects := filterSubjects(rb.Subjects, "ServiceAccount")
 
-		// If other kinds (Users/Groups) are used, we assume they exists for now
-		if len(serviceAccountSubjects) != len(rb.Subjects) {
+		// Check if a RoleBinding of only ServiceAccounts uses a valid service account
+		if len(serviceAccountSubjects) == len(rb.Subjects) && !isUsingValidServiceAccount(serviceAccountSubjects, serviceAccountNames) {
+			unusedRoleBindingNames = append(unusedRoleBindingNames, ResourceInfo{Name: rb.Name, Reason: "RoleBinding references a non-existing ServiceAccount"})
 			continue
 		}
 
-		// Check if RoleBinding uses a valid service account
-		if !isUsingValidServiceAccount(serviceAccountSubjects, serviceAccountNames) {
-			unusedRoleBindingNames = append(unusedRoleBindingNames, ResourceInfo{Name: rb.Name, Reason: "RoleBinding references a non-existing ServiceAccount"})
+		staleSubjects, fromAuditLog := retrieveStaleSubjects(rb.Subjects, rb.Namespace, namespaceNames, opts)
+		if len(staleSubjects) == 0 {
+			continue
+		}
+		if fromAuditLog && len(staleSubjects) == len(rb.Subjects) {
+			staleRoleBindings = append(staleRoleBindings, ResourceInfo{Name: rb.Name, Reason: "RoleBinding has no active subjects: " + strings.Join(staleSubjects, ", "), ReportOnly: true})
+		} else if len(staleSubjects) == len(rb.Subjects) {
+			unusedRoleBindingNames = append(unusedRoleBindingNames, ResourceInfo{Name: rb.Name, Reason: "RoleBinding has no active subjects: " + strings.Join(staleSubjects, ", ")})
+		} else {
+			staleRoleBindings = append(staleRoleBindings, ResourceInfo{Name: rb.Name, Reason: "RoleBinding has stale subjects: " + strings.Join(staleSubjects, ", "), ReportOnly: true})
 		}
 	}
 	if opts.DeleteFlag {
@@ -125,13 +162,14 @@ func processNamespaceRoleBindings(clientset kubernetes.Interface, namespace stri
 			fmt.Fprintf(os.Stderr, "Failed to delete RoleBinding %s in namespace %s: %v\n", unusedRoleBindingNames, namespace, err)
 		}
 	}
-	return unusedRoleBindingNames, nil
+	return append(unusedRoleBindingNames, staleRoleBindings...), nil
 }
 
 func GetUnusedRoleBindings(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
 	resources := make(map[string]map[string][]ResourceInfo)
+	cache := newClusterCache()
 	for _, namespace := range filterOpts.Namespaces(clientset) {
-		diff, err := processNamespaceRoleBindings(clientset, namespace, filterOpts, opts)
+		diff, err := processNamespaceRoleBindings(clientset, cache, namespace, filterOpts, opts)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to process namespace %s: %v\n", namespace, err)
 			continue
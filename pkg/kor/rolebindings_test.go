@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"reflect"
 	"testing"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -101,7 +102,7 @@ func createTestRoleBindings(t *testing.T) *fake.Clientset {
 func TestProcessNamespaceRoleBindings(t *testing.T) {
 	clientset := createTestRoleBindings(t)
 
-	unusedRoleBindings, err := processNamespaceRoleBindings(clientset, testNamespace, &filters.Options{}, common.Opts{})
+	unusedRoleBindings, err := processNamespaceRoleBindings(clientset, newClusterCache(), testNamespace, &filters.Options{}, common.Opts{})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -161,3 +162,57 @@ func init() {
 	scheme.Scheme = runtime.NewScheme()
 	_ = appsv1.AddToScheme(scheme.Scheme)
 }
+
+func TestProcessNamespaceRoleBindingsStaleSubjects(t *testing.T) {
+	clientset := createTestRoleBindings(t)
+	opts := common.Opts{
+		AuditLogActivity: &common.SubjectActivity{
+			Users:  map[string]time.Time{"alice": time.Now()},
+			Groups: map[string]time.Time{},
+			Since:  time.Now().AddDate(-1, 0, 0),
+		},
+		SubjectInactiveDays: 30,
+	}
+
+	roleRef := &rbacv1.RoleRef{Kind: "Role", Name: "existing-role"}
+	bindings := map[string][]rbacv1.Subject{
+		"test-rb-inactive": {
+			{Kind: rbacv1.UserKind, Name: "bob"},
+			{Kind: rbacv1.ServiceAccountKind, Name: "ci", Namespace: "deleted-namespace"},
+		},
+		"test-rb-partially-stale": {
+			{Kind: rbacv1.UserKind, Name: "alice"},
+			{Kind: rbacv1.GroupKind, Name: "contractors"},
+		},
+		"test-rb-system-group": {
+			{Kind: rbacv1.GroupKind, Name: "system:unauthenticated"},
+		},
+		"test-rb-active": {
+			{Kind: rbacv1.UserKind, Name: "alice"},
+		},
+	}
+	for name, subjects := range bindings {
+		roleBinding := CreateTestRoleBinding(testNamespace, name, "", roleRef)
+		roleBinding.Subjects = subjects
+		if _, err := clientset.RbacV1().RoleBindings(testNamespace).Create(context.TODO(), roleBinding, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake RoleBinding: %v", err)
+		}
+	}
+
+	unusedRoleBindings, err := processNamespaceRoleBindings(clientset, newClusterCache(), testNamespace, &filters.Options{}, opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "test-rb1", Reason: "RoleBinding references a non-existing Role"},
+		{Name: "test-rb2", Reason: "RoleBinding references a non-existing ClusterRole"},
+		{Name: "test-rb3", Reason: "RoleBinding references a non-existing ServiceAccount"},
+		// Audit logs may be partial, so inactive subjects never make a binding deletable
+		{Name: "test-rb-inactive", Reason: "RoleBinding has no active subjects: User bob made no request in 30 days, ServiceAccount deleted-namespace/ci namespace no longer exists", ReportOnly: true},
+		{Name: "test-rb-partially-stale", Reason: "RoleBinding has stale subjects: Group contractors made no request in 30 days", ReportOnly: true},
+	}
+	if !reflect.DeepEqual(expected, unusedRoleBindings) {
+		t.Errorf("Expected %v, got %v", expected, unusedRoleBindings)
+	}
+}
@@ -5,10 +5,19 @@ import (
 	"context"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"strings"
 
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
 
@@ -19,6 +28,210 @@ import (
 //go:embed exceptions/roles/roles.json
 var rolesConfig []byte
 
+// apiResourceIndex holds the resources, including subresources, served by each API group
+type apiResourceIndex struct {
+	resources map[string]map[string]metav1.APIResource
+	// preferredVersions is used to look up objects named in resourceNames
+	preferredVersions map[string]string
+	// unknownGroups failed discovery, so rules referencing them are not evaluated
+	unknownGroups map[string]bool
+	// existingObjects remembers the resourceNames already looked up, which many roles tend to share
+	existingObjects map[objectReference]bool
+}
+
+type objectReference struct {
+	gvr       schema.GroupVersionResource
+	namespace string
+	name      string
+}
+
+func retrieveAPIResourceIndex(discoveryClient discovery.DiscoveryInterface) (*apiResourceIndex, error) {
+	index := &apiResourceIndex{
+		resources:         make(map[string]map[string]metav1.APIResource),
+		preferredVersions: make(map[string]string),
+		unknownGroups:     make(map[string]bool),
+		existingObjects:   make(map[objectReference]bool),
+	}
+
+	groups, resourceLists, err := discoveryClient.ServerGroupsAndResources()
+	if err != nil {
+		var groupDiscoveryErr *discovery.ErrGroupDiscoveryFailed
+		if !errors.As(err, &groupDiscoveryErr) {
+			return nil, err
+		}
+		for groupVersion := range groupDiscoveryErr.Groups {
+			index.unknownGroups[groupVersion.Group] = true
+		}
+	}
+
+	for _, group := range groups {
+		index.preferredVersions[group.Name] = group.PreferredVersion.Version
+	}
+	for _, resourceList := range resourceLists {
+		groupVersion, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			return nil, err
+		}
+		if index.resources[groupVersion.Group] == nil {
+			index.resources[groupVersion.Group] = make(map[string]metav1.APIResource)
+		}
+		if _, ok := index.preferredVersions[groupVersion.Group]; !ok {
+			index.preferredVersions[groupVersion.Group] = groupVersion.Version
+		}
+		for _, resource := range resourceList.APIResources {
+			index.resources[groupVersion.Group][resource.Name] = resource
+		}
+	}
+	return index, nil
+}
+
+// serves reports whether an API group and resource of a PolicyRule, which may use wildcards, match a served resource
+func (i *apiResourceIndex) serves(group, resource string) bool {
+	if i.unknownGroups[group] {
+		return true
+	}
+	groups := []string{group}
+	if group == rbacv1.APIGroupAll {
+		groups = slices.Collect(maps.Keys(i.resources))
+	}
+
+	base, subresource, hasSubresource := strings.Cut(resource, "/")
+	for _, group := range groups {
+		for name := range i.resources[group] {
+			if resource == rbacv1.ResourceAll || name == resource {
+				return true
+			}
+			servedBase, servedSubresource, servedHasSubresource := strings.Cut(name, "/")
+			if hasSubresource && servedHasSubresource &&
+				(base == rbacv1.ResourceAll || base == servedBase) &&
+				(subresource == rbacv1.ResourceAll || subresource == servedSubresource) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func formatGroupResource(group, resource string) string {
+	return schema.GroupResource{Group: group, Resource: resource}.String()
+}
+
+// retrieveMissingResourceNames returns the resourceNames of a rule that no longer exist for a served resource,
+// and whether they could be checked at all. Namespaced resources are only looked up for Roles,
+// since a ClusterRole may be bound in any namespace.
+func retrieveMissingResourceNames(dynamicClient dynamic.Interface, index *apiResourceIndex, namespace, group, resource string, names []string) ([]string, bool, error) {
+	if group == rbacv1.APIGroupAll || resource == rbacv1.ResourceAll || strings.Contains(resource, "/") || index.unknownGroups[group] {
+		return nil, false, nil
+	}
+	apiResource := index.resources[group][resource]
+	if apiResource.Namespaced && namespace == "" {
+		return nil, false, nil
+	}
+	if !apiResource.Namespaced {
+		namespace = ""
+	}
+
+	gvr := schema.GroupVersionResource{Group: group, Version: index.preferredVersions[group], Resource: resource}
+	var missing []string
+	for _, name := range names {
+		ref := objectReference{gvr: gvr, namespace: namespace, name: name}
+		exists, found := index.existingObjects[ref]
+		if !found {
+			_, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+			// Without read access to the objects the rule can't be judged
+			if apierrors.IsForbidden(err) {
+				return nil, false, nil
+			}
+			if err != nil && !apierrors.IsNotFound(err) {
+				return nil, false, err
+			}
+			exists = err == nil
+			index.existingObjects[ref] = exists
+		}
+		if !exists {
+			missing = append(missing, name)
+		}
+	}
+	return missing, true, nil
+}
+
+// analyzePolicyRule explains which parts of a rule point to nothing, and whether the rule grants nothing at all
+func analyzePolicyRule(rule rbacv1.PolicyRule, index *apiResourceIndex, dynamicClient dynamic.Interface, namespace string) (string, bool, error) {
+	var details []string
+	var missingResources []string
+	var servedResources []schema.GroupResource
+	for _, group := range rule.APIGroups {
+		for _, resource := range rule.Resources {
+			if index.serves(group, resource) {
+				servedResources = append(servedResources, schema.GroupResource{Group: group, Resource: resource})
+			} else {
+				missingResources = append(missingResources, formatGroupResource(group, resource))
+			}
+		}
+	}
+	if len(missingResources) > 0 {
+		details = append(details, "references missing resources "+strings.Join(missingResources, ", "))
+		if len(servedResources) == 0 {
+			return strings.Join(details, ""), true, nil
+		}
+	}
+
+	if len(rule.ResourceNames) > 0 && len(servedResources) > 0 && dynamicClient != nil {
+		missingNames := make(map[string]bool)
+		allGone := true
+		for _, served := range servedResources {
+			missing, checked, err := retrieveMissingResourceNames(dynamicClient, index, namespace, served.Group, served.Resource, rule.ResourceNames)
+			if err != nil {
+				return "", false, err
+			}
+			if !checked || len(missing) < len(rule.ResourceNames) {
+				allGone = false
+			}
+			for _, name := range missing {
+				missingNames[name] = true
+			}
+		}
+		if len(missingNames) > 0 {
+			details = append(details, "references missing resourceNames "+strings.Join(slices.Sorted(maps.Keys(missingNames)), ", "))
+			if allGone {
+				return strings.Join(details, " and "), true, nil
+			}
+		}
+	}
+
+	return strings.Join(details, " and "), false, nil
+}
+
+// retrieveDeadRulesReason describes the rules of a Role or ClusterRole that grant nothing in this cluster,
+// or returns an empty string when every rule is valid
+func retrieveDeadRulesReason(kind string, rules []rbacv1.PolicyRule, index *apiResourceIndex, dynamicClient dynamic.Interface, namespace string) (string, error) {
+	// Without any discovered resources every rule would look dead
+	if len(index.resources) == 0 {
+		return "", nil
+	}
+	var details []string
+	deadRules := 0
+	for i, rule := range rules {
+		detail, dead, err := analyzePolicyRule(rule, index, dynamicClient, namespace)
+		if err != nil {
+			return "", err
+		}
+		if dead {
+			deadRules++
+		}
+		if detail != "" {
+			details = append(details, fmt.Sprintf("rule %d %s", i+1, detail))
+		}
+	}
+	if len(details) == 0 {
+		return "", nil
+	}
+	if deadRules == len(rules) {
+		return fmt.Sprintf("%s is fully dead: %s", kind, strings.Join(details, "; ")), nil
+	}
+	return fmt.Sprintf("%s is partially dead: %s", kind, strings.Join(details, "; ")), nil
+}
+
 func retrieveUsedRoles(clientset kubernetes.Interface, namespace string) ([]string, error) {
 	// Get a list of all role bindings in the specified namespace
 	roleBindings, err := clientset.RbacV1().RoleBindings(namespace).List(context.TODO(), metav1.ListOptions{})
@@ -78,7 +291,39 @@ func retrieveRoleNames(clientset kubernetes.Interface, namespace string, filterO
 	return names, unusedRoleNames, nil
 }
 
-func processNamespaceRoles(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+// retrieveDeadRoles reports the named Roles whose rules reference resources or objects that don't exist
+func retrieveDeadRoles(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, names []string) ([]ResourceInfo, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	roles, err := clientset.RbacV1().Roles(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := cache.retrieveAPIResourceIndex(clientset)
+	if err != nil {
+		return nil, err
+	}
+
+	var deadRoles []ResourceInfo
+	for _, role := range roles.Items {
+		if !slices.Contains(names, role.Name) {
+			continue
+		}
+		reason, err := retrieveDeadRulesReason("Role", role.Rules, index, dynamicClient, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			deadRoles = append(deadRoles, ResourceInfo{Name: role.Name, Reason: reason, ReportOnly: true})
+		}
+	}
+	return deadRoles, nil
+}
+
+func processNamespaceRoles(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
 	usedRoles, err := retrieveUsedRoles(clientset, namespace)
 	if err != nil {
 		return nil, err
@@ -107,13 +352,21 @@ func processNamespaceRoles(clientset kubernetes.Interface, namespace string, fil
 			fmt.Fprintf(os.Stderr, "Failed to delete Role %s in namespace %s: %v\n", diff, namespace, err)
 		}
 	}
-	return diff, nil
+
+	deadRoles, err := retrieveDeadRoles(clientset, dynamicClient, cache, namespace, slices.DeleteFunc(roleInfos, func(name string) bool {
+		return !slices.Contains(usedRoles, name)
+	}))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to check the rules of Roles in namespace %s: %v\n", namespace, err)
+	}
+	return append(diff, deadRoles...), nil
 }
 
-func GetUnusedRoles(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
+func GetUnusedRoles(filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
 	resources := make(map[string]map[string][]ResourceInfo)
+	cache := newClusterCache()
 	for _, namespace := range filterOpts.Namespaces(clientset) {
-		diff, err := processNamespaceRoles(clientset, namespace, filterOpts, opts)
+		diff, err := processNamespaceRoles(clientset, dynamicClient, cache, namespace, filterOpts, opts)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to process namespace %s: %v\n", namespace, err)
 			continue
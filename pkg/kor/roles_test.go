@@ -3,15 +3,21 @@ package kor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
 
 	"github.com/yonahd/kor/pkg/common"
 	"github.com/yonahd/kor/pkg/filters"
@@ -93,7 +99,7 @@ func TestRetrieveRoleNames(t *testing.T) {
 func TestProcessNamespaceRoles(t *testing.T) {
 	clientset := createTestRoles(t)
 
-	unusedRoles, err := processNamespaceRoles(clientset, testNamespace, &filters.Options{}, common.Opts{})
+	unusedRoles, err := processNamespaceRoles(clientset, nil, newClusterCache(), testNamespace, &filters.Options{}, common.Opts{})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -119,7 +125,7 @@ func TestGetUnusedRolesStructured(t *testing.T) {
 		GroupBy:       "namespace",
 	}
 
-	output, err := GetUnusedRoles(&filters.Options{}, clientset, "json", opts)
+	output, err := GetUnusedRoles(&filters.Options{}, clientset, nil, "json", opts)
 	if err != nil {
 		t.Fatalf("Error calling GetUnusedRolesStructured: %v", err)
 	}
@@ -180,7 +186,7 @@ func TestFilterOwnerReferencedRoles(t *testing.T) {
 
 	// Test without filter - should return both
 	filterOptsNoSkip := &filters.Options{IgnoreOwnerReferences: false}
-	unusedWithoutFilter, err := processNamespaceRoles(clientset, testNamespace, filterOptsNoSkip, common.Opts{})
+	unusedWithoutFilter, err := processNamespaceRoles(clientset, nil, newClusterCache(), testNamespace, filterOptsNoSkip, common.Opts{})
 	if err != nil {
 		t.Fatalf("Error retrieving unused Roles: %v", err)
 	}
@@ -191,7 +197,7 @@ func TestFilterOwnerReferencedRoles(t *testing.T) {
 
 	// Test with filter - should return only standalone
 	filterOptsWithSkip := &filters.Options{IgnoreOwnerReferences: true}
-	unusedWithFilter, err := processNamespaceRoles(clientset, testNamespace, filterOptsWithSkip, common.Opts{})
+	unusedWithFilter, err := processNamespaceRoles(clientset, nil, newClusterCache(), testNamespace, filterOptsWithSkip, common.Opts{})
 	if err != nil {
 		t.Fatalf("Error retrieving unused Roles: %v", err)
 	}
@@ -209,3 +215,84 @@ func init() {
 	scheme.Scheme = runtime.NewScheme()
 	_ = appsv1.AddToScheme(scheme.Scheme)
 }
+
+func TestProcessNamespaceRolesDeadRules(t *testing.T) {
+	clientset := fake.NewClientset()
+	clientset.Resources = []*v1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []v1.APIResource{
+				{Name: "pods", Namespaced: true, Kind: "Pod"},
+				{Name: "configmaps", Namespaced: true, Kind: "ConfigMap"},
+			},
+		},
+	}
+
+	podsRule := rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get"}}
+	roles := map[string][]rbacv1.PolicyRule{
+		"fully-dead": {
+			{APIGroups: []string{"example.com"}, Resources: []string{"widgets"}, Verbs: []string{"get"}},
+		},
+		"partially-dead": {
+			podsRule,
+			{APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{"present", "gone"}, Verbs: []string{"get"}},
+		},
+		"valid": {podsRule},
+	}
+	for name, rules := range roles {
+		role := CreateTestRole(testNamespace, name, AppLabels)
+		role.Rules = rules
+		if _, err := clientset.RbacV1().Roles(testNamespace).Create(context.TODO(), role, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake Role: %v", err)
+		}
+		roleBinding := CreateTestRoleBinding(testNamespace, name, "test-sa", CreateTestRoleRef(name))
+		if _, err := clientset.RbacV1().RoleBindings(testNamespace).Create(context.TODO(), roleBinding, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake RoleBinding: %v", err)
+		}
+	}
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), CreateTestUnstructered("ConfigMap", "v1", testNamespace, "present"))
+
+	unusedRoles, err := processNamespaceRoles(clientset, dynamicClient, newClusterCache(), testNamespace, &filters.Options{}, common.Opts{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "fully-dead", Reason: "Role is fully dead: rule 1 references missing resources widgets.example.com", ReportOnly: true},
+		{Name: "partially-dead", Reason: "Role is partially dead: rule 2 references missing resourceNames gone", ReportOnly: true},
+	}
+	if !reflect.DeepEqual(expected, unusedRoles) {
+		t.Errorf("Expected %v, got %v", expected, unusedRoles)
+	}
+
+	// resourceNames that can't be read are left unchecked
+	forbiddenClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	forbiddenClient.PrependReactor("get", "configmaps", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "gone", errors.New("access denied"))
+	})
+	unusedRoles, err = processNamespaceRoles(clientset, forbiddenClient, newClusterCache(), testNamespace, &filters.Options{}, common.Opts{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected = expected[:1]
+	if !reflect.DeepEqual(expected, unusedRoles) {
+		t.Errorf("Expected %v, got %v", expected, unusedRoles)
+	}
+
+	// Failing to check the rules must not lose the unused Roles
+	if _, err := clientset.RbacV1().Roles(testNamespace).Create(context.TODO(), CreateTestRole(testNamespace, "unbound", AppLabels), v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake Role: %v", err)
+	}
+	dynamicClient.PrependReactor("get", "configmaps", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+	unusedRoles, err = processNamespaceRoles(clientset, dynamicClient, newClusterCache(), testNamespace, &filters.Options{}, common.Opts{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected = []ResourceInfo{{Name: "unbound", Reason: "ServiceAccount is not in use"}}
+	if !reflect.DeepEqual(expected, unusedRoles) {
+		t.Errorf("Expected %v, got %v", expected, unusedRoles)
+	}
+}
@@ -0,0 +1,60 @@
+package kor
+
+import (
+	"context"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	nodev1 "k8s.io/api/node/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func createTestRuntimeClasses(t *testing.T) *fake.Clientset {
+	clientset := fake.NewClientset()
+
+	for _, name := range []string{"gvisor", "kata", "wasm", "unused"} {
+		rc := &nodev1.RuntimeClass{ObjectMeta: v1.ObjectMeta{Name: name}, Handler: name}
+		if _, err := clientset.NodeV1().RuntimeClasses().Create(context.TODO(), rc, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake runtimeClass: %v", err)
+		}
+	}
+
+	gvisor, kata, wasm := "gvisor", "kata", "wasm"
+
+	pod := CreateTestPod(testNamespace, "sandboxed-pod", "", nil, AppLabels)
+	pod.Spec.RuntimeClassName = &gvisor
+	if _, err := clientset.CoreV1().Pods(testNamespace).Create(context.TODO(), pod, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake pod: %v", err)
+	}
+
+	// Scaled down workloads still reference their runtime class through the pod template
+	deployment := CreateTestDeployment(testNamespace, "scaled-down", 0, AppLabels)
+	deployment.Spec.Template.Spec.RuntimeClassName = &kata
+	if _, err := clientset.AppsV1().Deployments(testNamespace).Create(context.TODO(), deployment, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake deployment: %v", err)
+	}
+
+	job := CreateTestJob(testNamespace, "wasm-job", &batchv1.JobStatus{}, AppLabels)
+	job.Spec.Template.Spec.RuntimeClassName = &wasm
+	if _, err := clientset.BatchV1().Jobs(testNamespace).Create(context.TODO(), job, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake job: %v", err)
+	}
+
+	return clientset
+}
+
+func TestProcessRuntimeClasses(t *testing.T) {
+	clientset := createTestRuntimeClasses(t)
+
+	unusedRuntimeClasses, err := processRuntimeClasses(clientset, &filters.Options{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(unusedRuntimeClasses) != 1 || unusedRuntimeClasses[0].Name != "unused" {
+		t.Errorf("Expected only unused runtimeClass, got %v", unusedRuntimeClasses)
+	}
+}
@@ -6,10 +6,21 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"slices"
-
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
 
@@ -24,6 +35,24 @@ var exceptionSecretTypes = []string{
 	`kubernetes.io/service-account-token`,
 }
 
+// injectCAFromSecretAnnotation makes the cert-manager CA injector copy the CA of a Secret into
+// webhook configurations and APIServices
+const injectCAFromSecretAnnotation = "cert-manager.io/inject-ca-from-secret"
+
+var (
+	gatewayGVR    = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gateways"}
+	apiServiceGVR = schema.GroupVersionResource{Group: "apiregistration.k8s.io", Version: "v1", Resource: "apiservices"}
+)
+
+// legacyTokenLastUsedLabel is set by the API server on legacy ServiceAccount token secrets when they authenticate
+const legacyTokenLastUsedLabel = "kubernetes.io/legacy-token-last-used"
+
+// legacyTokenTrackingConfigMap records in its "since" key the date the API server started setting legacyTokenLastUsedLabel
+const legacyTokenTrackingConfigMap = "kube-apiserver-legacy-service-account-token-tracking"
+
+// legacyTokenUnusedAge is how long a legacy token has to go unused, while its use is tracked, to be reported
+const legacyTokenUnusedAge = 30 * 24 * time.Hour
+
 //go:embed exceptions/secrets/secrets.json
 var secretsConfig []byte
 
@@ -45,6 +74,7 @@ func retrieveIngressTLS(clientset kubernetes.Interface, namespace string) ([]str
 
 }
 
+// retrieveUsedSecret returns Secrets referenced by pods and by the templates of workloads that may currently run no pods
 func retrieveUsedSecret(clientset kubernetes.Interface, namespace string) ([]string, []string, []string, []string, []string, []string, error) {
 	var envSecrets []string
 	var envSecrets2 []string
@@ -52,15 +82,14 @@ func retrieveUsedSecret(clientset kubernetes.Interface, namespace string) ([]str
 	var pullSecrets []string
 	var initContainerEnvSecrets []string
 
-	// Retrieve pods in the specified namespace
-	pods, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	podSpecs, err := retrieveWorkloadPodSpecs(clientset, namespace)
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, err
 	}
 
-	// Extract volume and environment information from pods
-	for _, pod := range pods.Items {
-		for _, container := range pod.Spec.Containers {
+	// Extract volume and environment information from pods and workload templates
+	for _, podSpec := range podSpecs {
+		for _, container := range podSpec.Spec.Containers {
 			for _, env := range container.Env {
 				if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
 					envSecrets = append(envSecrets, env.ValueFrom.SecretKeyRef.Name)
@@ -73,7 +102,7 @@ func retrieveUsedSecret(clientset kubernetes.Interface, namespace string) ([]str
 			}
 		}
 
-		for _, initContainer := range pod.Spec.InitContainers {
+		for _, initContainer := range podSpec.Spec.InitContainers {
 			for _, env := range initContainer.Env {
 				if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
 					initContainerEnvSecrets = append(initContainerEnvSecrets, env.ValueFrom.SecretKeyRef.Name)
@@ -86,7 +115,7 @@ func retrieveUsedSecret(clientset kubernetes.Interface, namespace string) ([]str
 			}
 		}
 
-		for _, volume := range pod.Spec.Volumes {
+		for _, volume := range podSpec.Spec.Volumes {
 			if volume.Secret != nil {
 				volumeSecrets = append(volumeSecrets, volume.Secret.SecretName)
 			}
@@ -99,8 +128,8 @@ func retrieveUsedSecret(clientset kubernetes.Interface, namespace string) ([]str
 			}
 		}
 
-		if pod.Spec.ImagePullSecrets != nil {
-			for _, secret := range pod.Spec.ImagePullSecrets {
+		if podSpec.Spec.ImagePullSecrets != nil {
+			for _, secret := range podSpec.Spec.ImagePullSecrets {
 				pullSecrets = append(pullSecrets, secret.Name)
 			}
 		}
@@ -158,41 +187,378 @@ func retrieveSecretNames(clientset kubernetes.Interface, namespace string, filte
 	return names, unusedSecretNames, nil
 }
 
-func processNamespaceSecret(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
-	envSecrets, envSecrets2, volumeSecrets, initContainerEnvSecrets, pullSecrets, tlsSecrets, err := retrieveUsedSecret(clientset, namespace)
+// retrieveLegacyTokenTrackingSince returns when the API server started tracking the use of legacy tokens,
+// or the zero time when it doesn't track them
+func retrieveLegacyTokenTrackingSince(clientset kubernetes.Interface) (time.Time, error) {
+	configMap, err := clientset.CoreV1().ConfigMaps(metav1.NamespaceSystem).Get(context.TODO(), legacyTokenTrackingConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	since, err := time.Parse(time.DateOnly, configMap.Data["since"])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid legacy token tracking date %q: %v", configMap.Data["since"], err)
+	}
+	return since, nil
+}
+
+// retrieveUnusedServiceAccountTokens returns legacy long-lived ServiceAccount token secrets whose
+// ServiceAccount no longer exists or that have never been used to authenticate. Tokens are only judged
+// never used once the API server has been tracking them for legacyTokenUnusedAge, and these are never deleted.
+func retrieveUnusedServiceAccountTokens(clientset kubernetes.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options) ([]ResourceInfo, error) {
+	secrets, err := clientset.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
 	if err != nil {
 		return nil, err
 	}
 
-	envSecrets = RemoveDuplicatesAndSort(envSecrets)
-	envSecrets2 = RemoveDuplicatesAndSort(envSecrets2)
-	volumeSecrets = RemoveDuplicatesAndSort(volumeSecrets)
-	initContainerEnvSecrets = RemoveDuplicatesAndSort(initContainerEnvSecrets)
-	pullSecrets = RemoveDuplicatesAndSort(pullSecrets)
-	tlsSecrets = RemoveDuplicatesAndSort(tlsSecrets)
+	serviceAccounts, err := clientset.CoreV1().ServiceAccounts(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
 
-	secretNames, unusedSecretNames, err := retrieveSecretNames(clientset, namespace, filterOpts)
+	serviceAccountNames := make(map[string]bool, len(serviceAccounts.Items))
+	for _, sa := range serviceAccounts.Items {
+		serviceAccountNames[sa.Name] = true
+	}
+
+	config, err := unmarshalConfig(secretsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	trackingSince, err := cache.retrieveLegacyTokenTrackingSince(clientset)
+	if err != nil {
+		return nil, err
+	}
+
+	var unusedTokens []ResourceInfo
+	for _, secret := range secrets.Items {
+		if secret.Type != corev1.SecretTypeServiceAccountToken {
+			continue
+		}
+
+		// Skip resources with ownerReferences if the general flag is set
+		if filterOpts.IgnoreOwnerReferences && len(secret.OwnerReferences) > 0 {
+			continue
+		}
+
+		if pass, _ := filter.SetObject(&secret).Run(filterOpts); pass {
+			continue
+		}
+
+		// Secrets marked with the unused label are already reported by retrieveSecretNames
+		if secret.Labels["kor/used"] == "false" {
+			continue
+		}
+
+		exceptionFound, err := isResourceException(secret.Name, secret.Namespace, config.ExceptionSecrets)
+		if err != nil {
+			return nil, err
+		}
+
+		if exceptionFound {
+			continue
+		}
+
+		serviceAccountName := secret.Annotations[corev1.ServiceAccountNameKey]
+		if !serviceAccountNames[serviceAccountName] {
+			reason := fmt.Sprintf("ServiceAccount token for non-existing ServiceAccount %s", serviceAccountName)
+			unusedTokens = append(unusedTokens, ResourceInfo{Name: secret.Name, Reason: reason})
+			continue
+		}
+
+		if _, used := secret.Labels[legacyTokenLastUsedLabel]; used || trackingSince.IsZero() {
+			continue
+		}
+		trackedSince := trackingSince
+		if secret.CreationTimestamp.After(trackedSince) {
+			trackedSince = secret.CreationTimestamp.Time
+		}
+		trackedFor := time.Since(trackedSince)
+		if trackedFor >= legacyTokenUnusedAge {
+			reason := fmt.Sprintf("Legacy ServiceAccount token has not been used in %d days", int(trackedFor.Hours()/24))
+			unusedTokens = append(unusedTokens, ResourceInfo{Name: secret.Name, Reason: reason, ReportOnly: true})
+		}
+	}
+
+	return unusedTokens, nil
+}
+
+func addSecretReference(references map[string]string, namespace, refNamespace, name, reason string) {
+	if name == "" || refNamespace != namespace {
+		return
+	}
+	if _, found := references[name]; !found {
+		references[name] = reason
+	}
+}
+
+// secretReferenceSources holds the cluster-wide objects that can reference Secrets of any namespace
+type secretReferenceSources struct {
+	storageClasses     []storagev1.StorageClass
+	persistentVolumes  []corev1.PersistentVolume
+	validatingWebhooks []admissionregistrationv1.ValidatingWebhookConfiguration
+	mutatingWebhooks   []admissionregistrationv1.MutatingWebhookConfiguration
+	gateways           []unstructured.Unstructured
+	apiServices        []unstructured.Unstructured
+}
+
+// retrieveSecretReferenceSources lists the cluster-wide objects that can reference Secrets.
+// Gateways and APIServices are skipped when the cluster doesn't serve them.
+func retrieveSecretReferenceSources(clientset kubernetes.Interface, dynamicClient dynamic.Interface) (*secretReferenceSources, error) {
+	sources := &secretReferenceSources{}
+
+	storageClasses, err := clientset.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sources.storageClasses = storageClasses.Items
+
+	pvs, err := clientset.CoreV1().PersistentVolumes().List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
+	sources.persistentVolumes = pvs.Items
 
-	var usedSecrets []string
-	slicesToAppend := [][]string{
-		envSecrets,
-		envSecrets2,
-		volumeSecrets,
-		pullSecrets,
-		tlsSecrets,
-		initContainerEnvSecrets,
+	validatingWebhooks, err := clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sources.validatingWebhooks = validatingWebhooks.Items
+
+	mutatingWebhooks, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sources.mutatingWebhooks = mutatingWebhooks.Items
+
+	if dynamicClient == nil {
+		return sources, nil
+	}
+
+	gateways, err := dynamicClient.Resource(gatewayGVR).Namespace(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		sources.gateways = gateways.Items
+	}
+
+	apiServices, err := dynamicClient.Resource(apiServiceGVR).List(context.TODO(), metav1.ListOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
 	}
+	if err == nil {
+		sources.apiServices = apiServices.Items
+	}
+	return sources, nil
+}
 
-	for _, slice := range slicesToAppend {
-		usedSecrets = append(usedSecrets, slice...)
+// addCSISecretReferences adds the Secrets named by the csi.storage.k8s.io/*-secret-name parameters of StorageClasses
+// and by the CSI secret references of PersistentVolumes.
+// A templated namespace such as ${pvc.namespace} matches every namespace, templated names can't be resolved.
+func addCSISecretReferences(sources *secretReferenceSources, namespace string, references map[string]string) {
+	for _, sc := range sources.storageClasses {
+		for _, key := range slices.Sorted(maps.Keys(sc.Parameters)) {
+			name := sc.Parameters[key]
+			prefix, found := strings.CutSuffix(key, "-secret-name")
+			if !found || strings.Contains(name, "${") {
+				continue
+			}
+			refNamespace := sc.Parameters[prefix+"-secret-namespace"]
+			if strings.Contains(refNamespace, "${") {
+				refNamespace = namespace
+			}
+			addSecretReference(references, namespace, refNamespace, name, fmt.Sprintf("Referenced by StorageClass %s parameter %s", sc.Name, key))
+		}
+	}
+
+	for _, pv := range sources.persistentVolumes {
+		csi := pv.Spec.CSI
+		if csi == nil {
+			continue
+		}
+		secretRefs := []struct {
+			field string
+			ref   *corev1.SecretReference
+		}{
+			{"controllerPublishSecretRef", csi.ControllerPublishSecretRef},
+			{"nodeStageSecretRef", csi.NodeStageSecretRef},
+			{"nodePublishSecretRef", csi.NodePublishSecretRef},
+			{"controllerExpandSecretRef", csi.ControllerExpandSecretRef},
+			{"nodeExpandSecretRef", csi.NodeExpandSecretRef},
+		}
+		for _, secretRef := range secretRefs {
+			if secretRef.ref != nil {
+				addSecretReference(references, namespace, secretRef.ref.Namespace, secretRef.ref.Name, fmt.Sprintf("Referenced by PersistentVolume %s %s", pv.Name, secretRef.field))
+			}
+		}
+	}
+}
+
+func addInjectedCAReference(references map[string]string, namespace string, object metav1.Object, kind string) {
+	value, found := object.GetAnnotations()[injectCAFromSecretAnnotation]
+	if !found {
+		return
+	}
+	if refNamespace, name, ok := strings.Cut(value, "/"); ok {
+		addSecretReference(references, namespace, refNamespace, name, fmt.Sprintf("CA injected into %s %s", kind, object.GetName()))
+	}
+}
+
+// addGatewaySecretReferences adds the Secrets used as certificates by Gateway listeners
+func addGatewaySecretReferences(sources *secretReferenceSources, namespace string, references map[string]string) {
+	for _, gateway := range sources.gateways {
+		listeners, _, _ := unstructured.NestedSlice(gateway.Object, "spec", "listeners")
+		for _, listener := range listeners {
+			listenerMap, ok := listener.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			certificateRefs, _, _ := unstructured.NestedSlice(listenerMap, "tls", "certificateRefs")
+			for _, certificateRef := range certificateRefs {
+				ref, ok := certificateRef.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				group, _, _ := unstructured.NestedString(ref, "group")
+				kind, _, _ := unstructured.NestedString(ref, "kind")
+				if group != "" || (kind != "" && kind != "Secret") {
+					continue
+				}
+				name, _, _ := unstructured.NestedString(ref, "name")
+				refNamespace, _, _ := unstructured.NestedString(ref, "namespace")
+				if refNamespace == "" {
+					refNamespace = gateway.GetNamespace()
+				}
+				addSecretReference(references, namespace, refNamespace, name, fmt.Sprintf("Certificate of Gateway %s/%s", gateway.GetNamespace(), gateway.GetName()))
+			}
+		}
+	}
+}
+
+// retrieveSecretReferences returns the Secrets of the namespace used outside of pod specs, mapped to the reason they are considered used
+func retrieveSecretReferences(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string) (map[string]string, error) {
+	references := make(map[string]string)
+
+	serviceAccounts, err := clientset.CoreV1().ServiceAccounts(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, sa := range serviceAccounts.Items {
+		for _, secret := range sa.Secrets {
+			addSecretReference(references, namespace, namespace, secret.Name, fmt.Sprintf("Referenced by ServiceAccount %s", sa.Name))
+		}
+		for _, secret := range sa.ImagePullSecrets {
+			addSecretReference(references, namespace, namespace, secret.Name, fmt.Sprintf("Used as imagePullSecret by ServiceAccount %s", sa.Name))
+		}
+	}
+
+	sources, err := cache.retrieveSecretReferenceSources(clientset, dynamicClient)
+	if err != nil {
+		return nil, err
+	}
+
+	addCSISecretReferences(sources, namespace, references)
+	for _, webhook := range sources.validatingWebhooks {
+		addInjectedCAReference(references, namespace, &webhook, "ValidatingWebhookConfiguration")
+	}
+	for _, webhook := range sources.mutatingWebhooks {
+		addInjectedCAReference(references, namespace, &webhook, "MutatingWebhookConfiguration")
+	}
+	addGatewaySecretReferences(sources, namespace, references)
+	for _, apiService := range sources.apiServices {
+		addInjectedCAReference(references, namespace, &apiService, "APIService")
+	}
+
+	return references, nil
+}
+
+// retrieveUnusedSecretKeys reports the unreferenced keys of the given Secrets that pods only consume key by key
+func retrieveUnusedSecretKeys(clientset kubernetes.Interface, namespace string, names []string, otherUses []string) ([]ResourceInfo, error) {
+	podSpecs, err := retrieveWorkloadPodSpecs(clientset, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets, err := clientset.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	objectKeys := make(map[string][]string)
+	for _, secret := range secrets.Items {
+		if slices.Contains(names, secret.Name) {
+			objectKeys[secret.Name] = slices.Sorted(maps.Keys(secret.Data))
+		}
+	}
+
+	return retrieveUnreferencedKeys(retrieveKeyUsage(podSpecs, "Secret"), objectKeys, otherUses), nil
+}
+
+// retrieveUsedSecrets returns the Secrets of the namespace that are used, mapped to the reason they are considered used,
+// along with those used outside of key by key consumption
+func retrieveUsedSecrets(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, opts common.Opts) (map[string]string, []string, error) {
+	envSecrets, envSecrets2, volumeSecrets, initContainerEnvSecrets, pullSecrets, tlsSecrets, err := retrieveUsedSecret(clientset, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ruleSecrets, err := retrieveRuleReferencedNames(dynamicClient, namespace, schema.GroupResource{Resource: "secrets"}, opts.ReferenceRules)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secretReferences, err := retrieveSecretReferences(clientset, dynamicClient, cache, namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	usedSecrets := maps.Clone(secretReferences)
+
+	usageReasons := []struct {
+		names  []string
+		reason string
+	}{
+		{envSecrets, "Used in container env"},
+		{envSecrets2, "Used in container envFrom"},
+		{volumeSecrets, "Mounted as a volume"},
+		{pullSecrets, "Used as imagePullSecret"},
+		{tlsSecrets, "Used by Ingress TLS"},
+		{initContainerEnvSecrets, "Used in init container env"},
+		{ruleSecrets, "Referenced by a custom resource reference rule"},
+	}
+	for _, usage := range usageReasons {
+		for _, name := range usage.names {
+			usedSecrets[name] = usage.reason
+		}
+	}
+
+	wholeUses := slices.Concat(pullSecrets, tlsSecrets, ruleSecrets, slices.Collect(maps.Keys(secretReferences)))
+	return usedSecrets, wholeUses, nil
+}
+
+func processNamespaceSecret(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+	secretNames, unusedSecretNames, err := retrieveSecretNames(clientset, namespace, filterOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	usedSecrets, wholeUses, err := retrieveUsedSecrets(clientset, dynamicClient, cache, namespace, opts)
+	if err != nil {
+		return nil, err
 	}
 
 	var diff []ResourceInfo
+	var keyCandidates []string
 
-	for _, name := range CalculateResourceDifference(usedSecrets, secretNames) {
+	for _, name := range secretNames {
+		if _, used := usedSecrets[name]; used {
+			keyCandidates = append(keyCandidates, name)
+			continue
+		}
 		reason := "Secret is not used in any pod, container, or ingress"
 		diff = append(diff, ResourceInfo{Name: name, Reason: reason})
 	}
@@ -202,19 +568,74 @@ func processNamespaceSecret(clientset kubernetes.Interface, namespace string, fi
 		diff = append(diff, ResourceInfo{Name: name, Reason: reason})
 	}
 
+	unusedTokens, err := retrieveUnusedServiceAccountTokens(clientset, cache, namespace, filterOpts)
+	if err != nil {
+		return nil, err
+	}
+	diff = append(diff, unusedTokens...)
+
 	if opts.DeleteFlag {
 		if diff, err = DeleteResource(diff, clientset, namespace, "Secret", opts.NoInteractive); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to delete Secret %s in namespace %s: %v\n", diff, namespace, err)
 		}
 	}
+
+	// Unused keys belong to Secrets still in use, so they are reported after the deletion step
+	if opts.UnusedKeys {
+		keyDiff, err := retrieveUnusedSecretKeys(clientset, namespace, keyCandidates, wholeUses)
+		if err != nil {
+			return nil, err
+		}
+		diff = append(diff, keyDiff...)
+	}
 	return diff, nil
 
 }
 
-func GetUnusedSecrets(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
+// formatUsedSecrets renders the used Secrets of a namespace with why they are used, apart from the unused resources
+func formatUsedSecrets(namespace string, secretNames []string, usedSecrets map[string]string) string {
+	var buf strings.Builder
+	table := tablewriter.NewWriter(&buf)
+	table.SetColWidth(60)
+	table.SetHeader([]string{"#", "SECRET NAME", "USED BECAUSE"})
+	var index int
+	for _, name := range secretNames {
+		if reason, used := usedSecrets[name]; used {
+			index++
+			table.Append([]string{fmt.Sprintf("%d", index), name, reason})
+		}
+	}
+	if index == 0 {
+		return ""
+	}
+	table.Render()
+	return fmt.Sprintf("Used secrets in namespace: %q\n%s\n", namespace, buf.String())
+}
+
+// retrieveUsedSecretsSection lists why each Secret is used, namespace by namespace, for --show-used
+func retrieveUsedSecretsSection(clientset kubernetes.Interface, dynamicClient dynamic.Interface, cache *clusterCache, filterOpts *filters.Options, opts common.Opts) string {
+	var section strings.Builder
+	for _, namespace := range filterOpts.Namespaces(clientset) {
+		secretNames, _, err := retrieveSecretNames(clientset, namespace, filterOpts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to process namespace %s: %v\n", namespace, err)
+			continue
+		}
+		usedSecrets, _, err := retrieveUsedSecrets(clientset, dynamicClient, cache, namespace, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to process namespace %s: %v\n", namespace, err)
+			continue
+		}
+		section.WriteString(formatUsedSecrets(namespace, secretNames, usedSecrets))
+	}
+	return section.String()
+}
+
+func GetUnusedSecrets(filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
 	resources := make(map[string]map[string][]ResourceInfo)
+	cache := newClusterCache()
 	for _, namespace := range filterOpts.Namespaces(clientset) {
-		diff, err := processNamespaceSecret(clientset, namespace, filterOpts, opts)
+		diff, err := processNamespaceSecret(clientset, dynamicClient, cache, namespace, filterOpts, opts)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to process namespace %s: %v\n", namespace, err)
 			continue
@@ -233,6 +654,9 @@ func GetUnusedSecrets(filterOpts *filters.Options, clientset kubernetes.Interfac
 	switch outputFormat {
 	case "table":
 		outputBuffer = FormatOutput(resources, opts)
+		if opts.ShowUsed {
+			outputBuffer.WriteString(retrieveUsedSecretsSection(clientset, dynamicClient, cache, filterOpts, opts))
+		}
 	case "json", "yaml":
 		var err error
 		if jsonResponse, err = json.MarshalIndent(resources, "", "  "); err != nil {
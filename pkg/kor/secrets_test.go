@@ -4,12 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 
@@ -244,6 +250,151 @@ func TestRetrieveUsedSecret(t *testing.T) {
 
 }
 
+func TestRetrieveSecretReferences(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	sa := CreateTestServiceAccount(testNamespace, "builder", AppLabels)
+	sa.Secrets = []corev1.ObjectReference{{Name: "sa-secret"}}
+	sa.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "registry-secret"}}
+	if _, err := clientset.CoreV1().ServiceAccounts(testNamespace).Create(context.TODO(), sa, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake serviceaccount: %v", err)
+	}
+
+	sc := &storagev1.StorageClass{
+		ObjectMeta: v1.ObjectMeta{Name: "csi-sc"},
+		Parameters: map[string]string{
+			"csi.storage.k8s.io/provisioner-secret-name":      "provisioner-secret",
+			"csi.storage.k8s.io/provisioner-secret-namespace": testNamespace,
+			"csi.storage.k8s.io/node-stage-secret-name":       "node-stage-secret",
+			"csi.storage.k8s.io/node-stage-secret-namespace":  "${pvc.namespace}",
+			"csi.storage.k8s.io/node-expand-secret-name":      "other-namespace-secret",
+			"csi.storage.k8s.io/node-expand-secret-namespace": "other-namespace",
+		},
+	}
+	if _, err := clientset.StorageV1().StorageClasses().Create(context.TODO(), sc, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake storageclass: %v", err)
+	}
+
+	pv := CreateTestPv("csi-pv", "Bound", AppLabels, "csi-sc")
+	pv.Spec.CSI = &corev1.CSIPersistentVolumeSource{
+		Driver:               "csi.example.com",
+		NodePublishSecretRef: &corev1.SecretReference{Namespace: testNamespace, Name: "node-publish-secret"},
+	}
+	if _, err := clientset.CoreV1().PersistentVolumes().Create(context.TODO(), pv, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake pv: %v", err)
+	}
+
+	webhook := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        "policy-webhook",
+			Annotations: map[string]string{injectCAFromSecretAnnotation: testNamespace + "/webhook-ca"},
+		},
+	}
+	if _, err := clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().Create(context.TODO(), webhook, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake webhook configuration: %v", err)
+	}
+
+	gateway := CreateTestUnstructered("Gateway", "gateway.networking.k8s.io/v1", "gateway-namespace", "public")
+	gateway.Object["spec"] = map[string]interface{}{
+		"listeners": []interface{}{
+			map[string]interface{}{
+				"name": "https",
+				"tls": map[string]interface{}{
+					"certificateRefs": []interface{}{
+						map[string]interface{}{"name": "gateway-cert", "namespace": testNamespace},
+					},
+				},
+			},
+		},
+	}
+	apiService := CreateTestUnstructered("APIService", "apiregistration.k8s.io/v1", "", "v1beta1.metrics.example.com")
+	apiService.SetAnnotations(map[string]string{injectCAFromSecretAnnotation: testNamespace + "/apiservice-ca"})
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		gatewayGVR:    "GatewayList",
+		apiServiceGVR: "APIServiceList",
+	}, apiService)
+	// Gateways are created explicitly as the fake client would guess "gatewaies" from the kind
+	if _, err := dynamicClient.Resource(gatewayGVR).Namespace("gateway-namespace").Create(context.TODO(), gateway, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake gateway: %v", err)
+	}
+
+	references, err := retrieveSecretReferences(clientset, dynamicClient, newClusterCache(), testNamespace)
+	if err != nil {
+		t.Fatalf("Error retrieving secret references: %v", err)
+	}
+
+	expected := map[string]string{
+		"sa-secret":           "Referenced by ServiceAccount builder",
+		"registry-secret":     "Used as imagePullSecret by ServiceAccount builder",
+		"provisioner-secret":  "Referenced by StorageClass csi-sc parameter csi.storage.k8s.io/provisioner-secret-name",
+		"node-stage-secret":   "Referenced by StorageClass csi-sc parameter csi.storage.k8s.io/node-stage-secret-name",
+		"node-publish-secret": "Referenced by PersistentVolume csi-pv nodePublishSecretRef",
+		"webhook-ca":          "CA injected into ValidatingWebhookConfiguration policy-webhook",
+		"gateway-cert":        "Certificate of Gateway gateway-namespace/public",
+		"apiservice-ca":       "CA injected into APIService v1beta1.metrics.example.com",
+	}
+	if !reflect.DeepEqual(references, expected) {
+		t.Errorf("Expected references %v, got %v", expected, references)
+	}
+}
+
+func TestProcessNamespaceSecretUnusedKeys(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	keyed := CreateTestSecret(testNamespace, "db-credentials", AppLabels)
+	keyed.Data = map[string][]byte{"username": []byte("app"), "password": []byte("secret"), "old-password": []byte("old")}
+	pulled := CreateTestSecret(testNamespace, "registry", AppLabels)
+	pulled.Data = map[string][]byte{"token": []byte("abc"), "unused": []byte("x")}
+	for _, secret := range []*corev1.Secret{keyed, pulled} {
+		if _, err := clientset.CoreV1().Secrets(testNamespace).Create(context.TODO(), secret, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake secret: %v", err)
+		}
+	}
+
+	pod := CreateTestPod(testNamespace, "pod-1", "", []corev1.Volume{
+		{
+			Name: "credentials",
+			VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+				{Secret: &corev1.SecretProjection{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "db-credentials"},
+					Items:                []corev1.KeyToPath{{Key: "password", Path: "password"}},
+				}},
+			}}},
+		},
+	}, AppLabels)
+	pod.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "registry"}}
+	pod.Spec.Containers = []corev1.Container{
+		{
+			Env: []corev1.EnvVar{
+				{
+					Name:      "DB_USER",
+					ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db-credentials"}, Key: "username"}},
+				},
+				{
+					Name:      "REGISTRY_TOKEN",
+					ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "registry"}, Key: "token"}},
+				},
+			},
+		},
+	}
+	if _, err := clientset.CoreV1().Pods(testNamespace).Create(context.TODO(), pod, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake pod: %v", err)
+	}
+
+	diff, err := processNamespaceSecret(clientset, nil, newClusterCache(), testNamespace, &filters.Options{}, common.Opts{UnusedKeys: true})
+	if err != nil {
+		t.Fatalf("Error processing namespace secrets: %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "db-credentials", Key: "old-password", Reason: "Key is not referenced by any consumer", ReportOnly: true},
+	}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("Expected diff %v, got %v", expected, diff)
+	}
+}
+
 func TestRetrieveSecretNames(t *testing.T) {
 	clientset := fake.NewClientset()
 
@@ -279,7 +430,7 @@ func TestRetrieveSecretNames(t *testing.T) {
 func TestProcessNamespaceSecret(t *testing.T) {
 	clientset := createTestSecrets(t)
 
-	unusedSecrets, err := processNamespaceSecret(clientset, testNamespace, &filters.Options{}, common.Opts{})
+	unusedSecrets, err := processNamespaceSecret(clientset, nil, newClusterCache(), testNamespace, &filters.Options{}, common.Opts{})
 	if err != nil {
 		t.Fatalf("Error retrieving unused secrets: %v", err)
 	}
@@ -291,7 +442,79 @@ func TestProcessNamespaceSecret(t *testing.T) {
 	if !resourceInfoContains(unusedSecrets, "test-secret3") {
 		t.Error("Expected specific Secret in the list")
 	}
+}
+
+func TestRetrieveUnusedServiceAccountTokens(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	sa := CreateTestServiceAccount(testNamespace, "existing-sa", AppLabels)
+	if _, err := clientset.CoreV1().ServiceAccounts(testNamespace).Create(context.TODO(), sa, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake serviceaccount: %v", err)
+	}
+
+	newToken := func(name, serviceAccount string, labels map[string]string) *corev1.Secret {
+		secret := CreateTestSecret(testNamespace, name, labels)
+		secret.Type = corev1.SecretTypeServiceAccountToken
+		secret.Annotations = map[string]string{corev1.ServiceAccountNameKey: serviceAccount}
+		return secret
+	}
+
+	recentToken := newToken("recent-token", "existing-sa", AppLabels)
+	recentToken.CreationTimestamp = v1.NewTime(time.Now().Add(-time.Hour))
+
+	secrets := []*corev1.Secret{
+		newToken("orphaned-token", "deleted-sa", map[string]string{legacyTokenLastUsedLabel: "2024-01-01"}),
+		newToken("never-used-token", "existing-sa", AppLabels),
+		newToken("used-token", "existing-sa", map[string]string{legacyTokenLastUsedLabel: "2024-01-01"}),
+		recentToken,
+		CreateTestSecret(testNamespace, "opaque-secret", AppLabels),
+	}
+	for _, secret := range secrets {
+		if _, err := clientset.CoreV1().Secrets(testNamespace).Create(context.TODO(), secret, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake secret: %v", err)
+		}
+	}
 
+	orphaned := ResourceInfo{Name: "orphaned-token", Reason: "ServiceAccount token for non-existing ServiceAccount deleted-sa"}
+
+	// Without tracking, or with tracking that started recently, no token can be judged never used
+	trackingSince := map[string]time.Time{"untracked": {}, "recently tracked": time.Now().UTC().AddDate(0, 0, -10)}
+	for name, since := range trackingSince {
+		if !since.IsZero() {
+			tracking := CreateTestConfigmap(v1.NamespaceSystem, legacyTokenTrackingConfigMap, AppLabels)
+			tracking.Data = map[string]string{"since": since.Format(time.DateOnly)}
+			if _, err := clientset.CoreV1().ConfigMaps(v1.NamespaceSystem).Create(context.TODO(), tracking, v1.CreateOptions{}); err != nil {
+				t.Fatalf("Error creating fake configmap: %v", err)
+			}
+		}
+
+		unusedTokens, err := retrieveUnusedServiceAccountTokens(clientset, newClusterCache(), testNamespace, &filters.Options{})
+		if err != nil {
+			t.Fatalf("Error retrieving unused service account tokens: %v", err)
+		}
+		if !reflect.DeepEqual(unusedTokens, []ResourceInfo{orphaned}) {
+			t.Errorf("Expected only %v when %s, got %v", orphaned, name, unusedTokens)
+		}
+	}
+
+	tracking := CreateTestConfigmap(v1.NamespaceSystem, legacyTokenTrackingConfigMap, AppLabels)
+	tracking.Data = map[string]string{"since": time.Now().UTC().AddDate(0, 0, -100).Format(time.DateOnly)}
+	if _, err := clientset.CoreV1().ConfigMaps(v1.NamespaceSystem).Update(context.TODO(), tracking, v1.UpdateOptions{}); err != nil {
+		t.Fatalf("Error updating fake configmap: %v", err)
+	}
+
+	unusedTokens, err := retrieveUnusedServiceAccountTokens(clientset, newClusterCache(), testNamespace, &filters.Options{})
+	if err != nil {
+		t.Fatalf("Error retrieving unused service account tokens: %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "never-used-token", Reason: "Legacy ServiceAccount token has not been used in 100 days", ReportOnly: true},
+		orphaned,
+	}
+	if !reflect.DeepEqual(unusedTokens, expected) {
+		t.Errorf("Expected %v, got %v", expected, unusedTokens)
+	}
 }
 
 func TestGetUnusedSecretsStructured(t *testing.T) {
@@ -306,7 +529,7 @@ func TestGetUnusedSecretsStructured(t *testing.T) {
 		GroupBy:       "namespace",
 	}
 
-	output, err := GetUnusedSecrets(&filters.Options{}, clientset, "json", opts)
+	output, err := GetUnusedSecrets(&filters.Options{}, clientset, nil, "json", opts)
 	if err != nil {
 		t.Fatalf("Error calling GetUnusedSecretsStructured: %v", err)
 	}
@@ -330,6 +553,37 @@ func TestGetUnusedSecretsStructured(t *testing.T) {
 	}
 }
 
+func TestGetUnusedSecretsShowUsed(t *testing.T) {
+	clientset := createTestSecrets(t)
+
+	opts := common.Opts{GroupBy: "namespace", ShowUsed: true}
+
+	// Used Secrets get a section of their own and stay out of the unused resources
+	output, err := GetUnusedSecrets(&filters.Options{}, clientset, nil, "table", opts)
+	if err != nil {
+		t.Fatalf("Error calling GetUnusedSecrets: %v", err)
+	}
+
+	unused, used, found := strings.Cut(output, "Used secrets in namespace")
+	if !found {
+		t.Fatalf("Expected a used secrets section, got:\n%s", output)
+	}
+	if strings.Contains(unused, "test-secret1") || !strings.Contains(used, "test-secret1") {
+		t.Errorf("Expected test-secret1 only in the used secrets section, got:\n%s", output)
+	}
+	if !strings.Contains(used, "Used in init container env") {
+		t.Errorf("Expected the reason test-secret1 is used, got:\n%s", used)
+	}
+
+	output, err = GetUnusedSecrets(&filters.Options{}, clientset, nil, "json", opts)
+	if err != nil {
+		t.Fatalf("Error calling GetUnusedSecrets: %v", err)
+	}
+	if strings.Contains(output, "test-secret1") {
+		t.Errorf("Expected used Secrets to stay out of the structured output, got:\n%s", output)
+	}
+}
+
 func TestFilterOwnerReferencedSecrets(t *testing.T) {
 	clientset := fake.NewClientset()
 
@@ -367,7 +621,7 @@ func TestFilterOwnerReferencedSecrets(t *testing.T) {
 
 	// Test without filter - should return both
 	filterOptsNoSkip := &filters.Options{IgnoreOwnerReferences: false}
-	unusedWithoutFilter, err := processNamespaceSecret(clientset, testNamespace, filterOptsNoSkip, common.Opts{})
+	unusedWithoutFilter, err := processNamespaceSecret(clientset, nil, newClusterCache(), testNamespace, filterOptsNoSkip, common.Opts{})
 	if err != nil {
 		t.Fatalf("Error retrieving unused secrets: %v", err)
 	}
@@ -378,7 +632,7 @@ func TestFilterOwnerReferencedSecrets(t *testing.T) {
 
 	// Test with filter - should return only standalone
 	filterOptsWithSkip := &filters.Options{IgnoreOwnerReferences: true}
-	unusedWithFilter, err := processNamespaceSecret(clientset, testNamespace, filterOptsWithSkip, common.Opts{})
+	unusedWithFilter, err := processNamespaceSecret(clientset, nil, newClusterCache(), testNamespace, filterOptsWithSkip, common.Opts{})
 	if err != nil {
 		t.Fatalf("Error retrieving unused secrets: %v", err)
 	}
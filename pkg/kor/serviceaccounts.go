@@ -2,12 +2,17 @@ package kor
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	_ "embed"
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
@@ -18,18 +23,18 @@ import (
 //go:embed exceptions/serviceaccounts/serviceaccounts.json
 var serviceAccountsConfig []byte
 
-func getServiceAccountsFromClusterRoleBindings(clientset kubernetes.Interface, namespace string) ([]string, error) {
-	// Get a list of all role bindings in the specified namespace
-	roleBindings, err := clientset.RbacV1().ClusterRoleBindings().List(context.TODO(), metav1.ListOptions{})
+func getServiceAccountsFromClusterRoleBindings(clientset kubernetes.Interface, cache *clusterCache) ([]string, error) {
+	// Get a list of all cluster role bindings
+	clusterRoleBindings, err := cache.retrieveClusterRoleBindings(clientset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list role bindings in namespace %s: %v", namespace, err)
+		return nil, fmt.Errorf("failed to list cluster role bindings: %v", err)
 	}
 
 	// Create a slice to store service account names
 	var serviceAccounts []string
 
 	// Extract service account names from the role bindings
-	for _, rb := range roleBindings.Items {
+	for _, rb := range clusterRoleBindings {
 		if pass := filters.KorLabelFilter(&rb, &filters.Options{}); pass {
 			continue
 		}
@@ -71,19 +76,15 @@ func getServiceAccountsFromRoleBindings(clientset kubernetes.Interface, namespac
 	return serviceAccounts, nil
 }
 
-func retrieveUsedSA(clientset kubernetes.Interface, namespace string) ([]string, []string, []string, error) {
+// retrieveUsedSA returns the ServiceAccounts run by the given pod specs, and those bound by RoleBindings and ClusterRoleBindings
+func retrieveUsedSA(clientset kubernetes.Interface, cache *clusterCache, namespace string, podSpecs []workloadPodSpec) ([]string, []string, []string, error) {
 
 	var podServiceAccounts []string
 
-	pods, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
-	if err != nil {
-		return nil, nil, nil, err
-	}
-
 	// Extract service account names from pods
-	for _, pod := range pods.Items {
-		if pod.Spec.ServiceAccountName != "" {
-			podServiceAccounts = append(podServiceAccounts, pod.Spec.ServiceAccountName)
+	for _, podSpec := range podSpecs {
+		if podSpec.Spec.ServiceAccountName != "" {
+			podServiceAccounts = append(podServiceAccounts, podSpec.Spec.ServiceAccountName)
 		}
 	}
 
@@ -91,19 +92,154 @@ func retrieveUsedSA(clientset kubernetes.Interface, namespace string) ([]string,
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	clusterRoleServiceAccounts, err := getServiceAccountsFromClusterRoleBindings(clientset, namespace)
+	clusterRoleServiceAccounts, err := getServiceAccountsFromClusterRoleBindings(clientset, cache)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 	return podServiceAccounts, roleServiceAccounts, clusterRoleServiceAccounts, nil
 }
 
-func retrieveServiceAccountNames(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options) ([]string, []string, error) {
+// powerfulVerbs let a subject grant permissions or act as another identity
+var powerfulVerbs = []string{"*", "escalate", "bind", "impersonate"}
+
+// writeVerbs modify the resources a rule applies to
+var writeVerbs = []string{"create", "update", "patch", "delete", "deletecollection"}
+
+// isPowerfulRule reports whether a rule grants writing any resource, reading Secrets, exec into pods or privilege escalation
+func isPowerfulRule(rule rbacv1.PolicyRule) bool {
+	hasVerb := func(verbs ...string) bool {
+		return slices.ContainsFunc(rule.Verbs, func(verb string) bool { return slices.Contains(verbs, verb) })
+	}
+	switch {
+	case hasVerb(powerfulVerbs...):
+		return true
+	case slices.Contains(rule.Resources, "*") && hasVerb(writeVerbs...):
+		return true
+	case slices.Contains(rule.Resources, "secrets") && hasVerb("get", "list", "watch"):
+		return true
+	case slices.Contains(rule.Resources, "pods/exec") && hasVerb("create"):
+		return true
+	}
+	return false
+}
+
+// retrievePowerfulServiceAccountRoles returns the ServiceAccounts of a namespace bound to a powerful Role or ClusterRole,
+// together with the role granting it
+func retrievePowerfulServiceAccountRoles(clientset kubernetes.Interface, cache *clusterCache, namespace string) (map[string]string, error) {
+	clusterRoles, err := cache.retrieveClusterRoles(clientset)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := clientset.RbacV1().Roles(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	powerfulRoles := make(map[rbacv1.RoleRef]bool)
+	for _, clusterRole := range clusterRoles {
+		// Bootstrap roles are bound to control plane components, which use their ServiceAccounts without pods
+		if strings.HasPrefix(clusterRole.Name, "system:") {
+			continue
+		}
+		if slices.ContainsFunc(clusterRole.Rules, isPowerfulRule) {
+			powerfulRoles[rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: clusterRole.Name}] = true
+		}
+	}
+	for _, role := range roles.Items {
+		if strings.HasPrefix(role.Name, "system:") {
+			continue
+		}
+		if slices.ContainsFunc(role.Rules, isPowerfulRule) {
+			powerfulRoles[rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: role.Name}] = true
+		}
+	}
+
+	serviceAccountRoles := make(map[string]string)
+	addSubjects := func(subjects []rbacv1.Subject, roleRef rbacv1.RoleRef, defaultNamespace string) {
+		roleRef.APIGroup = rbacv1.GroupName
+		if !powerfulRoles[roleRef] {
+			return
+		}
+		for _, subject := range subjects {
+			subjectNamespace := cmp.Or(subject.Namespace, defaultNamespace)
+			if subject.Kind == "ServiceAccount" && subjectNamespace == namespace {
+				serviceAccountRoles[subject.Name] = roleRef.Kind + " " + roleRef.Name
+			}
+		}
+	}
+
+	roleBindings, err := clientset.RbacV1().RoleBindings(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, rb := range roleBindings.Items {
+		addSubjects(rb.Subjects, rb.RoleRef, rb.Namespace)
+	}
+
+	clusterRoleBindings, err := cache.retrieveClusterRoleBindings(clientset)
+	if err != nil {
+		return nil, err
+	}
+	for _, crb := range clusterRoleBindings {
+		addSubjects(crb.Subjects, crb.RoleRef, "")
+	}
+
+	return serviceAccountRoles, nil
+}
+
+// isDefaultTokenRequested reports whether a workload running as the default ServiceAccount
+// asks for its token in the pod spec, which it only does to call the API
+func isDefaultTokenRequested(podSpecs []workloadPodSpec) bool {
+	return slices.ContainsFunc(podSpecs, func(podSpec workloadPodSpec) bool {
+		automount := podSpec.Spec.AutomountServiceAccountToken
+		return cmp.Or(podSpec.Spec.ServiceAccountName, "default") == "default" && automount != nil && *automount
+	})
+}
+
+// retrieveServiceAccountRisks reports ServiceAccounts that hold powerful roles without any workload using them,
+// and default ServiceAccounts that automount a token neither a RoleBinding nor a workload gives a purpose to
+func retrieveServiceAccountRisks(clientset kubernetes.Interface, cache *clusterCache, namespace string, serviceAccounts []corev1.ServiceAccount, podSpecs []workloadPodSpec, workloadServiceAccounts, boundServiceAccounts []string, config *Config) ([]ResourceInfo, error) {
+	powerfulRoles, err := retrievePowerfulServiceAccountRoles(clientset, cache, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var risks []ResourceInfo
+	for _, sa := range serviceAccounts {
+		// The default ServiceAccount is in the exceptions, so its token is checked before them
+		if sa.Name == "default" {
+			automount := sa.AutomountServiceAccountToken == nil || *sa.AutomountServiceAccountToken
+			if automount && !slices.Contains(boundServiceAccounts, sa.Name) && !isDefaultTokenRequested(podSpecs) {
+				reason := "Default ServiceAccount automounts its token but no RoleBinding or workload needs API access"
+				risks = append(risks, ResourceInfo{Name: sa.Name, Reason: reason, ReportOnly: true})
+				continue
+			}
+		}
+
+		exceptionFound, err := isResourceException(sa.Name, namespace, config.ExceptionServiceAccounts)
+		if err != nil {
+			return nil, err
+		}
+
+		if exceptionFound {
+			continue
+		}
+
+		if role, ok := powerfulRoles[sa.Name]; ok && !slices.Contains(workloadServiceAccounts, sa.Name) {
+			reason := fmt.Sprintf("ServiceAccount is bound to powerful %s but used by no workload", role)
+			risks = append(risks, ResourceInfo{Name: sa.Name, Reason: reason, ReportOnly: true})
+		}
+	}
+	return risks, nil
+}
+
+// retrieveServiceAccounts returns the ServiceAccounts passing the filters, and the names of those marked as unused
+func retrieveServiceAccounts(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options) ([]corev1.ServiceAccount, []string, error) {
 	serviceaccounts, err := clientset.CoreV1().ServiceAccounts(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
 	if err != nil {
 		return nil, nil, err
 	}
-	names := make([]string, 0, len(serviceaccounts.Items))
+	var filteredServiceAccounts []corev1.ServiceAccount
 	var unusedServiceAccountNames []string
 
 	for _, serviceaccount := range serviceaccounts.Items {
@@ -121,13 +257,30 @@ func retrieveServiceAccountNames(clientset kubernetes.Interface, namespace strin
 			continue
 		}
 
-		names = append(names, serviceaccount.Name)
+		filteredServiceAccounts = append(filteredServiceAccounts, serviceaccount)
+	}
+	return filteredServiceAccounts, unusedServiceAccountNames, nil
+}
+
+func retrieveServiceAccountNames(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options) ([]string, []string, error) {
+	serviceAccounts, unusedServiceAccountNames, err := retrieveServiceAccounts(clientset, namespace, filterOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make([]string, 0, len(serviceAccounts))
+	for _, sa := range serviceAccounts {
+		names = append(names, sa.Name)
 	}
 	return names, unusedServiceAccountNames, nil
 }
 
-func processNamespaceSA(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
-	usedServiceAccounts, roleServiceAccounts, clusterRoleServiceAccounts, err := retrieveUsedSA(clientset, namespace)
+func processNamespaceSA(clientset kubernetes.Interface, cache *clusterCache, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+	// Pod templates count too, so CronJobs between runs and scaled-down workloads keep their ServiceAccount
+	podSpecs, err := retrieveWorkloadPodSpecs(clientset, namespace)
+	if err != nil {
+		return nil, err
+	}
+	usedServiceAccounts, roleServiceAccounts, clusterRoleServiceAccounts, err := retrieveUsedSA(clientset, cache, namespace, podSpecs)
 	if err != nil {
 		return nil, err
 	}
@@ -140,12 +293,18 @@ func processNamespaceSA(clientset kubernetes.Interface, namespace string, filter
 	roleServiceAccounts = RemoveDuplicatesAndSort(roleServiceAccounts)
 	clusterRoleServiceAccounts = RemoveDuplicatesAndSort(clusterRoleServiceAccounts)
 
-	usedServiceAccounts = append(append(usedServiceAccounts, roleServiceAccounts...), clusterRoleServiceAccounts...)
+	workloadServiceAccounts := usedServiceAccounts
+	boundServiceAccounts := slices.Concat(roleServiceAccounts, clusterRoleServiceAccounts)
+	usedServiceAccounts = slices.Concat(usedServiceAccounts, boundServiceAccounts)
 
-	serviceAccountNames, unusedServiceAccountNames, err := retrieveServiceAccountNames(clientset, namespace, filterOpts)
+	serviceAccounts, unusedServiceAccountNames, err := retrieveServiceAccounts(clientset, namespace, filterOpts)
 	if err != nil {
 		return nil, err
 	}
+	serviceAccountNames := make([]string, 0, len(serviceAccounts))
+	for _, sa := range serviceAccounts {
+		serviceAccountNames = append(serviceAccountNames, sa.Name)
+	}
 
 	var unusedServiceAccounts []ResourceInfo
 
@@ -171,13 +330,19 @@ func processNamespaceSA(clientset kubernetes.Interface, namespace string, filter
 			fmt.Fprintf(os.Stderr, "Failed to delete Serviceaccount %s in namespace %s: %v\n", unusedServiceAccounts, namespace, err)
 		}
 	}
-	return unusedServiceAccounts, nil
+
+	risks, err := retrieveServiceAccountRisks(clientset, cache, namespace, serviceAccounts, podSpecs, workloadServiceAccounts, boundServiceAccounts, config)
+	if err != nil {
+		return nil, err
+	}
+	return append(unusedServiceAccounts, risks...), nil
 }
 
 func GetUnusedServiceAccounts(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
 	resources := make(map[string]map[string][]ResourceInfo)
+	cache := newClusterCache()
 	for _, namespace := range filterOpts.Namespaces(clientset) {
-		diff, err := processNamespaceSA(clientset, namespace, filterOpts, opts)
+		diff, err := processNamespaceSA(clientset, cache, namespace, filterOpts, opts)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to process namespace %s: %v\n", namespace, err)
 			continue
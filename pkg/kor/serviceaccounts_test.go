@@ -7,7 +7,9 @@ import (
 	"testing"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
@@ -64,7 +66,7 @@ func TestGetServiceAccountsFromClusterRoleBindings(t *testing.T) {
 		t.Fatalf("Error creating fake %s: %v", "clusterRoleBinding", err)
 	}
 
-	serviceAccountWithCRB, err := getServiceAccountsFromClusterRoleBindings(clientset, testNamespace)
+	serviceAccountWithCRB, err := getServiceAccountsFromClusterRoleBindings(clientset, newClusterCache())
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -115,7 +117,11 @@ func TestRetrieveUsedSA(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Error creating fake %s: %v", "Pod", err)
 	}
-	serviceAccountUsedByPod, _, _, err := retrieveUsedSA(clientset, testNamespace)
+	podSpecs, err := retrieveWorkloadPodSpecs(clientset, testNamespace)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	serviceAccountUsedByPod, _, _, err := retrieveUsedSA(clientset, newClusterCache(), testNamespace, podSpecs)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -162,7 +168,7 @@ func TestProcessNamespaceSA(t *testing.T) {
 		t.Fatalf("Error creating fake %s: %v", "Pod", err)
 	}
 
-	unusedServiceAccounts, err := processNamespaceSA(clientset, testNamespace, &filters.Options{}, common.Opts{})
+	unusedServiceAccounts, err := processNamespaceSA(clientset, newClusterCache(), testNamespace, &filters.Options{}, common.Opts{})
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -258,7 +264,7 @@ func TestProcessNamespaceSAWithOwnerReferences(t *testing.T) {
 	clientset := createTestServiceAccountsWithOwnerReferences(t)
 
 	// Test with --ignore-owner-references=false (default behavior)
-	unusedServiceAccounts, err := processNamespaceSA(clientset, testNamespace, &filters.Options{}, common.Opts{})
+	unusedServiceAccounts, err := processNamespaceSA(clientset, newClusterCache(), testNamespace, &filters.Options{}, common.Opts{})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -269,7 +275,7 @@ func TestProcessNamespaceSAWithOwnerReferences(t *testing.T) {
 	}
 
 	// Test with --ignore-owner-references=true
-	unusedServiceAccounts, err = processNamespaceSA(clientset, testNamespace, &filters.Options{IgnoreOwnerReferences: true}, common.Opts{})
+	unusedServiceAccounts, err = processNamespaceSA(clientset, newClusterCache(), testNamespace, &filters.Options{IgnoreOwnerReferences: true}, common.Opts{})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -284,6 +290,125 @@ func TestProcessNamespaceSAWithOwnerReferences(t *testing.T) {
 	}
 }
 
+func TestProcessNamespaceSATemplatesAndRisks(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	for _, name := range []string{"default", "cron-sa", "ci-deployer", "reader"} {
+		sa := CreateTestServiceAccount(testNamespace, name, AppLabels)
+		if _, err := clientset.CoreV1().ServiceAccounts(testNamespace).Create(context.TODO(), sa, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake ServiceAccount: %v", err)
+		}
+	}
+
+	cronJob := &batchv1.CronJob{ObjectMeta: v1.ObjectMeta{Namespace: testNamespace, Name: "nightly"}}
+	cronJob.Spec.JobTemplate.Spec.Template.Spec.ServiceAccountName = "cron-sa"
+	if _, err := clientset.BatchV1().CronJobs(testNamespace).Create(context.TODO(), cronJob, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake CronJob: %v", err)
+	}
+
+	adminRole := &rbacv1.ClusterRole{
+		ObjectMeta: v1.ObjectMeta{Name: "super-admin"},
+		Rules:      []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}},
+	}
+	if _, err := clientset.RbacV1().ClusterRoles().Create(context.TODO(), adminRole, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake ClusterRole: %v", err)
+	}
+	if _, err := clientset.RbacV1().Roles(testNamespace).Create(context.TODO(), CreateTestRole(testNamespace, "pod-reader", AppLabels), v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake Role: %v", err)
+	}
+
+	clusterRoleBinding := CreateTestClusterRoleBindingRoleRef(testNamespace, "ci-deployer", "ci-deployer", CreateTestRoleRefForClusterRole("super-admin"))
+	if _, err := clientset.RbacV1().ClusterRoleBindings().Create(context.TODO(), clusterRoleBinding, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake ClusterRoleBinding: %v", err)
+	}
+	roleBinding := CreateTestRoleBinding(testNamespace, "reader", "reader", CreateTestRoleRef("pod-reader"))
+	if _, err := clientset.RbacV1().RoleBindings(testNamespace).Create(context.TODO(), roleBinding, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake RoleBinding: %v", err)
+	}
+
+	serviceAccounts, err := processNamespaceSA(clientset, newClusterCache(), testNamespace, &filters.Options{}, common.Opts{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The default ServiceAccount is listed in the exceptions, which don't cover its token
+	expected := []ResourceInfo{
+		{Name: "ci-deployer", Reason: "ServiceAccount is bound to powerful ClusterRole super-admin but used by no workload", ReportOnly: true},
+		{Name: "default", Reason: "Default ServiceAccount automounts its token but no RoleBinding or workload needs API access", ReportOnly: true},
+	}
+	if !reflect.DeepEqual(expected, serviceAccounts) {
+		t.Errorf("Expected %v, got %v", expected, serviceAccounts)
+	}
+}
+
+func TestRetrieveServiceAccountRisks(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	viewRole := &rbacv1.ClusterRole{
+		ObjectMeta: v1.ObjectMeta{Name: "view-everything"},
+		Rules:      []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"get", "list", "watch"}}},
+	}
+	if _, err := clientset.RbacV1().ClusterRoles().Create(context.TODO(), viewRole, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake ClusterRole: %v", err)
+	}
+	systemRole := &rbacv1.Role{
+		ObjectMeta: v1.ObjectMeta{Namespace: testNamespace, Name: "system:controller:bootstrap-signer"},
+		Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get", "list", "watch"}}},
+	}
+	if _, err := clientset.RbacV1().Roles(testNamespace).Create(context.TODO(), systemRole, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake Role: %v", err)
+	}
+
+	clusterRoleBinding := CreateTestClusterRoleBindingRoleRef(testNamespace, "viewer", "viewer", CreateTestRoleRefForClusterRole("view-everything"))
+	if _, err := clientset.RbacV1().ClusterRoleBindings().Create(context.TODO(), clusterRoleBinding, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake ClusterRoleBinding: %v", err)
+	}
+	roleBinding := CreateTestRoleBinding(testNamespace, "bootstrap-signer", "bootstrap-signer", CreateTestRoleRef("system:controller:bootstrap-signer"))
+	if _, err := clientset.RbacV1().RoleBindings(testNamespace).Create(context.TODO(), roleBinding, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake RoleBinding: %v", err)
+	}
+
+	automount := false
+	serviceAccounts := []corev1.ServiceAccount{
+		*CreateTestServiceAccount(testNamespace, "default", AppLabels),
+		*CreateTestServiceAccount(testNamespace, "viewer", AppLabels),
+		*CreateTestServiceAccount(testNamespace, "bootstrap-signer", AppLabels),
+	}
+	otherNamespaceDefault := CreateTestServiceAccount("other-namespace", "default", AppLabels)
+	otherNamespaceDefault.AutomountServiceAccountToken = &automount
+
+	risks, err := retrieveServiceAccountRisks(clientset, newClusterCache(), testNamespace, serviceAccounts, nil, nil, []string{"viewer", "bootstrap-signer"}, &Config{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "default", Reason: "Default ServiceAccount automounts its token but no RoleBinding or workload needs API access", ReportOnly: true},
+	}
+	if !reflect.DeepEqual(expected, risks) {
+		t.Errorf("Expected %v, got %v", expected, risks)
+	}
+
+	risks, err = retrieveServiceAccountRisks(clientset, newClusterCache(), "other-namespace", []corev1.ServiceAccount{*otherNamespaceDefault}, nil, nil, nil, &Config{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(risks) != 0 {
+		t.Errorf("Expected no risks for a default ServiceAccount without token automount, got %v", risks)
+	}
+
+	// A workload asking for the default token in its pod spec needs API access
+	automountPod := true
+	podSpecs := []workloadPodSpec{{Kind: "Deployment", Namespace: testNamespace, Name: "api-client", Spec: corev1.PodSpec{AutomountServiceAccountToken: &automountPod}}}
+	risks, err = retrieveServiceAccountRisks(clientset, newClusterCache(), testNamespace, serviceAccounts[:1], podSpecs, nil, nil, &Config{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(risks) != 0 {
+		t.Errorf("Expected no risks for a default ServiceAccount whose token a workload requests, got %v", risks)
+	}
+}
+
 func init() {
 	scheme.Scheme = runtime.NewScheme()
 	_ = appsv1.AddToScheme(scheme.Scheme)
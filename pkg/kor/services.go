@@ -7,8 +7,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/yonahd/kor/pkg/common"
@@ -18,7 +21,7 @@ import (
 //go:embed exceptions/services/services.json
 var servicesConfig []byte
 
-func processNamespaceServices(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
+func processNamespaceServices(clientset kubernetes.Interface, dynamicClient dynamic.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
 	endpointSlices, err := clientset.DiscoveryV1().EndpointSlices(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
 	if err != nil {
 		return nil, err
@@ -29,6 +32,11 @@ func processNamespaceServices(clientset kubernetes.Interface, namespace string,
 		return nil, err
 	}
 
+	ruleServices, err := retrieveRuleReferencedNames(dynamicClient, namespace, schema.GroupResource{Resource: "services"}, opts.ReferenceRules)
+	if err != nil {
+		return nil, err
+	}
+
 	var endpointsWithoutSubsets []ResourceInfo
 
 	for _, endpoints := range endpointSlices.Items {
@@ -56,7 +64,13 @@ func processNamespaceServices(clientset kubernetes.Interface, namespace string,
 			status.Reason = "Marked with unused label"
 			endpointsWithoutSubsets = append(endpointsWithoutSubsets, status)
 			continue
-		} else if len(endpoints.Endpoints) == 0 {
+		}
+
+		if slices.Contains(ruleServices, status.Name) {
+			continue
+		}
+
+		if len(endpoints.Endpoints) == 0 {
 			status.Reason = "Service has no endpointslices"
 			endpointsWithoutSubsets = append(endpointsWithoutSubsets, status)
 		}
@@ -71,11 +85,11 @@ func processNamespaceServices(clientset kubernetes.Interface, namespace string,
 	return endpointsWithoutSubsets, nil
 }
 
-func GetUnusedServices(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
+func GetUnusedServices(filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
 	resources := make(map[string]map[string][]ResourceInfo)
 
 	for _, namespace := range filterOpts.Namespaces(clientset) {
-		diff, err := processNamespaceServices(clientset, namespace, filterOpts, opts)
+		diff, err := processNamespaceServices(clientset, dynamicClient, namespace, filterOpts, opts)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to process namespace %s: %v\n", namespace, err)
 			continue
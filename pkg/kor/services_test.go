@@ -59,7 +59,7 @@ func createTestServices(t *testing.T) *fake.Clientset {
 func TestGetEndpointsWithoutSubsets(t *testing.T) {
 	clientset := createTestServices(t)
 
-	servicesWithoutEndpoints, err := processNamespaceServices(clientset, testNamespace, &filters.Options{}, common.Opts{})
+	servicesWithoutEndpoints, err := processNamespaceServices(clientset, nil, testNamespace, &filters.Options{}, common.Opts{})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -85,7 +85,7 @@ func TestGetUnusedServicesStructured(t *testing.T) {
 		GroupBy:       "namespace",
 	}
 
-	output, err := GetUnusedServices(&filters.Options{}, clientset, "json", opts)
+	output, err := GetUnusedServices(&filters.Options{}, clientset, nil, "json", opts)
 	if err != nil {
 		t.Fatalf("Error calling GetUnusedServicesStructured: %v", err)
 	}
@@ -171,7 +171,7 @@ func TestFilterOwnerReferencedServices(t *testing.T) {
 
 	// Test without filter - should return both
 	filterOptsNoSkip := &filters.Options{IgnoreOwnerReferences: false}
-	unusedWithoutFilter, err := processNamespaceServices(clientset, testNamespace, filterOptsNoSkip, common.Opts{})
+	unusedWithoutFilter, err := processNamespaceServices(clientset, nil, testNamespace, filterOptsNoSkip, common.Opts{})
 	if err != nil {
 		t.Fatalf("Error retrieving unused services: %v", err)
 	}
@@ -206,7 +206,7 @@ func TestFilterOwnerReferencedServices(t *testing.T) {
 	}
 
 	// Test without filter - should return both
-	unusedWithoutFilter2, err := processNamespaceServices(clientset, testNamespace, filterOptsNoSkip, common.Opts{})
+	unusedWithoutFilter2, err := processNamespaceServices(clientset, nil, testNamespace, filterOptsNoSkip, common.Opts{})
 	if err != nil {
 		t.Fatalf("Error retrieving unused services: %v", err)
 	}
@@ -217,7 +217,7 @@ func TestFilterOwnerReferencedServices(t *testing.T) {
 
 	// Test with filter - should return only standalone
 	filterOptsWithSkip := &filters.Options{IgnoreOwnerReferences: true}
-	unusedWithFilter, err := processNamespaceServices(clientset, testNamespace, filterOptsWithSkip, common.Opts{})
+	unusedWithFilter, err := processNamespaceServices(clientset, nil, testNamespace, filterOptsWithSkip, common.Opts{})
 	if err != nil {
 		t.Fatalf("Error retrieving unused services: %v", err)
 	}
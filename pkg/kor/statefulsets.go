@@ -7,8 +7,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/yonahd/kor/pkg/common"
@@ -18,6 +23,22 @@ import (
 //go:embed exceptions/statefulsets/statefulsets.json
 var statefulsetConfig []byte
 
+// retrieveStatefulSetIssue explains why a StatefulSet can't run its pods, or returns an empty string
+func retrieveStatefulSetIssue(statefulSet appsv1.StatefulSet, pods []corev1.Pod, objects *namespaceObjects, threshold time.Duration, now time.Time) string {
+	if missing := objects.missingReferences(statefulSet.Spec.Template.Spec); len(missing) > 0 {
+		return "Pod template references missing " + strings.Join(missing, ", ")
+	}
+
+	// Skip StatefulSets the controller hasn't reported on yet
+	if statefulSet.Status.ObservedGeneration == 0 || statefulSet.Status.AvailableReplicas > 0 {
+		return ""
+	}
+	if age := now.Sub(retrieveUnavailableSince(&statefulSet, pods)); age >= threshold {
+		return fmt.Sprintf("No available replicas for %s", duration.HumanDuration(age))
+	}
+	return ""
+}
+
 func processNamespaceStatefulSets(clientset kubernetes.Interface, namespace string, filterOpts *filters.Options, opts common.Opts) ([]ResourceInfo, error) {
 	statefulSetsList, err := clientset.AppsV1().StatefulSets(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
 	if err != nil {
@@ -28,7 +49,19 @@ func processNamespaceStatefulSets(clientset kubernetes.Interface, namespace stri
 		return nil, err
 	}
 
+	objects, err := retrieveNamespaceObjects(clientset, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
 	var statefulSetsWithoutReplicas []ResourceInfo
+	var unavailableStatefulSets []ResourceInfo
+	now := time.Now()
 
 	for _, statefulSet := range statefulSetsList.Items {
 		// Skip resources with ownerReferences if the general flag is set
@@ -60,6 +93,12 @@ func processNamespaceStatefulSets(clientset kubernetes.Interface, namespace stri
 		if *statefulSet.Spec.Replicas == 0 {
 			status.Reason = "StatefulSet has no replicas"
 			statefulSetsWithoutReplicas = append(statefulSetsWithoutReplicas, status)
+			continue
+		}
+
+		if status.Reason = retrieveStatefulSetIssue(statefulSet, pods.Items, objects, opts.UnavailableThreshold, now); status.Reason != "" {
+			status.ReportOnly = true
+			unavailableStatefulSets = append(unavailableStatefulSets, status)
 		}
 	}
 	if opts.DeleteFlag {
@@ -68,7 +107,7 @@ func processNamespaceStatefulSets(clientset kubernetes.Interface, namespace stri
 		}
 	}
 
-	return statefulSetsWithoutReplicas, nil
+	return append(statefulSetsWithoutReplicas, unavailableStatefulSets...), nil
 }
 
 func GetUnusedStatefulSets(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"reflect"
 	"testing"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -72,6 +73,58 @@ func TestProcessNamespaceStatefulSets(t *testing.T) {
 	}
 }
 
+func TestProcessNamespaceStatefulSetIssues(t *testing.T) {
+	clientset := fake.NewClientset()
+	isController := true
+	twoDaysAgo := v1.NewTime(time.Now().Add(-48 * time.Hour))
+
+	unavailable := CreateTestStatefulSet(testNamespace, "unavailable", 1, AppLabels)
+	unavailable.UID = "unavailable-uid"
+	unavailable.CreationTimestamp = v1.NewTime(time.Now().Add(-30 * 24 * time.Hour))
+	unavailable.Status = appsv1.StatefulSetStatus{ObservedGeneration: 1}
+
+	recovering := CreateTestStatefulSet(testNamespace, "recovering", 1, AppLabels)
+	recovering.UID = "recovering-uid"
+	recovering.CreationTimestamp = v1.NewTime(time.Now().Add(-30 * 24 * time.Hour))
+	recovering.Status = appsv1.StatefulSetStatus{ObservedGeneration: 1}
+
+	missingClaim := CreateTestStatefulSet(testNamespace, "missing-claim", 1, AppLabels)
+	missingClaim.Spec.Template.Spec.Volumes = []corev1.Volume{
+		{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "shared-data"}}},
+	}
+
+	for _, statefulSet := range []*appsv1.StatefulSet{unavailable, recovering, missingClaim} {
+		if _, err := clientset.AppsV1().StatefulSets(testNamespace).Create(context.TODO(), statefulSet, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake statefulset: %v", err)
+		}
+	}
+
+	newPod := func(name string, owner *appsv1.StatefulSet, readySince v1.Time) *corev1.Pod {
+		pod := CreateTestPod(testNamespace, name, "", nil, AppLabels)
+		pod.OwnerReferences = []v1.OwnerReference{{Kind: "StatefulSet", Name: owner.Name, UID: owner.UID, Controller: &isController}}
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse, LastTransitionTime: readySince}}
+		return pod
+	}
+	for _, pod := range []*corev1.Pod{newPod("unavailable-0", unavailable, twoDaysAgo), newPod("recovering-0", recovering, v1.Now())} {
+		if _, err := clientset.CoreV1().Pods(testNamespace).Create(context.TODO(), pod, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake pod: %v", err)
+		}
+	}
+
+	issues, err := processNamespaceStatefulSets(clientset, testNamespace, &filters.Options{}, common.Opts{UnavailableThreshold: 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "missing-claim", Reason: "Pod template references missing PersistentVolumeClaim shared-data", ReportOnly: true},
+		{Name: "unavailable", Reason: "No available replicas for 2d", ReportOnly: true},
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Expected %v, got %v", expected, issues)
+	}
+}
+
 func TestGetUnusedStatefulSetsStructured(t *testing.T) {
 	clientset := createTestStatefulSets(t)
 
@@ -7,7 +7,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
@@ -19,6 +23,45 @@ import (
 //go:embed exceptions/storageclasses/storageclasses.json
 var storageClassesConfig []byte
 
+// defaultStorageClassAnnotations mark the StorageClass used by PVCs without a storageClassName
+var defaultStorageClassAnnotations = []string{
+	"storageclass.kubernetes.io/is-default-class",
+	"storageclass.beta.kubernetes.io/is-default-class",
+}
+
+func isDefaultStorageClass(sc storagev1.StorageClass) bool {
+	return slices.ContainsFunc(defaultStorageClassAnnotations, func(annotation string) bool {
+		return sc.Annotations[annotation] == "true"
+	})
+}
+
+func retrieveDefaultStorageClasses(clientset kubernetes.Interface) ([]string, error) {
+	scs, err := clientset.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var defaultStorageClasses []string
+	for _, sc := range scs.Items {
+		if isDefaultStorageClass(sc) {
+			defaultStorageClasses = append(defaultStorageClasses, sc.Name)
+		}
+	}
+	return defaultStorageClasses, nil
+}
+
+// claimStorageClasses returns the StorageClasses a claim spec resolves to, where a nil storageClassName
+// selects the default StorageClass and an empty one disables dynamic provisioning
+func claimStorageClasses(storageClassName *string, defaultStorageClasses []string) []string {
+	switch {
+	case storageClassName == nil:
+		return defaultStorageClasses
+	case *storageClassName == "":
+		return nil
+	default:
+		return []string{*storageClassName}
+	}
+}
+
 func retrieveUsedStorageClasses(clientset kubernetes.Interface) ([]string, error) {
 	pvs, err := clientset.CoreV1().PersistentVolumes().List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
@@ -32,6 +75,21 @@ func retrieveUsedStorageClasses(clientset kubernetes.Interface) ([]string, error
 		os.Exit(1)
 	}
 
+	defaultStorageClasses, err := retrieveDefaultStorageClasses(clientset)
+	if err != nil {
+		return nil, err
+	}
+
+	statefulSets, err := clientset.AppsV1().StatefulSets("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	podSpecs, err := retrieveWorkloadPodSpecs(clientset, "")
+	if err != nil {
+		return nil, err
+	}
+
 	var usedStorageClasses []string
 
 	// Iterate through each PV and check for StorageClass usage
@@ -43,25 +101,132 @@ func retrieveUsedStorageClasses(clientset kubernetes.Interface) ([]string, error
 
 	// Iterate through each PVC and check for StorageClass usage
 	for _, pvc := range pvcs.Items {
-		if pvc.Spec.StorageClassName != nil {
-			usedStorageClasses = append(usedStorageClasses, *pvc.Spec.StorageClassName)
+		usedStorageClasses = append(usedStorageClasses, claimStorageClasses(pvc.Spec.StorageClassName, defaultStorageClasses)...)
+	}
+
+	// Claims that are created once a StatefulSet scales up or a pod with an ephemeral volume starts
+	for _, sts := range statefulSets.Items {
+		for _, template := range sts.Spec.VolumeClaimTemplates {
+			usedStorageClasses = append(usedStorageClasses, claimStorageClasses(template.Spec.StorageClassName, defaultStorageClasses)...)
+		}
+	}
+	for _, podSpec := range podSpecs {
+		for _, volume := range podSpec.Spec.Volumes {
+			if volume.Ephemeral != nil && volume.Ephemeral.VolumeClaimTemplate != nil {
+				usedStorageClasses = append(usedStorageClasses, claimStorageClasses(volume.Ephemeral.VolumeClaimTemplate.Spec.StorageClassName, defaultStorageClasses)...)
+			}
 		}
 	}
 
 	return usedStorageClasses, err
 }
 
-func processStorageClasses(clientset kubernetes.Interface, filterOpts *filters.Options) ([]ResourceInfo, error) {
-	scs, err := clientset.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
+// provisionerFlags are the flags external provisioners and CSI drivers take their name from
+var provisionerFlags = []string{"provisioner", "provisioner-name", "driver-name", "drivername"}
+
+// retrieveProvisionerFlagValues returns the values of provisionerFlags in a container command line,
+// in either the --flag=value or the --flag value form
+func retrieveProvisionerFlagValues(args []string) []string {
+	var values []string
+	for i, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !slices.Contains(provisionerFlags, name) {
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				continue
+			}
+			value = args[i+1]
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// isProvisionerRunning reports whether a provisioner has a CSIDriver or a running pod passing its name as a flag or env value
+func isProvisionerRunning(provisioner string, csiDrivers []string, pods []corev1.Pod) bool {
+	// In-tree provisioners, including kubernetes.io/no-provisioner, are part of the control plane
+	if strings.HasPrefix(provisioner, "kubernetes.io/") || slices.Contains(csiDrivers, provisioner) {
+		return true
+	}
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			if slices.Contains(retrieveProvisionerFlagValues(slices.Concat(container.Command, container.Args)), provisioner) {
+				return true
+			}
+			for _, env := range container.Env {
+				if env.Value == provisioner {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// retrieveStorageClassIssues reports StorageClasses that are in use but whose provisioner is missing,
+// and default StorageClasses competing with each other
+func retrieveStorageClassIssues(clientset kubernetes.Interface, scs []storagev1.StorageClass) ([]ResourceInfo, error) {
+	csiDriverList, err := clientset.StorageV1().CSIDrivers().List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
+	csiDrivers := make([]string, 0, len(csiDriverList.Items))
+	for _, driver := range csiDriverList.Items {
+		csiDrivers = append(csiDrivers, driver.Name)
+	}
 
-	config, err := unmarshalConfig(storageClassesConfig)
+	var pods []corev1.Pod
+	if slices.ContainsFunc(scs, func(sc storagev1.StorageClass) bool {
+		return !strings.HasPrefix(sc.Provisioner, "kubernetes.io/") && !slices.Contains(csiDrivers, sc.Provisioner)
+	}) {
+		podList, err := clientset.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		pods = podList.Items
+	}
+
+	defaultStorageClasses, err := retrieveDefaultStorageClasses(clientset)
 	if err != nil {
 		return nil, err
 	}
 
+	var issues []ResourceInfo
+	for _, sc := range scs {
+		if !isProvisionerRunning(sc.Provisioner, csiDrivers, pods) {
+			reason := fmt.Sprintf("Provisioner %s has no CSIDriver and no running pods", sc.Provisioner)
+			issues = append(issues, ResourceInfo{Name: sc.Name, Reason: reason, ReportOnly: true})
+			continue
+		}
+		if len(defaultStorageClasses) > 1 && isDefaultStorageClass(sc) {
+			reason := fmt.Sprintf("One of %d default StorageClasses", len(defaultStorageClasses))
+			issues = append(issues, ResourceInfo{Name: sc.Name, Reason: reason, ReportOnly: true})
+		}
+	}
+	return issues, nil
+}
+
+// processStorageClasses returns the unused StorageClasses, and the used ones with a missing provisioner
+// or competing for the default as report-only findings
+func processStorageClasses(clientset kubernetes.Interface, filterOpts *filters.Options) ([]ResourceInfo, []ResourceInfo, error) {
+	scs, err := clientset.StorageV1().StorageClasses().List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config, err := unmarshalConfig(storageClassesConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var unusedStorageClasses []ResourceInfo
 	storageClassNames := make([]string, 0, len(scs.Items))
 
@@ -82,7 +247,7 @@ func processStorageClasses(clientset kubernetes.Interface, filterOpts *filters.O
 
 		exceptionFound, err := isResourceException(sc.Name, sc.Namespace, config.ExceptionStorageClasses)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		if exceptionFound {
@@ -94,19 +259,28 @@ func processStorageClasses(clientset kubernetes.Interface, filterOpts *filters.O
 
 	usedStorageClasses, err := retrieveUsedStorageClasses(clientset)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	diff := CalculateResourceDifference(usedStorageClasses, storageClassNames)
 	for _, name := range diff {
 		unusedStorageClasses = append(unusedStorageClasses, ResourceInfo{Name: name, Reason: "Not in Use"})
 	}
-	return unusedStorageClasses, nil
+
+	usedScs := slices.DeleteFunc(slices.Clone(scs.Items), func(sc storagev1.StorageClass) bool {
+		return !slices.Contains(storageClassNames, sc.Name) || slices.Contains(diff, sc.Name)
+	})
+	issues, err := retrieveStorageClassIssues(clientset, usedScs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return unusedStorageClasses, issues, nil
 }
 
 func GetUnusedStorageClasses(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
 	resources := make(map[string]map[string][]ResourceInfo)
-	diff, err := processStorageClasses(clientset, filterOpts)
+	diff, issues, err := processStorageClasses(clientset, filterOpts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to process storageClasses: %v\n", err)
 	}
@@ -115,6 +289,7 @@ func GetUnusedStorageClasses(filterOpts *filters.Options, clientset kubernetes.I
 			fmt.Fprintf(os.Stderr, "Failed to delete StorageClass %s: %v\n", diff, err)
 		}
 	}
+	diff = append(diff, issues...)
 	switch opts.GroupBy {
 	case "namespace":
 		resources[""] = make(map[string][]ResourceInfo)
@@ -6,6 +6,8 @@ import (
 	"reflect"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 
@@ -53,7 +55,7 @@ func TestRetrieveUsedStorageClassesFromPVs(t *testing.T) {
 
 func TestProcessStorageClasses(t *testing.T) {
 	clientset := createTestStorageClass(t)
-	unusedStorageClasses, err := processStorageClasses(clientset, &filters.Options{})
+	unusedStorageClasses, _, err := processStorageClasses(clientset, &filters.Options{})
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -129,7 +131,7 @@ func TestFilterOwnerReferencedStorageClasses(t *testing.T) {
 
 	// Test without filter - should return both
 	filterOptsNoSkip := &filters.Options{IgnoreOwnerReferences: false}
-	unusedWithoutFilter, err := processStorageClasses(clientset, filterOptsNoSkip)
+	unusedWithoutFilter, _, err := processStorageClasses(clientset, filterOptsNoSkip)
 	if err != nil {
 		t.Fatalf("Error retrieving unused StorageClasses: %v", err)
 	}
@@ -140,7 +142,7 @@ func TestFilterOwnerReferencedStorageClasses(t *testing.T) {
 
 	// Test with filter - should return only standalone
 	filterOptsWithSkip := &filters.Options{IgnoreOwnerReferences: true}
-	unusedWithFilter, err := processStorageClasses(clientset, filterOptsWithSkip)
+	unusedWithFilter, _, err := processStorageClasses(clientset, filterOptsWithSkip)
 	if err != nil {
 		t.Fatalf("Error retrieving unused StorageClasses: %v", err)
 	}
@@ -153,3 +155,114 @@ func TestFilterOwnerReferencedStorageClasses(t *testing.T) {
 		t.Errorf("Expected standalone-sc to be unused, got %s", unusedWithFilter[0].Name)
 	}
 }
+
+func TestProcessStorageClassesTemplatesAndProvisioners(t *testing.T) {
+	clientset := fake.NewClientset()
+
+	defaultSc := CreateTestStorageClass("fast", "ebs.csi.aws.com")
+	defaultSc.Annotations = map[string]string{"storageclass.kubernetes.io/is-default-class": "true"}
+	legacyDefaultSc := CreateTestStorageClass("legacy-default", "kubernetes.io/aws-ebs")
+	legacyDefaultSc.Annotations = map[string]string{"storageclass.beta.kubernetes.io/is-default-class": "true"}
+	storageClasses := []*storagev1.StorageClass{
+		defaultSc,
+		legacyDefaultSc,
+		CreateTestStorageClass("sts-template", "ebs.csi.aws.com"),
+		CreateTestStorageClass("ephemeral", "nfs.example.com"),
+		CreateTestStorageClass("broken", "gone.example.com"),
+		CreateTestStorageClass("unused", "ebs.csi.aws.com"),
+	}
+	for _, sc := range storageClasses {
+		if _, err := clientset.StorageV1().StorageClasses().Create(context.TODO(), sc, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake StorageClass: %v", err)
+		}
+	}
+
+	if _, err := clientset.StorageV1().CSIDrivers().Create(context.TODO(), CreateTestCSIDriver("ebs.csi.aws.com"), v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake CSIDriver: %v", err)
+	}
+
+	// A PVC without storageClassName uses the default StorageClasses
+	pvc := CreateTestPvc(testNamespace, "data", AppLabels, "")
+	pvc.Spec.StorageClassName = nil
+	brokenPvc := CreateTestPvc(testNamespace, "broken", AppLabels, "broken")
+	for _, claim := range []*corev1.PersistentVolumeClaim{pvc, brokenPvc} {
+		if _, err := clientset.CoreV1().PersistentVolumeClaims(testNamespace).Create(context.TODO(), claim, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake PVC: %v", err)
+		}
+	}
+
+	stsTemplateClass := "sts-template"
+	sts := CreateTestStatefulSet(testNamespace, "db", 0, AppLabels)
+	sts.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{{
+		ObjectMeta: v1.ObjectMeta{Name: "data"},
+		Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &stsTemplateClass},
+	}}
+	if _, err := clientset.AppsV1().StatefulSets(testNamespace).Create(context.TODO(), sts, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake StatefulSet: %v", err)
+	}
+
+	ephemeralClass := "ephemeral"
+	ephemeralVolume := CreateEphemeralVolumeDefinition("scratch", "1Gi")
+	ephemeralVolume.Ephemeral.VolumeClaimTemplate.Spec.StorageClassName = &ephemeralClass
+	deployment := CreateTestDeployment(testNamespace, "app", 1, AppLabels)
+	deployment.Spec.Template.Spec.Volumes = []corev1.Volume{*ephemeralVolume}
+	if _, err := clientset.AppsV1().Deployments(testNamespace).Create(context.TODO(), deployment, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake Deployment: %v", err)
+	}
+
+	provisioner := CreateTestPod(testNamespace, "nfs-provisioner", "", nil, AppLabels)
+	provisioner.Spec.Containers = []corev1.Container{{Name: "provisioner", Env: []corev1.EnvVar{{Name: "PROVISIONER_NAME", Value: "nfs.example.com"}}}}
+	provisioner.Status.Phase = corev1.PodRunning
+	if _, err := clientset.CoreV1().Pods(testNamespace).Create(context.TODO(), provisioner, v1.CreateOptions{}); err != nil {
+		t.Fatalf("Error creating fake Pod: %v", err)
+	}
+
+	unusedStorageClasses, issues, err := processStorageClasses(clientset, &filters.Options{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedUnused := []ResourceInfo{{Name: "unused", Reason: "Not in Use"}}
+	if !reflect.DeepEqual(expectedUnused, unusedStorageClasses) {
+		t.Errorf("Expected %v, got %v", expectedUnused, unusedStorageClasses)
+	}
+
+	expectedIssues := []ResourceInfo{
+		{Name: "broken", Reason: "Provisioner gone.example.com has no CSIDriver and no running pods", ReportOnly: true},
+		{Name: "fast", Reason: "One of 2 default StorageClasses", ReportOnly: true},
+		{Name: "legacy-default", Reason: "One of 2 default StorageClasses", ReportOnly: true},
+	}
+	if !reflect.DeepEqual(expectedIssues, issues) {
+		t.Errorf("Expected %v, got %v", expectedIssues, issues)
+	}
+}
+
+func TestIsProvisionerRunning(t *testing.T) {
+	runningPod := func(args ...string) corev1.Pod {
+		return corev1.Pod{
+			Spec:   corev1.PodSpec{Containers: []corev1.Container{{Name: "provisioner", Args: args}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		provisioner string
+		pods        []corev1.Pod
+		expected    bool
+	}{
+		{"in-tree provisioner", "kubernetes.io/no-provisioner", nil, true},
+		{"flag with equals sign", "nfs.example.com", []corev1.Pod{runningPod("--provisioner=nfs.example.com")}, true},
+		{"flag with separate value", "nfs.example.com", []corev1.Pod{runningPod("--driver-name", "nfs.example.com")}, true},
+		{"provisioner name as substring", "nfs.example.com", []corev1.Pod{runningPod("--provisioner=legacy-nfs.example.com")}, false},
+		{"provisioner name in another flag", "nfs.example.com", []corev1.Pod{runningPod("--endpoint=unix:///csi/nfs.example.com/csi.sock")}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if running := isProvisionerRunning(test.provisioner, nil, test.pods); running != test.expected {
+				t.Errorf("Expected %v, got %v", test.expected, running)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package kor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func retrieveBoundValidatingAdmissionPolicies(clientset kubernetes.Interface) ([]string, error) {
+	bindings, err := clientset.AdmissionregistrationV1().ValidatingAdmissionPolicyBindings().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ValidatingAdmissionPolicyBindings: %v", err)
+	}
+
+	var boundPolicies []string
+	for _, binding := range bindings.Items {
+		boundPolicies = append(boundPolicies, binding.Spec.PolicyName)
+	}
+
+	return boundPolicies, nil
+}
+
+func processValidatingAdmissionPolicies(clientset kubernetes.Interface, filterOpts *filters.Options) ([]ResourceInfo, error) {
+	policies, err := clientset.AdmissionregistrationV1().ValidatingAdmissionPolicies().List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
+	if err != nil {
+		return nil, err
+	}
+
+	var unusedPolicies []ResourceInfo
+	policyNames := make([]string, 0, len(policies.Items))
+
+	for _, policy := range policies.Items {
+		// Skip resources with ownerReferences if the general flag is set
+		if filterOpts.IgnoreOwnerReferences && len(policy.OwnerReferences) > 0 {
+			continue
+		}
+
+		if pass, _ := filter.SetObject(&policy).Run(filterOpts); pass {
+			continue
+		}
+
+		if policy.Labels["kor/used"] == "false" {
+			unusedPolicies = append(unusedPolicies, ResourceInfo{Name: policy.Name, Reason: unusedLabelReason})
+			continue
+		}
+
+		policyNames = append(policyNames, policy.Name)
+	}
+
+	boundPolicies, err := retrieveBoundValidatingAdmissionPolicies(clientset)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := CalculateResourceDifference(boundPolicies, policyNames)
+	for _, name := range diff {
+		unusedPolicies = append(unusedPolicies, ResourceInfo{Name: name, Reason: "ValidatingAdmissionPolicy has no ValidatingAdmissionPolicyBinding"})
+	}
+	return unusedPolicies, nil
+}
+
+func GetUnusedValidatingAdmissionPolicies(filterOpts *filters.Options, clientset kubernetes.Interface, outputFormat string, opts common.Opts) (string, error) {
+	resources := make(map[string]map[string][]ResourceInfo)
+	diff, err := processValidatingAdmissionPolicies(clientset, filterOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to process validatingAdmissionPolicies: %v\n", err)
+	}
+	if opts.DeleteFlag {
+		if diff, err = DeleteResource(diff, clientset, "", "ValidatingAdmissionPolicy", opts.NoInteractive); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to delete ValidatingAdmissionPolicy %s: %v\n", diff, err)
+		}
+	}
+	switch opts.GroupBy {
+	case "namespace":
+		resources[""] = make(map[string][]ResourceInfo)
+		resources[""]["ValidatingAdmissionPolicy"] = diff
+	case "resource":
+		appendResources(resources, "ValidatingAdmissionPolicy", "", diff)
+	}
+
+	var outputBuffer bytes.Buffer
+	var jsonResponse []byte
+	switch outputFormat {
+	case "table":
+		outputBuffer = FormatOutput(resources, opts)
+	case "json", "yaml":
+		var err error
+		if jsonResponse, err = json.MarshalIndent(resources, "", "  "); err != nil {
+			return "", err
+		}
+	}
+
+	unusedPolicies, err := unusedResourceFormatter(outputFormat, outputBuffer, opts, jsonResponse)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+	}
+
+	return unusedPolicies, nil
+}
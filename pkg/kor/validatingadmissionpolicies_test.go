@@ -0,0 +1,82 @@
+package kor
+
+import (
+	"context"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func createTestValidatingAdmissionPolicy(name string, paramKind *admissionregistrationv1.ParamKind) *admissionregistrationv1.ValidatingAdmissionPolicy {
+	return &admissionregistrationv1.ValidatingAdmissionPolicy{
+		ObjectMeta: v1.ObjectMeta{Name: name},
+		Spec:       admissionregistrationv1.ValidatingAdmissionPolicySpec{ParamKind: paramKind},
+	}
+}
+
+func createTestValidatingAdmissionPolicyBinding(name, policyName string, paramRef *admissionregistrationv1.ParamRef) *admissionregistrationv1.ValidatingAdmissionPolicyBinding {
+	return &admissionregistrationv1.ValidatingAdmissionPolicyBinding{
+		ObjectMeta: v1.ObjectMeta{Name: name},
+		Spec:       admissionregistrationv1.ValidatingAdmissionPolicyBindingSpec{PolicyName: policyName, ParamRef: paramRef},
+	}
+}
+
+func createTestValidatingAdmissionPolicies(t *testing.T) *fake.Clientset {
+	clientset := fake.NewClientset()
+
+	configMapParam := &admissionregistrationv1.ParamKind{APIVersion: "v1", Kind: "ConfigMap"}
+	widgetParam := &admissionregistrationv1.ParamKind{APIVersion: "example.com/v1", Kind: "Widget"}
+
+	policies := []*admissionregistrationv1.ValidatingAdmissionPolicy{
+		createTestValidatingAdmissionPolicy("bound-policy", nil),
+		createTestValidatingAdmissionPolicy("unbound-policy", nil),
+		createTestValidatingAdmissionPolicy("configmap-param-policy", configMapParam),
+		createTestValidatingAdmissionPolicy("widget-param-policy", widgetParam),
+	}
+	for _, policy := range policies {
+		if _, err := clientset.AdmissionregistrationV1().ValidatingAdmissionPolicies().Create(context.TODO(), policy, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake validatingAdmissionPolicy: %v", err)
+		}
+	}
+
+	bindings := []*admissionregistrationv1.ValidatingAdmissionPolicyBinding{
+		createTestValidatingAdmissionPolicyBinding("valid-binding", "bound-policy", nil),
+		createTestValidatingAdmissionPolicyBinding("dangling-binding", "deleted-policy", nil),
+		createTestValidatingAdmissionPolicyBinding("valid-param-binding", "configmap-param-policy", &admissionregistrationv1.ParamRef{Name: "limits", Namespace: testNamespace}),
+		createTestValidatingAdmissionPolicyBinding("missing-param-binding", "configmap-param-policy", &admissionregistrationv1.ParamRef{Name: "gone", Namespace: testNamespace}),
+		createTestValidatingAdmissionPolicyBinding("per-request-param-binding", "configmap-param-policy", &admissionregistrationv1.ParamRef{Name: "limits"}),
+		createTestValidatingAdmissionPolicyBinding("unserved-param-binding", "widget-param-policy", &admissionregistrationv1.ParamRef{Name: "widget"}),
+	}
+	for _, binding := range bindings {
+		if _, err := clientset.AdmissionregistrationV1().ValidatingAdmissionPolicyBindings().Create(context.TODO(), binding, v1.CreateOptions{}); err != nil {
+			t.Fatalf("Error creating fake validatingAdmissionPolicyBinding: %v", err)
+		}
+	}
+
+	clientset.Resources = []*v1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []v1.APIResource{{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: []string{"get", "list"}}},
+		},
+	}
+
+	return clientset
+}
+
+func TestProcessValidatingAdmissionPolicies(t *testing.T) {
+	clientset := createTestValidatingAdmissionPolicies(t)
+
+	unusedPolicies, err := processValidatingAdmissionPolicies(clientset, &filters.Options{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := ResourceInfo{Name: "unbound-policy", Reason: "ValidatingAdmissionPolicy has no ValidatingAdmissionPolicyBinding"}
+	if len(unusedPolicies) != 1 || unusedPolicies[0] != expected {
+		t.Errorf("Expected %v, got %v", expected, unusedPolicies)
+	}
+}
@@ -0,0 +1,158 @@
+package kor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/yonahd/kor/pkg/common"
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func validatePolicyReference(binding admissionregistrationv1.ValidatingAdmissionPolicyBinding, policies map[string]admissionregistrationv1.ValidatingAdmissionPolicy) *ResourceInfo {
+	if _, exists := policies[binding.Spec.PolicyName]; !exists {
+		return &ResourceInfo{Name: binding.Name, Reason: "ValidatingAdmissionPolicyBinding references a non-existing ValidatingAdmissionPolicy"}
+	}
+	return nil
+}
+
+// validateParamReference checks that the parameter object named by the binding's paramRef exists.
+// Selector based references and namespaced params without a namespace are resolved per admission request and are skipped.
+func validateParamReference(binding admissionregistrationv1.ValidatingAdmissionPolicyBinding, policy admissionregistrationv1.ValidatingAdmissionPolicy, discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface, cache map[string][]metav1.APIResource) (*ResourceInfo, error) {
+	paramRef, paramKind := binding.Spec.ParamRef, policy.Spec.ParamKind
+	if paramRef == nil || paramKind == nil || paramRef.Name == "" {
+		return nil, nil
+	}
+
+	apiResource, err := retrieveAPIResource(discoveryClient, cache, paramKind.APIVersion, paramKind.Kind)
+	if err != nil {
+		return nil, err
+	}
+	if apiResource == nil {
+		reason := fmt.Sprintf("ValidatingAdmissionPolicyBinding references param kind %s %s which is not served", paramKind.APIVersion, paramKind.Kind)
+		return &ResourceInfo{Name: binding.Name, Reason: reason}, nil
+	}
+
+	namespace := paramRef.Namespace
+	if !apiResource.Namespaced {
+		namespace = ""
+	} else if namespace == "" {
+		return nil, nil
+	}
+
+	gv, err := schema.ParseGroupVersion(paramKind.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = dynamicClient.Resource(gv.WithResource(apiResource.Name)).Namespace(namespace).Get(context.TODO(), paramRef.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		name := paramRef.Name
+		if namespace != "" {
+			name = namespace + "/" + name
+		}
+		reason := fmt.Sprintf("ValidatingAdmissionPolicyBinding references a non-existing %s %s", paramKind.Kind, name)
+		return &ResourceInfo{Name: binding.Name, Reason: reason}, nil
+	}
+	return nil, err
+}
+
+func processValidatingAdmissionPolicyBindings(clientset kubernetes.Interface, dynamicClient dynamic.Interface, filterOpts *filters.Options) ([]ResourceInfo, error) {
+	bindings, err := clientset.AdmissionregistrationV1().ValidatingAdmissionPolicyBindings().List(context.TODO(), metav1.ListOptions{LabelSelector: filterOpts.IncludeLabels})
+	if err != nil {
+		return nil, err
+	}
+
+	policyList, err := clientset.AdmissionregistrationV1().ValidatingAdmissionPolicies().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[string]admissionregistrationv1.ValidatingAdmissionPolicy, len(policyList.Items))
+	for _, policy := range policyList.Items {
+		policies[policy.Name] = policy
+	}
+
+	var unusedBindings []ResourceInfo
+	apiResourceCache := make(map[string][]metav1.APIResource)
+
+	for _, binding := range bindings.Items {
+		// Skip resources with ownerReferences if the general flag is set
+		if filterOpts.IgnoreOwnerReferences && len(binding.OwnerReferences) > 0 {
+			continue
+		}
+
+		if pass, _ := filter.SetObject(&binding).Run(filterOpts); pass {
+			continue
+		}
+
+		if binding.Labels["kor/used"] == "false" {
+			unusedBindings = append(unusedBindings, ResourceInfo{Name: binding.Name, Reason: unusedLabelReason})
+			continue
+		}
+
+		if policyReferenceIssue := validatePolicyReference(binding, policies); policyReferenceIssue != nil {
+			unusedBindings = append(unusedBindings, *policyReferenceIssue)
+			continue
+		}
+
+		paramReferenceIssue, err := validateParamReference(binding, policies[binding.Spec.PolicyName], clientset.Discovery(), dynamicClient, apiResourceCache)
+		if err != nil {
+			return nil, err
+		}
+		if paramReferenceIssue != nil {
+			unusedBindings = append(unusedBindings, *paramReferenceIssue)
+		}
+	}
+
+	return unusedBindings, nil
+}
+
+func GetUnusedValidatingAdmissionPolicyBindings(filterOpts *filters.Options, clientset kubernetes.Interface, dynamicClient dynamic.Interface, outputFormat string, opts common.Opts) (string, error) {
+	resources := make(map[string]map[string][]ResourceInfo)
+	diff, err := processValidatingAdmissionPolicyBindings(clientset, dynamicClient, filterOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to process validatingAdmissionPolicyBindings: %v\n", err)
+	}
+	if opts.DeleteFlag {
+		if diff, err = DeleteResource(diff, clientset, "", "ValidatingAdmissionPolicyBinding", opts.NoInteractive); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to delete ValidatingAdmissionPolicyBinding %s: %v\n", diff, err)
+		}
+	}
+	switch opts.GroupBy {
+	case "namespace":
+		resources[""] = make(map[string][]ResourceInfo)
+		resources[""]["ValidatingAdmissionPolicyBinding"] = diff
+	case "resource":
+		appendResources(resources, "ValidatingAdmissionPolicyBinding", "", diff)
+	}
+
+	var outputBuffer bytes.Buffer
+	var jsonResponse []byte
+	switch outputFormat {
+	case "table":
+		outputBuffer = FormatOutput(resources, opts)
+	case "json", "yaml":
+		var err error
+		if jsonResponse, err = json.MarshalIndent(resources, "", "  "); err != nil {
+			return "", err
+		}
+	}
+
+	unusedBindings, err := unusedResourceFormatter(outputFormat, outputBuffer, opts, jsonResponse)
+	if err != nil {
+		fmt.Printf("err: %v\n", err)
+	}
+
+	return unusedBindings, nil
+}
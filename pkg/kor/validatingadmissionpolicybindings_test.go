@@ -0,0 +1,39 @@
+package kor
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/yonahd/kor/pkg/filters"
+)
+
+func TestProcessValidatingAdmissionPolicyBindings(t *testing.T) {
+	clientset := createTestValidatingAdmissionPolicies(t)
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "configmaps"}: "ConfigMapList",
+	}, CreateTestUnstructered("ConfigMap", "v1", testNamespace, "limits"))
+
+	unusedBindings, err := processValidatingAdmissionPolicyBindings(clientset, dynamicClient, &filters.Options{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []ResourceInfo{
+		{Name: "dangling-binding", Reason: "ValidatingAdmissionPolicyBinding references a non-existing ValidatingAdmissionPolicy"},
+		{Name: "missing-param-binding", Reason: "ValidatingAdmissionPolicyBinding references a non-existing ConfigMap " + testNamespace + "/gone"},
+		{Name: "unserved-param-binding", Reason: "ValidatingAdmissionPolicyBinding references param kind example.com/v1 Widget which is not served"},
+	}
+
+	if len(unusedBindings) != len(expected) {
+		t.Fatalf("Expected %d unused bindings, got %d: %v", len(expected), len(unusedBindings), unusedBindings)
+	}
+	for i, binding := range unusedBindings {
+		if binding != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], binding)
+		}
+	}
+}
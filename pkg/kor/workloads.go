@@ -0,0 +1,324 @@
+package kor
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// workloadPodSpec is a pod spec together with the object declaring it
+type workloadPodSpec struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Spec      corev1.PodSpec
+}
+
+// retrieveWorkloadPodSpecs returns the pod specs of Pods and of every built-in pod template
+// (Deployments, StatefulSets, DaemonSets, ReplicaSets, Jobs, CronJobs and PodTemplates), so a
+// reference is found even while the workload is scaled to zero.
+// An empty namespace lists all namespaces.
+func retrieveWorkloadPodSpecs(clientset kubernetes.Interface, namespace string) ([]workloadPodSpec, error) {
+	var podSpecs []workloadPodSpec
+
+	pods, err := clientset.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Pods: %v", err)
+	}
+	for _, pod := range pods.Items {
+		podSpecs = append(podSpecs, workloadPodSpec{"Pod", pod.Namespace, pod.Name, pod.Spec})
+	}
+
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Deployments: %v", err)
+	}
+	for _, deployment := range deployments.Items {
+		podSpecs = append(podSpecs, workloadPodSpec{"Deployment", deployment.Namespace, deployment.Name, deployment.Spec.Template.Spec})
+	}
+
+	statefulSets, err := clientset.AppsV1().StatefulSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list StatefulSets: %v", err)
+	}
+	for _, sts := range statefulSets.Items {
+		podSpecs = append(podSpecs, workloadPodSpec{"StatefulSet", sts.Namespace, sts.Name, sts.Spec.Template.Spec})
+	}
+
+	daemonSets, err := clientset.AppsV1().DaemonSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list DaemonSets: %v", err)
+	}
+	for _, ds := range daemonSets.Items {
+		podSpecs = append(podSpecs, workloadPodSpec{"DaemonSet", ds.Namespace, ds.Name, ds.Spec.Template.Spec})
+	}
+
+	replicaSets, err := clientset.AppsV1().ReplicaSets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ReplicaSets: %v", err)
+	}
+	for _, rs := range replicaSets.Items {
+		podSpecs = append(podSpecs, workloadPodSpec{"ReplicaSet", rs.Namespace, rs.Name, rs.Spec.Template.Spec})
+	}
+
+	jobs, err := clientset.BatchV1().Jobs(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Jobs: %v", err)
+	}
+	for _, job := range jobs.Items {
+		podSpecs = append(podSpecs, workloadPodSpec{"Job", job.Namespace, job.Name, job.Spec.Template.Spec})
+	}
+
+	cronJobs, err := clientset.BatchV1().CronJobs(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CronJobs: %v", err)
+	}
+	for _, cronJob := range cronJobs.Items {
+		podSpecs = append(podSpecs, workloadPodSpec{"CronJob", cronJob.Namespace, cronJob.Name, cronJob.Spec.JobTemplate.Spec.Template.Spec})
+	}
+
+	podTemplates, err := clientset.CoreV1().PodTemplates(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PodTemplates: %v", err)
+	}
+	for _, podTemplate := range podTemplates.Items {
+		podSpecs = append(podSpecs, workloadPodSpec{"PodTemplate", podTemplate.Namespace, podTemplate.Name, podTemplate.Template.Spec})
+	}
+
+	return podSpecs, nil
+}
+
+// keyUsage records how pod specs consume the keys of ConfigMaps or Secrets
+type keyUsage struct {
+	// whole holds objects consumed in full, through envFrom or a volume without items
+	whole map[string]bool
+	// keys holds the individually referenced keys of each object
+	keys map[string]map[string]bool
+}
+
+func (u keyUsage) addKey(name, key string) {
+	if u.keys[name] == nil {
+		u.keys[name] = make(map[string]bool)
+	}
+	u.keys[name][key] = true
+}
+
+func (u keyUsage) addItems(name string, items []corev1.KeyToPath) {
+	if len(items) == 0 {
+		u.whole[name] = true
+		return
+	}
+	for _, item := range items {
+		u.addKey(name, item.Key)
+	}
+}
+
+// retrieveKeyUsage returns the keys of ConfigMaps or Secrets, depending on kind, referenced by
+// configMapKeyRef/secretKeyRef, volume items and projected volume items, along with the objects consumed in full
+func retrieveKeyUsage(podSpecs []workloadPodSpec, kind string) keyUsage {
+	usage := keyUsage{whole: make(map[string]bool), keys: make(map[string]map[string]bool)}
+
+	for _, podSpec := range podSpecs {
+		containers := slices.Concat(podSpec.Spec.InitContainers, podSpec.Spec.Containers)
+		for _, container := range containers {
+			for _, env := range container.Env {
+				if env.ValueFrom == nil {
+					continue
+				}
+				if ref := env.ValueFrom.ConfigMapKeyRef; kind == "ConfigMap" && ref != nil {
+					usage.addKey(ref.Name, ref.Key)
+				}
+				if ref := env.ValueFrom.SecretKeyRef; kind == "Secret" && ref != nil {
+					usage.addKey(ref.Name, ref.Key)
+				}
+			}
+			for _, envFrom := range container.EnvFrom {
+				if kind == "ConfigMap" && envFrom.ConfigMapRef != nil {
+					usage.whole[envFrom.ConfigMapRef.Name] = true
+				}
+				if kind == "Secret" && envFrom.SecretRef != nil {
+					usage.whole[envFrom.SecretRef.Name] = true
+				}
+			}
+		}
+
+		for _, volume := range podSpec.Spec.Volumes {
+			if kind == "ConfigMap" && volume.ConfigMap != nil {
+				usage.addItems(volume.ConfigMap.Name, volume.ConfigMap.Items)
+			}
+			if kind == "Secret" && volume.Secret != nil {
+				usage.addItems(volume.Secret.SecretName, volume.Secret.Items)
+			}
+			if volume.Projected == nil {
+				continue
+			}
+			for _, source := range volume.Projected.Sources {
+				if kind == "ConfigMap" && source.ConfigMap != nil {
+					usage.addItems(source.ConfigMap.Name, source.ConfigMap.Items)
+				}
+				if kind == "Secret" && source.Secret != nil {
+					usage.addItems(source.Secret.Name, source.Secret.Items)
+				}
+			}
+		}
+	}
+
+	return usage
+}
+
+// retrieveUnavailableSince returns the last time one of the pods controlled by owner changed readiness, or the
+// owner creation time when it has no pods. StatefulSets and DaemonSets don't report an Available condition.
+func retrieveUnavailableSince(owner metav1.Object, pods []corev1.Pod) time.Time {
+	since := owner.GetCreationTimestamp().Time
+	for _, pod := range pods {
+		controller := metav1.GetControllerOf(&pod)
+		if controller == nil || controller.UID != owner.GetUID() {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.LastTransitionTime.After(since) {
+				since = condition.LastTransitionTime.Time
+			}
+		}
+	}
+	return since
+}
+
+// retrieveUnreferencedKeys returns one report-only entry per key that no consumer references, for objects only used
+// through individual keys. Objects consumed in full or listed in otherUses are skipped.
+func retrieveUnreferencedKeys(usage keyUsage, objectKeys map[string][]string, otherUses []string) []ResourceInfo {
+	var unreferenced []ResourceInfo
+	for _, name := range slices.Sorted(maps.Keys(objectKeys)) {
+		referencedKeys, usedByKey := usage.keys[name]
+		if !usedByKey || usage.whole[name] || slices.Contains(otherUses, name) {
+			continue
+		}
+		for _, key := range objectKeys[name] {
+			if !referencedKeys[key] {
+				unreferenced = append(unreferenced, ResourceInfo{Name: name, Key: key, Reason: "Key is not referenced by any consumer", ReportOnly: true})
+			}
+		}
+	}
+	return unreferenced
+}
+
+// namespaceObjects holds the names of the objects a pod template can reference in a namespace
+type namespaceObjects struct {
+	configMaps      map[string]bool
+	secrets         map[string]bool
+	pvcs            map[string]bool
+	serviceAccounts map[string]bool
+}
+
+func retrieveNamespaceObjects(clientset kubernetes.Interface, namespace string) (*namespaceObjects, error) {
+	objects := &namespaceObjects{
+		configMaps:      make(map[string]bool),
+		secrets:         make(map[string]bool),
+		pvcs:            make(map[string]bool),
+		serviceAccounts: make(map[string]bool),
+	}
+
+	configMaps, err := clientset.CoreV1().ConfigMaps(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ConfigMaps: %v", err)
+	}
+	for _, configMap := range configMaps.Items {
+		objects.configMaps[configMap.Name] = true
+	}
+
+	secrets, err := clientset.CoreV1().Secrets(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Secrets: %v", err)
+	}
+	for _, secret := range secrets.Items {
+		objects.secrets[secret.Name] = true
+	}
+
+	pvcs, err := clientset.CoreV1().PersistentVolumeClaims(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PersistentVolumeClaims: %v", err)
+	}
+	for _, pvc := range pvcs.Items {
+		objects.pvcs[pvc.Name] = true
+	}
+
+	serviceAccounts, err := clientset.CoreV1().ServiceAccounts(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ServiceAccounts: %v", err)
+	}
+	for _, sa := range serviceAccounts.Items {
+		objects.serviceAccounts[sa.Name] = true
+	}
+
+	return objects, nil
+}
+
+// missingReferences returns the required ConfigMaps, Secrets, PVCs and ServiceAccount of a pod spec
+// that don't exist, which keeps its pods from ever starting. Optional references are ignored.
+func (o *namespaceObjects) missingReferences(spec corev1.PodSpec) []string {
+	var missing []string
+	addMissing := func(kind, name string, existing map[string]bool, optional *bool) {
+		reference := kind + " " + name
+		if name == "" || existing[name] || (optional != nil && *optional) || slices.Contains(missing, reference) {
+			return
+		}
+		missing = append(missing, reference)
+	}
+
+	if spec.ServiceAccountName != "" {
+		addMissing("ServiceAccount", spec.ServiceAccountName, o.serviceAccounts, nil)
+	}
+
+	for _, container := range slices.Concat(spec.InitContainers, spec.Containers) {
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				addMissing("ConfigMap", ref.Name, o.configMaps, ref.Optional)
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				addMissing("Secret", ref.Name, o.secrets, ref.Optional)
+			}
+		}
+		for _, envFrom := range container.EnvFrom {
+			if ref := envFrom.ConfigMapRef; ref != nil {
+				addMissing("ConfigMap", ref.Name, o.configMaps, ref.Optional)
+			}
+			if ref := envFrom.SecretRef; ref != nil {
+				addMissing("Secret", ref.Name, o.secrets, ref.Optional)
+			}
+		}
+	}
+
+	for _, volume := range spec.Volumes {
+		if volume.ConfigMap != nil {
+			addMissing("ConfigMap", volume.ConfigMap.Name, o.configMaps, volume.ConfigMap.Optional)
+		}
+		if volume.Secret != nil {
+			addMissing("Secret", volume.Secret.SecretName, o.secrets, volume.Secret.Optional)
+		}
+		if volume.PersistentVolumeClaim != nil {
+			addMissing("PersistentVolumeClaim", volume.PersistentVolumeClaim.ClaimName, o.pvcs, nil)
+		}
+		if volume.Projected == nil {
+			continue
+		}
+		for _, source := range volume.Projected.Sources {
+			if source.ConfigMap != nil {
+				addMissing("ConfigMap", source.ConfigMap.Name, o.configMaps, source.ConfigMap.Optional)
+			}
+			if source.Secret != nil {
+				addMissing("Secret", source.Secret.Name, o.secrets, source.Secret.Optional)
+			}
+		}
+	}
+
+	return missing
+}